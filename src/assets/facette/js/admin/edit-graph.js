@@ -842,7 +842,12 @@ app.controller('AdminEditGraphController', function($q, $rootScope, $routeParams
                 {name: 'Last', value: groupConsolidateLast},
                 {name: 'Max', value: groupConsolidateMax},
                 {name: 'Min', value: groupConsolidateMin},
-                {name: 'Sum', value: groupConsolidateSum}
+                {name: 'Sum', value: groupConsolidateSum},
+                {name: 'Median', value: groupConsolidateMedian},
+                {name: 'Percentile', value: groupConsolidatePercentile},
+                {name: 'Count', value: groupConsolidateCount},
+                {name: 'Standard deviation', value: groupConsolidateStddev},
+                {name: 'Envelope (min/max)', value: groupConsolidateEnvelope}
             ];
 
             $scope.graphTypes = [
@@ -33,6 +33,11 @@ var filterActions = [
     groupConsolidateMax = 4,
     groupConsolidateMin = 5,
     groupConsolidateSum = 6,
+    groupConsolidateMedian = 7,
+    groupConsolidatePercentile = 8,
+    groupConsolidateCount = 9,
+    groupConsolidateStddev = 10,
+    groupConsolidateEnvelope = 11,
 
     patternPrefixGlob = 'glob:',
     patternPrefixRegexp = 'regexp:',
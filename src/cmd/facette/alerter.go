@@ -0,0 +1,416 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"facette/backend"
+	"facette/notifier"
+	"facette/plot"
+	"facette/worker"
+
+	"github.com/facette/logger"
+	"github.com/facette/maputil"
+	"github.com/facette/sliceutil"
+	"github.com/facette/sqlstorage"
+)
+
+const (
+	alerterTickInterval = 10 * time.Second
+	alerterWindow       = 5 * time.Minute
+)
+
+var alertStateSeverities = map[string]int{
+	backend.AlertStateOK:       0,
+	backend.AlertStateUnknown:  1,
+	backend.AlertStateWarning:  2,
+	backend.AlertStateCritical: 3,
+}
+
+type alertStatus struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	State     string    `json:"state"`
+	Series    string    `json:"series,omitempty"`
+	Value     *float64  `json:"value"`
+	Since     time.Time `json:"since,omitempty"`
+	Evaluated time.Time `json:"evaluated"`
+
+	organization string
+	recorded     string
+}
+
+// apply updates the status given a newly evaluated rule state, returning the state last recorded into the rule
+// history and whether or not the new state has to be recorded. Warning and critical states are only raised once the
+// rule condition has held for the rule duration across evaluations, the status being pending meanwhile.
+func (s *alertStatus) apply(rule *backend.AlertRule, state string, now time.Time) (string, bool) {
+	previous := s.recorded
+
+	if rule.Duration > 0 && (state == backend.AlertStateWarning || state == backend.AlertStateCritical) &&
+		previous != backend.AlertStateWarning && previous != backend.AlertStateCritical {
+
+		if s.State != backend.AlertStatePending {
+			s.State = backend.AlertStatePending
+			s.Since = now
+		}
+
+		if now.Sub(s.Since) < time.Duration(rule.Duration)*time.Second {
+			return previous, false
+		}
+	}
+
+	if state != s.State {
+		s.State = state
+		s.Since = now
+	}
+
+	s.recorded = state
+
+	return previous, state != previous
+}
+
+type alerterWorker struct {
+	sync.Mutex
+	worker.CommonWorker
+
+	service  *Service
+	log      *logger.Logger
+	statuses map[string]*alertStatus
+	stopChan chan struct{}
+	wg       *sync.WaitGroup
+}
+
+func newAlerterWorker(s *Service) *alerterWorker {
+	return &alerterWorker{
+		service:  s,
+		log:      s.log.Context("alerter"),
+		statuses: make(map[string]*alertStatus),
+		stopChan: make(chan struct{}),
+		wg:       &sync.WaitGroup{},
+	}
+}
+
+func (w *alerterWorker) Run(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	w.wg.Add(1)
+	defer w.wg.Done()
+
+	w.log.Debug("worker started")
+
+	ticker := time.NewTicker(alerterTickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			w.evaluateAll(now.UTC())
+
+		case <-w.stopChan:
+			w.log.Debug("worker stopped")
+			return
+		}
+	}
+}
+
+func (w *alerterWorker) Shutdown() {
+	if w.Stopping() {
+		return
+	}
+
+	// Trigger alerter shutdown
+	close(w.stopChan)
+	w.wg.Wait()
+
+	w.CommonWorker.Shutdown()
+}
+
+// Statuses returns the current statuses of the alert rules belonging to an organization sorted by name.
+func (w *alerterWorker) Statuses(organization string) []alertStatus {
+	w.Lock()
+	defer w.Unlock()
+
+	result := []alertStatus{}
+	for _, status := range w.statuses {
+		if status.organization == organization {
+			result = append(result, *status)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result
+}
+
+func (w *alerterWorker) evaluateAll(now time.Time) {
+	rules := []*backend.AlertRule{}
+	if _, err := w.service.backend.Storage().List(&rules, map[string]interface{}{"enabled": true}, nil, 0,
+		0); err != nil {
+		w.log.Error("failed to list alert rules: %s", err)
+		return
+	}
+
+	w.Lock()
+
+	// Forget about statuses of deleted or disabled rules
+	ids := []string{}
+	for _, rule := range rules {
+		ids = append(ids, rule.ID)
+	}
+
+	for id := range w.statuses {
+		if !sliceutil.Has(ids, id) {
+			delete(w.statuses, id)
+		}
+	}
+
+	w.Unlock()
+
+	for _, rule := range rules {
+		interval := time.Duration(w.service.config.Alerting.Interval) * time.Second
+		if rule.Interval > 0 {
+			interval = time.Duration(rule.Interval) * time.Second
+		}
+
+		w.Lock()
+		status, ok := w.statuses[rule.ID]
+		w.Unlock()
+
+		if ok && now.Sub(status.Evaluated) < interval {
+			continue
+		}
+
+		w.evaluate(rule, now)
+	}
+}
+
+func (w *alerterWorker) evaluate(rule *backend.AlertRule, now time.Time) {
+	status, err := w.currentStatus(rule)
+	if err != nil {
+		w.log.Error("failed to retrieve %q alert rule state: %s", rule.Name, err)
+		return
+	}
+
+	state, series, value := w.evaluateRule(rule, now)
+
+	w.Lock()
+	status.Name = rule.Name
+	status.Evaluated = now
+	status.Series = series
+	status.Value = value
+
+	previous, changed := status.apply(rule, state, now)
+	w.Unlock()
+
+	if !changed {
+		return
+	}
+
+	// Record state change into rule history and send notifications
+	event := &backend.AlertEvent{
+		RuleID:   rule.ID,
+		Series:   series,
+		State:    state,
+		Previous: previous,
+		Value:    value,
+		Time:     now.Round(time.Second),
+	}
+
+	if err := w.service.backend.Storage().Save(event); err != nil {
+		w.log.Error("failed to save %q alert rule event: %s", rule.Name, err)
+	}
+
+	w.notify(rule, event)
+}
+
+func (w *alerterWorker) evaluateRule(rule *backend.AlertRule, now time.Time) (string, string, *float64) {
+	graph, err := w.ruleGraph(rule)
+	if err != nil {
+		w.log.Error("failed to prepare %q alert rule graph: %s", rule.Name, err)
+		return backend.AlertStateUnknown, "", nil
+	}
+
+	// Summarize series over a fixed window, the rule duration being handled across evaluations
+	req := &plot.Request{
+		StartTime:  now.Add(-alerterWindow),
+		EndTime:    now,
+		Sample:     plot.DefaultSample,
+		Downsample: plot.DownsampleBucket,
+		Graph:      graph,
+	}
+
+	series, _ := w.service.http.executeRequest(req)
+
+	// Keep worst state among graph series (no series at all being considered as missing data)
+	state := rule.Evaluate(math.NaN(), true)
+	name := ""
+	var value *float64
+
+	for i, s := range series {
+		v, ok := s.Summary[rule.Statistic]
+		missing := !ok || v.IsNaN()
+
+		current := rule.Evaluate(float64(v), missing)
+		if i == 0 || alertStateSeverities[current] > alertStateSeverities[state] {
+			state = current
+			name = s.Name
+			value = nil
+
+			if !missing {
+				f := float64(v)
+				value = &f
+			}
+		}
+	}
+
+	return state, name, value
+}
+
+func (w *alerterWorker) ruleGraph(rule *backend.AlertRule) (*backend.Graph, error) {
+	graph := w.service.backend.NewGraph()
+
+	if rule.GraphID != nil && *rule.GraphID != "" {
+		if err := w.service.backend.Storage().Get("id", *rule.GraphID, graph); err != nil {
+			return nil, err
+		} else if err := graph.Expand(nil); err != nil {
+			return nil, err
+		}
+
+		// Only evaluate actual series, thus skip derived ones
+		for _, group := range graph.Groups {
+			for _, series := range group.Series {
+				if series.Options != nil {
+					series.Options = series.Options.Clone()
+					delete(series.Options, "trend")
+					delete(series.Options, "anomaly")
+				}
+			}
+		}
+	} else {
+		graph.SetOrganization(rule.GetOrganization())
+		graph.Groups = backend.SeriesGroups{{
+			Name:     rule.Name,
+			Operator: plot.OperatorNone,
+			Series: []*backend.Series{{
+				Name:   rule.Name,
+				Origin: rule.Origin,
+				Source: rule.Source,
+				Metric: rule.Metric,
+			}},
+		}}
+	}
+
+	// Request summary statistic computation if not part of the default ones
+	graph.Options = maputil.Map{}
+
+	if sliceutil.Has(plot.SummaryStats, rule.Statistic) {
+		graph.Options["summary"] = []interface{}{rule.Statistic}
+	} else if strings.HasSuffix(rule.Statistic, "th") {
+		var pct float64
+		if _, err := fmt.Sscanf(rule.Statistic, "%gth", &pct); err != nil {
+			return nil, ErrInvalidStatistic
+		}
+
+		graph.Options["percentiles"] = []interface{}{pct}
+	}
+
+	return graph, nil
+}
+
+func (w *alerterWorker) currentStatus(rule *backend.AlertRule) (*alertStatus, error) {
+	w.Lock()
+	defer w.Unlock()
+
+	if status, ok := w.statuses[rule.ID]; ok {
+		return status, nil
+	}
+
+	// Restore last known state from rule history
+	status := &alertStatus{ID: rule.ID, Name: rule.Name, State: backend.AlertStateOK,
+		organization: rule.GetOrganization(), recorded: backend.AlertStateOK}
+
+	events := []*backend.AlertEvent{}
+	if _, err := w.service.backend.Storage().List(&events, map[string]interface{}{"rule": rule.ID},
+		[]string{"-time"}, 0, 1); err != nil && err != sqlstorage.ErrItemNotFound {
+		return nil, err
+	} else if len(events) > 0 {
+		status.State = events[0].State
+		status.recorded = events[0].State
+		status.Since = events[0].Time
+		status.Series = events[0].Series
+		status.Value = events[0].Value
+	}
+
+	w.statuses[rule.ID] = status
+
+	return status, nil
+}
+
+func (w *alerterWorker) notify(rule *backend.AlertRule, event *backend.AlertEvent) {
+	value := "n/a"
+	if event.Value != nil {
+		value = fmt.Sprintf("%g", *event.Value)
+	}
+
+	msg := fmt.Sprintf("alert %q changed from %s to %s (series: %q, %s: %s)", rule.Name, event.Previous,
+		event.State, event.Series, rule.Statistic, value)
+
+	switch event.State {
+	case backend.AlertStateCritical:
+		w.log.Error("%s", msg)
+
+	case backend.AlertStateWarning, backend.AlertStateUnknown:
+		w.log.Warning("%s", msg)
+
+	default:
+		w.log.Notice("%s", msg)
+	}
+
+	if len(rule.Channels) == 0 {
+		return
+	}
+
+	// Send notification through the rule channels
+	channels := []*backend.Channel{}
+	if err := w.service.backend.Storage().Get("id", []string(rule.Channels), &channels); err != nil &&
+		err != sqlstorage.ErrItemNotFound {
+		w.log.Error("failed to fetch %q alert rule channels: %s", rule.Name, err)
+		return
+	}
+
+	notification := &notifier.Notification{
+		Subject: fmt.Sprintf("%s is %s", rule.Name, event.State),
+		Message: msg,
+		Time:    event.Time,
+		Attributes: map[string]interface{}{
+			"id":        rule.ID,
+			"name":      rule.Name,
+			"state":     event.State,
+			"previous":  event.Previous,
+			"series":    event.Series,
+			"statistic": rule.Statistic,
+			"value":     value,
+		},
+	}
+
+	for _, channel := range channels {
+		if !channel.Enabled {
+			continue
+		}
+
+		n, err := w.service.NewNotifier(channel, w.log)
+		if err != nil {
+			w.log.Error("failed to initialize %q channel: %s", channel.Name, err)
+			continue
+		}
+
+		if err := n.Notify(notification); err != nil {
+			w.log.Error("failed to send %q alert rule notification to %q channel: %s", rule.Name, channel.Name,
+				err)
+		}
+	}
+}
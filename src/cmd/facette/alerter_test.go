@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"facette/backend"
+)
+
+func Test_AlertStatus_Apply(t *testing.T) {
+	rule := &backend.AlertRule{Duration: 60}
+	status := &alertStatus{State: backend.AlertStateOK, recorded: backend.AlertStateOK}
+
+	now := time.Now().UTC()
+
+	for _, test := range []struct {
+		offset   time.Duration
+		state    string
+		expected string
+		previous string
+		changed  bool
+	}{
+		{0, backend.AlertStateOK, backend.AlertStateOK, backend.AlertStateOK, false},
+		{10 * time.Second, backend.AlertStateWarning, backend.AlertStatePending, backend.AlertStateOK, false},
+		{40 * time.Second, backend.AlertStateCritical, backend.AlertStatePending, backend.AlertStateOK, false},
+		{50 * time.Second, backend.AlertStateOK, backend.AlertStateOK, backend.AlertStateOK, false},
+		{60 * time.Second, backend.AlertStateCritical, backend.AlertStatePending, backend.AlertStateOK, false},
+		{120 * time.Second, backend.AlertStateCritical, backend.AlertStateCritical, backend.AlertStateOK, true},
+		{130 * time.Second, backend.AlertStateWarning, backend.AlertStateWarning, backend.AlertStateCritical, true},
+		{140 * time.Second, backend.AlertStateOK, backend.AlertStateOK, backend.AlertStateWarning, true},
+		{150 * time.Second, backend.AlertStateUnknown, backend.AlertStateUnknown, backend.AlertStateOK, true},
+	} {
+		previous, changed := status.apply(rule, test.state, now.Add(test.offset))
+		if status.State != test.expected || previous != test.previous || changed != test.changed {
+			t.Logf("\nExpected %q (previous %q, changed %v)\nbut got  %q (previous %q, changed %v) at %s",
+				test.expected, test.previous, test.changed, status.State, previous, changed, test.offset)
+			t.Fail()
+		}
+	}
+
+	// Ensure states are raised immediately if the rule has no duration
+	rule.Duration = 0
+	status = &alertStatus{State: backend.AlertStateOK, recorded: backend.AlertStateOK}
+
+	if previous, changed := status.apply(rule, backend.AlertStateCritical, now); status.State !=
+		backend.AlertStateCritical || previous != backend.AlertStateOK || !changed {
+		t.Logf("\nExpected %q\nbut got  %q (previous %q, changed %v)", backend.AlertStateCritical, status.State,
+			previous, changed)
+		t.Fail()
+	}
+}
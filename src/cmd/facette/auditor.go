@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"facette/backend"
+
+	"github.com/facette/logger"
+)
+
+// auditor records the actions performed through the API into the back-end audit log, and into a JSON file if
+// configured (one entry per line).
+type auditor struct {
+	service *Service
+	log     *logger.Logger
+	file    *os.File
+	lock    sync.Mutex
+}
+
+func newAuditor(s *Service) (*auditor, error) {
+	a := &auditor{
+		service: s,
+		log:     s.log.Context("audit"),
+	}
+
+	if s.config.Audit.Path != "" {
+		f, err := os.OpenFile(s.config.Audit.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
+		if err != nil {
+			return nil, err
+		}
+
+		a.file = f
+	}
+
+	return a, nil
+}
+
+// Record stores an audit log entry. Failing to do so is logged, as the audited action has already been performed.
+func (a *auditor) Record(entry *backend.AuditEntry) {
+	if err := a.service.backend.Storage().Save(entry); err != nil {
+		a.log.Error("failed to save audit entry: %s", err)
+	}
+
+	if a.file == nil {
+		return
+	}
+
+	data, err := json.Marshal(struct {
+		*backend.AuditEntry
+		Organization *string `json:"organization,omitempty"`
+	}{entry, entry.OrganizationID})
+	if err != nil {
+		a.log.Error("failed to marshal audit entry: %s", err)
+		return
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if _, err := a.file.Write(append(data, '\n')); err != nil {
+		a.log.Error("failed to write audit entry: %s", err)
+	}
+}
+
+// Close closes the audit log file if any.
+func (a *auditor) Close() error {
+	if a.file == nil {
+		return nil
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return a.file.Close()
+}
@@ -18,6 +18,18 @@ const (
 	defaultFrontendEnabled   = true
 	defaultFrontendAssetsDir = "assets"
 	defaultHideBuildDetails  = false
+	defaultAlertingEnabled   = true
+	defaultAlertingInterval  = 60
+	defaultReportingEnabled  = true
+	defaultCommandChannels   = false
+	defaultAuthEnabled       = false
+	defaultAuthSessionTTL    = 86400
+	defaultTrashEnabled      = true
+	defaultTrashRetention    = 2592000
+	defaultAuditEnabled      = true
+	defaultAuditPath         = ""
+	defaultTLSMinVersion     = "1.2"
+	defaultTLSHTTP2          = true
 )
 
 type frontendConfig struct {
@@ -25,19 +37,70 @@ type frontendConfig struct {
 	AssetsDir string `yaml:"assets_dir"`
 }
 
+type alertingConfig struct {
+	Enabled  bool `yaml:"enabled"`
+	Interval int  `yaml:"interval"`
+}
+
+type reportingConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Directory string `yaml:"directory"`
+}
+
+type channelsConfig struct {
+	Command commandChannelsConfig `yaml:"command"`
+}
+
+type commandChannelsConfig struct {
+	Enabled         bool     `yaml:"enabled"`
+	AllowedCommands []string `yaml:"allowed_commands"`
+}
+
+type authConfig struct {
+	Enabled      bool           `yaml:"enabled"`
+	SessionTTL   int            `yaml:"session_ttl"`
+	SecureCookie bool           `yaml:"secure_cookie"`
+	Backends     []*maputil.Map `yaml:"backends"`
+}
+
+type trashConfig struct {
+	Enabled   bool `yaml:"enabled"`
+	Retention int  `yaml:"retention"`
+}
+
+type auditConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"`
+}
+
+type tlsConfig struct {
+	CertFile     string `yaml:"cert_file"`
+	KeyFile      string `yaml:"key_file"`
+	MinVersion   string `yaml:"min_version"`
+	ClientCAFile string `yaml:"client_ca_file"`
+	HTTP2        bool   `yaml:"http2"`
+}
+
 type config struct {
-	Listen           string         `yaml:"listen"`
-	SocketMode       string         `yaml:"socket_mode"`
-	SocketUser       string         `yaml:"socket_user"`
-	SocketGroup      string         `yaml:"socket_group"`
-	GracefulTimeout  int            `yaml:"graceful_timeout"`
-	RootPath         string         `yaml:"root_path"`
-	LogPath          string         `yaml:"log_path"`
-	LogLevel         string         `yaml:"log_level"`
-	Frontend         frontendConfig `yaml:"frontend"`
-	Backend          *maputil.Map   `yaml:"backend"`
-	HideBuildDetails bool           `yaml:"hide_build_details"`
-	ReadOnly         bool           `yaml:"read_only"`
+	Listen           string          `yaml:"listen"`
+	SocketMode       string          `yaml:"socket_mode"`
+	SocketUser       string          `yaml:"socket_user"`
+	SocketGroup      string          `yaml:"socket_group"`
+	TLS              tlsConfig       `yaml:"tls"`
+	GracefulTimeout  int             `yaml:"graceful_timeout"`
+	RootPath         string          `yaml:"root_path"`
+	LogPath          string          `yaml:"log_path"`
+	LogLevel         string          `yaml:"log_level"`
+	Frontend         frontendConfig  `yaml:"frontend"`
+	Backend          *maputil.Map    `yaml:"backend"`
+	Alerting         alertingConfig  `yaml:"alerting"`
+	Reporting        reportingConfig `yaml:"reporting"`
+	Channels         channelsConfig  `yaml:"channels"`
+	Auth             authConfig      `yaml:"auth"`
+	Trash            trashConfig     `yaml:"trash"`
+	Audit            auditConfig     `yaml:"audit"`
+	HideBuildDetails bool            `yaml:"hide_build_details"`
+	ReadOnly         bool            `yaml:"read_only"`
 }
 
 func initConfig(path string) (*config, error) {
@@ -48,10 +111,38 @@ func initConfig(path string) (*config, error) {
 			RootPath:        defaultRootPath,
 			LogPath:         defaultLogPath,
 			LogLevel:        defaultLogLevel,
+			TLS: tlsConfig{
+				MinVersion: defaultTLSMinVersion,
+				HTTP2:      defaultTLSHTTP2,
+			},
 			Frontend: frontendConfig{
 				Enabled:   defaultFrontendEnabled,
 				AssetsDir: defaultFrontendAssetsDir,
 			},
+			Alerting: alertingConfig{
+				Enabled:  defaultAlertingEnabled,
+				Interval: defaultAlertingInterval,
+			},
+			Reporting: reportingConfig{
+				Enabled: defaultReportingEnabled,
+			},
+			Channels: channelsConfig{
+				Command: commandChannelsConfig{
+					Enabled: defaultCommandChannels,
+				},
+			},
+			Auth: authConfig{
+				Enabled:    defaultAuthEnabled,
+				SessionTTL: defaultAuthSessionTTL,
+			},
+			Trash: trashConfig{
+				Enabled:   defaultTrashEnabled,
+				Retention: defaultTrashRetention,
+			},
+			Audit: auditConfig{
+				Enabled: defaultAuditEnabled,
+				Path:    defaultAuditPath,
+			},
 			HideBuildDetails: defaultHideBuildDetails,
 		}
 	)
@@ -3,16 +3,38 @@ package main
 import "errors"
 
 var (
+	// ErrAuthDisabled represents a disabled authentication error.
+	ErrAuthDisabled = errors.New("authentication disabled")
+	// ErrCommandChannelsDisabled represents a disabled command channels error.
+	ErrCommandChannelsDisabled = errors.New("command channels disabled")
+	// ErrCommandNotAllowed represents a command channel not allowed command error.
+	ErrCommandNotAllowed = errors.New("command not allowed")
+	// ErrForbidden represents a forbidden access error.
+	ErrForbidden = errors.New("access forbidden")
+	// ErrInvalidCredentials represents an invalid credentials error.
+	ErrInvalidCredentials = errors.New("invalid credentials")
 	// ErrInvalidJSON represents an invalid JSON data error.
 	ErrInvalidJSON = errors.New("invalid JSON data")
 	// ErrInvalidParameter represents an invalid request parameter error.
 	ErrInvalidParameter = errors.New("invalid request parameter")
+	// ErrInvalidReportDirectory represents an invalid report directory error.
+	ErrInvalidReportDirectory = errors.New("invalid report directory")
+	// ErrInvalidStatistic represents an invalid summary statistic error.
+	ErrInvalidStatistic = errors.New("invalid summary statistic")
 	// ErrInvalidTimerange represents an invalid time range error.
 	ErrInvalidTimerange = errors.New("invalid time range")
+	// ErrPreconditionFailed represents a failed request precondition error.
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrReportDirectoryDisabled represents a disabled report directories error.
+	ErrReportDirectoryDisabled = errors.New("report directories disabled")
 	// ErrReadOnly represents a read-only instance error.
 	ErrReadOnly = errors.New("read-only instance")
+	// ErrUnauthorized represents an authentication required error.
+	ErrUnauthorized = errors.New("authentication required")
 	// ErrUnhandledError represents a service unhandled error.
 	ErrUnhandledError = errors.New("an unhandled error has occurred")
 	// ErrUnknownEndpoint represents an unknown endpoint error.
 	ErrUnknownEndpoint = errors.New("unknown endpoint")
+	// ErrUnknownOrganization represents an unknown organization error.
+	ErrUnknownOrganization = errors.New("unknown organization")
 )
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"net/http"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"facette/auth"
 	"facette/worker"
 
 	"github.com/facette/httproute"
@@ -24,11 +26,13 @@ type httpWorker struct {
 	sync.Mutex
 	worker.CommonWorker
 
-	service *Service
-	log     *logger.Logger
-	router  *httproute.Router
-	server  *graceful.Server
-	prefix  string
+	service        *Service
+	log            *logger.Logger
+	router         *httproute.Router
+	server         *graceful.Server
+	tlsConfig      *tls.Config
+	prefix         string
+	authenticators []auth.Authenticator
 }
 
 func newHTTPWorker(s *Service) *httpWorker {
@@ -41,6 +45,33 @@ func newHTTPWorker(s *Service) *httpWorker {
 
 	// Initialize HTTP router
 	w.router.Use(w.httpHandleLogger)
+	w.router.Use(w.httpHandleOrganization)
+	w.router.Use(w.httpHandleAuth)
+
+	w.router.Endpoint(w.prefix + "/alerts/").
+		Get(w.httpHandleAlertList)
+	w.router.Endpoint(w.prefix + "/alerts/:id/history").
+		Get(w.httpHandleAlertHistory)
+
+	w.router.Endpoint(w.prefix + "/annotations").
+		Post(w.httpHandleAnnotationPush)
+
+	w.router.Endpoint(w.prefix + "/audit/").
+		Get(w.httpHandleAuditList)
+
+	w.router.Endpoint(w.prefix + "/auth/login").
+		Post(w.httpHandleAuthLogin)
+	w.router.Endpoint(w.prefix + "/auth/logout").
+		Post(w.httpHandleAuthLogout)
+	w.router.Endpoint(w.prefix + "/auth/password").
+		Put(w.httpHandleAuthPassword)
+	w.router.Endpoint(w.prefix + "/auth/user").
+		Get(w.httpHandleAuthUser)
+	w.router.Endpoint(w.prefix + "/auth/tokens/").
+		Get(w.httpHandleAuthTokenList).
+		Post(w.httpHandleAuthTokenCreate)
+	w.router.Endpoint(w.prefix + "/auth/tokens/:id").
+		Delete(w.httpHandleAuthTokenDelete)
 
 	w.router.Endpoint(w.prefix + "/bulk").
 		Post(w.httpHandleBulk)
@@ -63,19 +94,49 @@ func newHTTPWorker(s *Service) *httpWorker {
 		Post(w.httpHandleLibrarySearch)
 	w.router.Endpoint(w.prefix + "/library/collections/tree").
 		Get(w.httpHandleLibraryCollectionTree)
+	w.router.Endpoint(w.prefix + "/library/channels/:id/test").
+		Post(w.httpHandleChannelTest)
+	w.router.Endpoint(w.prefix + "/library/reports/:id/run").
+		Post(w.httpHandleReportRun)
+	w.router.Endpoint(w.prefix + "/library/:type/:id/revisions/").
+		Get(w.httpHandleLibraryType(w.httpHandleRevisionList))
+	w.router.Endpoint(w.prefix + "/library/:type/:id/revisions/:revision").
+		Get(w.httpHandleLibraryType(w.httpHandleRevisionGet))
+	w.router.Endpoint(w.prefix + "/library/:type/:id/revisions/:revision/diff").
+		Get(w.httpHandleLibraryType(w.httpHandleRevisionDiff))
+	w.router.Endpoint(w.prefix + "/library/:type/:id/revisions/:revision/restore").
+		Post(w.httpHandleLibraryType(w.httpHandleRevisionRestore))
 	w.router.Endpoint(w.prefix + "/library/:type/").
+		Delete(w.httpHandleLibraryType(w.httpHandleBackendDeleteAll)).
+		Get(w.httpHandleLibraryType(w.httpHandleBackendList)).
+		Post(w.httpHandleLibraryType(w.httpHandleBackendCreate))
+	w.router.Endpoint(w.prefix + "/library/:type/:id").
+		Delete(w.httpHandleLibraryType(w.httpHandleBackendDelete)).
+		Get(w.httpHandleLibraryType(w.httpHandleBackendGet)).
+		Patch(w.httpHandleLibraryType(w.httpHandleBackendUpdate)).
+		Put(w.httpHandleLibraryType(w.httpHandleBackendUpdate))
+
+	organizationCtx := context.WithValue(context.Background(), "type", "organizations")
+
+	w.router.EndpointWithContext(w.prefix+"/organizations/", organizationCtx).
 		Delete(w.httpHandleBackendDeleteAll).
 		Get(w.httpHandleBackendList).
 		Post(w.httpHandleBackendCreate)
-	w.router.Endpoint(w.prefix + "/library/:type/:id").
+	w.router.EndpointWithContext(w.prefix+"/organizations/:id", organizationCtx).
 		Delete(w.httpHandleBackendDelete).
 		Get(w.httpHandleBackendGet).
 		Patch(w.httpHandleBackendUpdate).
 		Put(w.httpHandleBackendUpdate)
 
 	w.router.Endpoint(w.prefix + "/plots").
+		Get(w.httpHandlePlotsGet).
 		Post(w.httpHandlePlots)
 
+	w.router.Endpoint(w.prefix + "/render/graphs").
+		Post(w.httpHandleRender)
+	w.router.Endpoint(w.prefix + "/render/graphs/:id").
+		Get(w.httpHandleRenderGraph)
+
 	providerCtx := context.WithValue(context.Background(), "type", "providers")
 
 	w.router.EndpointWithContext(w.prefix+"/providers/", providerCtx).
@@ -90,6 +151,44 @@ func newHTTPWorker(s *Service) *httpWorker {
 
 	w.router.Endpoint(w.prefix + "/providers/:id/refresh").
 		Post(w.httpHandleProviderRefresh)
+	w.router.EndpointWithContext(w.prefix+"/providers/:id/revisions/", providerCtx).
+		Get(w.httpHandleRevisionList)
+	w.router.EndpointWithContext(w.prefix+"/providers/:id/revisions/:revision", providerCtx).
+		Get(w.httpHandleRevisionGet)
+	w.router.EndpointWithContext(w.prefix+"/providers/:id/revisions/:revision/diff", providerCtx).
+		Get(w.httpHandleRevisionDiff)
+	w.router.EndpointWithContext(w.prefix+"/providers/:id/revisions/:revision/restore", providerCtx).
+		Post(w.httpHandleRevisionRestore)
+
+	w.router.Endpoint(w.prefix + "/snapshots/").
+		Get(w.httpHandleSnapshotList).
+		Post(w.httpHandleSnapshotCreate)
+	w.router.Endpoint(w.prefix + "/snapshots/public/:secret").
+		Get(w.httpHandleSnapshotPublic)
+	w.router.Endpoint(w.prefix + "/snapshots/:id").
+		Delete(w.httpHandleSnapshotDelete).
+		Get(w.httpHandleSnapshotGet)
+
+	userCtx := context.WithValue(context.Background(), "type", "users")
+
+	w.router.EndpointWithContext(w.prefix+"/users/", userCtx).
+		Delete(w.httpHandleBackendDeleteAll).
+		Get(w.httpHandleBackendList).
+		Post(w.httpHandleBackendCreate)
+	w.router.EndpointWithContext(w.prefix+"/users/:id", userCtx).
+		Delete(w.httpHandleBackendDelete).
+		Get(w.httpHandleBackendGet).
+		Patch(w.httpHandleBackendUpdate).
+		Put(w.httpHandleBackendUpdate)
+
+	w.router.Endpoint(w.prefix + "/trash/").
+		Delete(w.httpHandleTrashPurgeAll).
+		Get(w.httpHandleTrashList)
+	w.router.Endpoint(w.prefix + "/trash/:id").
+		Delete(w.httpHandleTrashPurge).
+		Get(w.httpHandleTrashGet)
+	w.router.Endpoint(w.prefix + "/trash/:id/restore").
+		Post(w.httpHandleTrashRestore)
 
 	w.router.Endpoint(w.prefix + "/").
 		Get(w.httpHandleInfo)
@@ -103,13 +202,27 @@ func newHTTPWorker(s *Service) *httpWorker {
 	return w
 }
 
+func (w *httpWorker) Init() error {
+	var err error
+
+	if w.tlsConfig, err = w.httpInitTLS(); err != nil {
+		return err
+	}
+
+	return w.httpInitAuth()
+}
+
 func (w *httpWorker) Run(wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	w.log.Debug("worker started")
 
 	// Start router
-	w.log.Info("listening on %q", w.service.config.Listen)
+	if w.tlsConfig != nil {
+		w.log.Info("listening on %q using TLS", w.service.config.Listen)
+	} else {
+		w.log.Info("listening on %q", w.service.config.Listen)
+	}
 
 	netProto := "tcp"
 	netAddr := w.service.config.Listen
@@ -168,11 +281,17 @@ func (w *httpWorker) Run(wg *sync.WaitGroup) {
 		}
 	}
 
+	// Serve requests over TLS if configured
+	if w.tlsConfig != nil {
+		listener = tls.NewListener(listener, w.tlsConfig)
+	}
+
 	w.Lock()
 	w.server = &graceful.Server{
 		Server: &http.Server{
-			Addr:    netAddr,
-			Handler: w.router,
+			Addr:      netAddr,
+			Handler:   w.router,
+			TLSConfig: w.tlsConfig,
 		},
 		NoSignalHandling: true,
 		Timeout:          time.Duration(w.service.config.GracefulTimeout) * time.Second,
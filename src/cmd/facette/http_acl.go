@@ -0,0 +1,183 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"facette/backend"
+
+	"github.com/facette/httputil"
+	"github.com/facette/sliceutil"
+	"github.com/facette/sqlstorage"
+)
+
+var (
+	// aclReadOnlyEndpoints represents the non-GET endpoints not modifying any data, thus granted to viewers.
+	aclReadOnlyEndpoints = []string{
+		"/bulk",
+		"/expand",
+		"/library/parse",
+		"/library/search",
+		"/plots",
+		"/render/graphs",
+	}
+
+	aclDeleteAllRegexp = regexp.MustCompile(`^/(?:library/[^/]+|trash)/?$`)
+)
+
+// httpAuthorize returns whether or not a user role allows to perform a request. Access to items owned by users is
+// further checked in the back-end handlers.
+func (w *httpWorker) httpAuthorize(user *backend.User, r *http.Request) bool {
+	path := strings.TrimPrefix(r.URL.Path, w.prefix)
+	typ := httpRequestType(path)
+
+	switch {
+	case typ == "organizations":
+		// Organizations management is restricted to administrators not belonging to any organization
+		return user.Role == backend.RoleAdmin && user.OrganizationID == nil
+
+	case user.Role == backend.RoleAdmin:
+		return true
+
+	case strings.HasPrefix(path, "/audit"),
+		typ == "providers",
+		typ == "users" && r.Method != "GET",
+		r.Method == "DELETE" && aclDeleteAllRegexp.MatchString(path):
+		// Audit log, providers, users and items bulk deletion are restricted to administrators
+		return false
+
+	case r.Method == "GET" || r.Method == "HEAD",
+		strings.HasPrefix(path, "/auth/"),
+		sliceutil.Has(aclReadOnlyEndpoints, strings.TrimRight(path, "/")):
+		return true
+	}
+
+	return user.Role == backend.RoleEditor
+}
+
+// httpRequestType returns the back-end item type a request path applies to, either through its dedicated endpoints
+// or the library ones, or an empty string if none.
+func httpRequestType(path string) string {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+	if parts[0] == "library" && len(parts) > 1 {
+		parts = parts[1:]
+	}
+
+	if parts[0] == "organizations" || sliceutil.Has(backendTypes, parts[0]) {
+		return parts[0]
+	}
+
+	return ""
+}
+
+// httpCheckAccess returns whether or not the authenticated user is given an access to a back-end item, writing an
+// error response otherwise. Items the user isn't allowed to see are reported as not found.
+func (w *httpWorker) httpCheckAccess(rw http.ResponseWriter, item interface{}, access string) bool {
+	v, ok := item.(backend.ACLItem)
+	if !ok {
+		return true
+	}
+
+	acl, user := v.GetACL(), httpAuthUser(rw)
+
+	if acl.Allowed(user, access) {
+		return true
+	} else if access == backend.AccessWrite && acl.Allowed(user, backend.AccessRead) {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrForbidden), http.StatusForbidden)
+	} else {
+		httputil.WriteJSON(rw, httpBuildMessage(sqlstorage.ErrItemNotFound), http.StatusNotFound)
+	}
+
+	return false
+}
+
+// httpApplyACL sets the access control list of an item being saved by the authenticated user, given its original
+// list (nil upon creation). Only administrators can change an item owner, and only its owner can change who it is
+// granted to.
+func (w *httpWorker) httpApplyACL(rw http.ResponseWriter, item interface{}, orig *backend.ACL) {
+	v, ok := item.(backend.ACLItem)
+	if !ok {
+		return
+	}
+
+	acl, user := v.GetACL(), httpAuthUser(rw)
+
+	if user == nil {
+		return
+	} else if orig == nil {
+		if user.Role != backend.RoleAdmin || acl.OwnerID == nil || *acl.OwnerID == "" {
+			acl.OwnerID = &user.ID
+		}
+		return
+	} else if user.Role == backend.RoleAdmin {
+		return
+	}
+
+	if !orig.IsOwner(user) {
+		acl.Private = orig.Private
+		acl.Grants = orig.Grants
+	}
+
+	acl.OwnerID = orig.OwnerID
+}
+
+// httpHiddenItems returns the identifiers of the private items the authenticated user isn't allowed to see.
+func (w *httpWorker) httpHiddenItems(rw http.ResponseWriter) (map[string]bool, error) {
+	return w.hiddenItems(httpAuthUser(rw))
+}
+
+// hiddenItems returns the identifiers of the private items a user isn't allowed to see.
+func (w *httpWorker) hiddenItems(user *backend.User) (map[string]bool, error) {
+	if user == nil || user.Role == backend.RoleAdmin {
+		return nil, nil
+	}
+
+	graphs := []*backend.Graph{}
+	if _, err := w.service.backend.Storage().List(&graphs, map[string]interface{}{"private": true}, nil, 0,
+		0); err != nil {
+		return nil, err
+	}
+
+	collections := []*backend.Collection{}
+	if _, err := w.service.backend.Storage().List(&collections, map[string]interface{}{"private": true}, nil, 0,
+		0); err != nil {
+		return nil, err
+	}
+
+	hidden := map[string]bool{}
+
+	for _, graph := range graphs {
+		if !graph.Allowed(user, backend.AccessRead) {
+			hidden[graph.ID] = true
+		}
+	}
+
+	for _, collection := range collections {
+		if !collection.Allowed(user, backend.AccessRead) {
+			hidden[collection.ID] = true
+		}
+	}
+
+	return hidden, nil
+}
+
+// httpCheckGraphAccess returns whether or not the authenticated user is allowed to see a graph, writing an error
+// response otherwise.
+func (w *httpWorker) httpCheckGraphAccess(rw http.ResponseWriter, id string) bool {
+	if id == "" {
+		return true
+	}
+
+	hidden, err := w.httpHiddenItems(rw)
+	if err != nil {
+		w.log.Error("failed to fetch items: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return false
+	} else if hidden[id] {
+		httputil.WriteJSON(rw, httpBuildMessage(sqlstorage.ErrItemNotFound), http.StatusNotFound)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"facette/backend"
+
+	"github.com/facette/httproute"
+)
+
+func Test_HTTP_Authorize(t *testing.T) {
+	org := "00000000-0000-0000-0000-000000000001"
+
+	admin := &backend.User{Name: "admin", Role: backend.RoleAdmin}
+	orgAdmin := &backend.User{Name: "admin2", Role: backend.RoleAdmin, OrganizationID: &org}
+	editor := &backend.User{Name: "editor", Role: backend.RoleEditor}
+	viewer := &backend.User{Name: "viewer", Role: backend.RoleViewer}
+
+	w := &httpWorker{prefix: apiPrefix}
+
+	for _, test := range []struct {
+		user     *backend.User
+		method   string
+		path     string
+		expected bool
+	}{
+		// Library items
+		{viewer, "GET", "/library/graphs/", true},
+		{viewer, "POST", "/library/graphs/", false},
+		{editor, "POST", "/library/graphs/", true},
+		{editor, "PUT", "/library/graphs/1", true},
+		{editor, "DELETE", "/library/graphs/1", true},
+		{editor, "DELETE", "/library/graphs/", false},
+		{admin, "DELETE", "/library/graphs/", true},
+		{viewer, "GET", "/library/graphs/1/revisions/", true},
+		{viewer, "POST", "/library/graphs/1/revisions/2/restore", false},
+		{editor, "POST", "/library/graphs/1/revisions/2/restore", true},
+		{viewer, "POST", "/library/search", true},
+		{viewer, "POST", "/plots", true},
+
+		// Users
+		{viewer, "GET", "/users/", true},
+		{editor, "POST", "/users/", false},
+		{editor, "POST", "/library/users/", false},
+		{editor, "PATCH", "/library/users/1", false},
+		{admin, "POST", "/users/", true},
+
+		// Providers
+		{editor, "GET", "/providers/", false},
+		{editor, "GET", "/library/providers/", false},
+		{editor, "GET", "/library/providers/1/revisions/", false},
+		{editor, "POST", "/providers/1/refresh", false},
+		{admin, "GET", "/providers/", true},
+
+		// Organizations
+		{editor, "GET", "/organizations/", false},
+		{editor, "POST", "/library/organizations/", false},
+		{orgAdmin, "POST", "/organizations/", false},
+		{orgAdmin, "POST", "/library/organizations/", false},
+		{orgAdmin, "POST", "/library/graphs/", true},
+		{admin, "POST", "/organizations/", true},
+
+		// Audit log
+		{editor, "GET", "/audit/", false},
+		{admin, "GET", "/audit/", true},
+
+		// Authentication
+		{viewer, "POST", "/auth/tokens/", true},
+		{viewer, "PUT", "/auth/password", true},
+	} {
+		r := httptest.NewRequest(test.method, apiPrefix+test.path, nil)
+
+		if result := w.httpAuthorize(test.user, r); result != test.expected {
+			t.Logf("\nExpected %v\nbut got  %v (%s %s as %s)", test.expected, result, test.method, test.path,
+				test.user.Name)
+			t.Fail()
+		}
+	}
+}
+
+func Test_HTTP_RequestType(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/library/graphs/":                 "graphs",
+		"/library/graphs/1/revisions/":     "graphs",
+		"/library/collections/tree":        "collections",
+		"/library/users/1":                 "users",
+		"/library/organizations":           "organizations",
+		"/library/parse":                   "",
+		"/library/":                        "",
+		"/providers/1/refresh":             "providers",
+		"/providers/1/revisions/2/restore": "providers",
+		"/users/":                          "users",
+		"/organizations/1":                 "organizations",
+		"/plots":                           "",
+		"/":                                "",
+	} {
+		if result := httpRequestType(path); result != expected {
+			t.Logf("\nExpected %q\nbut got  %q (%s)", expected, result, path)
+			t.Fail()
+		}
+	}
+}
+
+func Test_HTTP_HandleLibraryType(t *testing.T) {
+	w := &httpWorker{}
+
+	// Register a no-op middleware, as the router handlers chain is only initialized upon registration
+	router := httproute.NewRouter()
+	router.Use(func(h http.Handler) http.Handler { return h })
+	router.Endpoint("/library/:type/").
+		Get(w.httpHandleLibraryType(func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusNoContent)
+		}))
+
+	for path, expected := range map[string]int{
+		"/library/graphs/":        http.StatusNoContent,
+		"/library/channels/":      http.StatusNoContent,
+		"/library/users/":         http.StatusNotFound,
+		"/library/providers/":     http.StatusNotFound,
+		"/library/organizations/": http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+
+		if rec.Code != expected {
+			t.Logf("\nExpected %d\nbut got  %d (%s)", expected, rec.Code, path)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"facette/backend"
+
+	"github.com/facette/httproute"
+	"github.com/facette/httputil"
+	"github.com/facette/sqlstorage"
+)
+
+func (w *httpWorker) httpHandleAlertList(rw http.ResponseWriter, r *http.Request) {
+	result := []alertStatus{}
+	if w.service.alerter != nil {
+		result = w.service.alerter.Statuses(httpOrganizationID(rw))
+	}
+
+	httputil.WriteJSON(rw, result, http.StatusOK)
+}
+
+func (w *httpWorker) httpHandleAlertHistory(rw http.ResponseWriter, r *http.Request) {
+	id := httproute.ContextParam(r, "id").(string)
+
+	// Check for alert rule existence
+	rule := w.service.backend.NewAlertRule()
+	if err := w.service.backend.Storage().Get("id", id, rule); err == sqlstorage.ErrItemNotFound {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		w.log.Error("failed to fetch item: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	} else if !w.httpCheckOrganization(rw, rule) {
+		return
+	}
+
+	offset, err := httpGetIntParam(r, "offset")
+	if err != nil || offset < 0 {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return
+	}
+
+	limit, err := httpGetIntParam(r, "limit")
+	if err != nil || limit < 0 {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return
+	}
+
+	// Request rule state changes history from back-end
+	events := []*backend.AlertEvent{}
+
+	count, err := w.service.backend.Storage().List(&events, map[string]interface{}{"rule": id},
+		[]string{"-time"}, offset, limit)
+	if err != nil {
+		w.log.Error("failed to fetch items: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("X-Total-Records", fmt.Sprintf("%d", count))
+	httputil.WriteJSON(rw, events, http.StatusOK)
+}
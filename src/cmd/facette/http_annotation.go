@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+
+	"facette/backend"
+
+	"github.com/facette/httputil"
+	"github.com/facette/sqlstorage"
+)
+
+func (w *httpWorker) httpHandleAnnotationPush(rw http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	if w.service.config.ReadOnly {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrReadOnly), http.StatusForbidden)
+		return
+	}
+
+	// Get annotation from received data (start time defaulting to current time)
+	annotation := w.service.backend.NewAnnotation()
+	if err := httputil.BindJSON(r, annotation); err == httputil.ErrInvalidContentType {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusUnsupportedMediaType)
+		return
+	} else if err != nil {
+		w.log.Error("unable to unmarshal JSON data: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidJSON), http.StatusBadRequest)
+		return
+	}
+
+	annotation.ID = ""
+	w.httpApplyOrganization(rw, annotation)
+
+	if !w.httpCheckReferences(rw, annotation) {
+		return
+	}
+
+	// Insert annotation into back-end
+	if err := w.service.backend.Storage().Save(annotation); err != nil {
+		switch err {
+		case sqlstorage.ErrItemConflict:
+			httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusConflict)
+
+		case backend.ErrInvalidName, backend.ErrInvalidTimerange, sqlstorage.ErrMissingField,
+			sqlstorage.ErrUnknownReference:
+			httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadRequest)
+
+		default:
+			w.log.Error("failed to insert item: %s", err)
+			httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	w.log.Debug("inserted %q annotation into backend", annotation.ID)
+
+	w.httpAudit(rw, r, backend.AuditActionCreate, "annotations", annotation, nil)
+
+	http.Redirect(rw, r, w.prefix+"/library/annotations/"+annotation.ID, http.StatusCreated)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"facette/backend"
+
+	"github.com/facette/httputil"
+)
+
+func (w *httpWorker) httpHandleAuditList(rw http.ResponseWriter, r *http.Request) {
+	filters := map[string]interface{}{}
+	w.httpApplyOrganizationFilter(rw, &backend.AuditEntry{}, filters)
+
+	for param, column := range map[string]string{
+		"action": "action",
+		"type":   "item_type",
+		"item":   "item",
+		"user":   "user",
+	} {
+		if v := r.URL.Query().Get(param); v != "" {
+			filters[column] = v
+		}
+	}
+
+	offset, err := httpGetIntParam(r, "offset")
+	if err != nil || offset < 0 {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return
+	}
+
+	limit, err := httpGetIntParam(r, "limit")
+	if err != nil || limit < 0 {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return
+	}
+
+	// Request audit log entries from back-end, most recent first
+	entries := []*backend.AuditEntry{}
+
+	count, err := w.service.backend.Storage().List(&entries, filters, []string{"-date"}, offset, limit)
+	if err != nil {
+		w.log.Error("failed to fetch items: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("X-Total-Records", fmt.Sprintf("%d", count))
+	httputil.WriteJSON(rw, entries, http.StatusOK)
+}
+
+// httpAudit records an action performed by the authenticated user on a back-end item into the audit log, given the
+// item previous state upon update (nil otherwise).
+func (w *httpWorker) httpAudit(rw http.ResponseWriter, r *http.Request, action, typ string, item,
+	prev interface{}) {
+
+	if w.service.auditor == nil {
+		return
+	}
+
+	// Mask secret settings, as audit entries are readable by administrators and possibly written to external sinks
+	entry, err := backend.NewAuditEntry(action, typ, httpMaskItem(item), httpMaskItem(prev))
+	if err != nil {
+		w.log.Error("failed to create audit entry: %s", err)
+		return
+	}
+
+	if user := httpAuthUser(rw); user != nil {
+		entry.User = &user.Name
+	}
+
+	addr := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	entry.Address = &addr
+
+	entry.SetOrganization(httpOrganizationID(rw))
+
+	w.service.auditor.Record(entry)
+}
@@ -0,0 +1,641 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"facette/auth"
+	"facette/backend"
+
+	"github.com/facette/httproute"
+	"github.com/facette/httputil"
+	"github.com/facette/sqlstorage"
+)
+
+const (
+	authCookieName    = "facette_session"
+	authDefaultUser   = "admin"
+	authLastUsedDelay = time.Minute
+)
+
+// authResponseWriter carries the authenticated user down to the handlers, as the router replaces the requests
+// context with the endpoints one.
+type authResponseWriter struct {
+	http.ResponseWriter
+	user  *backend.User
+	token *backend.Token
+}
+
+type authLoginRequest struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+type authPasswordRequest struct {
+	Current  string `json:"current"`
+	Password string `json:"password"`
+}
+
+type authTokenRequest struct {
+	Name    string     `json:"name"`
+	Expires *time.Time `json:"expires"`
+}
+
+type authTokenResponse struct {
+	*backend.Token
+	Secret string `json:"token"`
+}
+
+// httpInitAuth initializes the external authentication backends, and creates a default administrator user with a
+// random password if authentication is enabled and no user exists yet, as otherwise nobody would be able to log in.
+func (w *httpWorker) httpInitAuth() error {
+	if !w.service.config.Auth.Enabled {
+		return nil
+	}
+
+	for _, settings := range w.service.config.Auth.Backends {
+		a, err := auth.NewAuthenticator(settings, w.log.Context("auth"))
+		if err != nil {
+			return err
+		}
+
+		w.authenticators = append(w.authenticators, a)
+	}
+
+	count, err := w.service.backend.Storage().Count(&backend.User{})
+	if err != nil || count > 0 {
+		return err
+	}
+
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+
+	user := w.service.backend.NewUser()
+	user.Name = authDefaultUser
+	user.Password = hex.EncodeToString(buf)
+	user.Role = backend.RoleAdmin
+	user.Enabled = true
+
+	password := user.Password
+
+	if err := w.service.backend.Storage().Save(user); err != nil {
+		return err
+	}
+
+	w.log.Warning("created %q user with password %q, please change it upon first login", user.Name, password)
+
+	return nil
+}
+
+func (w *httpWorker) httpHandleAuth(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Skip authentication if disabled or for public resources
+		if !w.service.config.Auth.Enabled || !w.httpAuthRequired(r) {
+			h.ServeHTTP(rw, r)
+			return
+		}
+
+		// Authenticate request unless already done (i.e. bulk sub-requests)
+		user := httpAuthUser(rw)
+		if user == nil {
+			var (
+				token *backend.Token
+				err   error
+			)
+
+			user, token, err = w.httpAuthenticate(rw, r)
+			if err == ErrUnauthorized {
+				rw.Header().Set("WWW-Authenticate", `Bearer realm="facette"`)
+				httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusUnauthorized)
+				return
+			} else if err != nil {
+				w.log.Error("failed to authenticate request: %s", err)
+				httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+				return
+			}
+
+			rw = authResponseWriter{rw, user, token}
+		}
+
+		if !w.httpAuthorize(user, r) {
+			httputil.WriteJSON(rw, httpBuildMessage(ErrForbidden), http.StatusForbidden)
+			return
+		}
+
+		// Restrict users belonging to an organization to it, selecting it unless done by the request. Only
+		// administrators not belonging to any organization can select one, other users being restricted to the items
+		// not belonging to any.
+		if user.OrganizationID != nil {
+			if org := httpOrganization(rw); org == nil {
+				org, err := w.httpGetOrganization(*user.OrganizationID)
+				if err != nil {
+					w.log.Error("failed to fetch organization: %s", err)
+					httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+					return
+				}
+
+				rw = orgResponseWriter{rw, org}
+			} else if org.ID != *user.OrganizationID {
+				httputil.WriteJSON(rw, httpBuildMessage(ErrForbidden), http.StatusForbidden)
+				return
+			}
+		} else if httpOrganization(rw) != nil && user.Role != backend.RoleAdmin {
+			httputil.WriteJSON(rw, httpBuildMessage(ErrForbidden), http.StatusForbidden)
+			return
+		}
+
+		h.ServeHTTP(rw, r)
+	})
+}
+
+func (w *httpWorker) httpAuthRequired(r *http.Request) bool {
+	// Frontend assets are public, as they are needed to display the login page
+	if !strings.HasPrefix(r.URL.Path, w.prefix+"/") {
+		return false
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, w.prefix)
+
+	switch path {
+	case "/", "/auth/login":
+		return false
+	}
+
+	// Snapshots are publicly accessible using their secret
+	return !strings.HasPrefix(path, "/snapshots/public/")
+}
+
+func (w *httpWorker) httpAuthenticate(rw http.ResponseWriter, r *http.Request) (*backend.User, *backend.Token,
+	error) {
+
+	var secret string
+
+	// Identify user from trusted request properties (i.e. reverse proxy headers) if supported
+	for _, a := range w.authenticators {
+		ra, ok := a.(auth.RequestAuthenticator)
+		if !ok {
+			continue
+		}
+
+		identity, err := ra.Identify(r)
+		if err == auth.ErrInvalidCredentials {
+			return nil, nil, ErrUnauthorized
+		} else if err != nil {
+			return nil, nil, err
+		} else if identity == nil {
+			continue
+		}
+
+		user, err := w.httpProvisionUser(identity, ra.Type())
+		if err == ErrInvalidCredentials || err == nil && !user.Enabled {
+			return nil, nil, ErrUnauthorized
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		return user, nil, nil
+	}
+
+	// Retrieve token secret either from authorization header or session cookie
+	if v := r.Header.Get("Authorization"); strings.HasPrefix(v, "Bearer ") {
+		secret = strings.TrimSpace(strings.TrimPrefix(v, "Bearer "))
+	} else if c, err := r.Cookie(authCookieName); err == nil {
+		secret = c.Value
+	}
+
+	if secret == "" {
+		return nil, nil, ErrUnauthorized
+	}
+
+	token := &backend.Token{}
+	if err := w.service.backend.Storage().Get("hash", backend.HashToken(secret), token); err ==
+		sqlstorage.ErrItemNotFound {
+		return nil, nil, ErrUnauthorized
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now().UTC()
+
+	if token.Expired(now) {
+		if err := w.service.backend.Storage().Delete(token); err != nil {
+			w.log.Error("failed to delete expired token: %s", err)
+		}
+
+		return nil, nil, ErrUnauthorized
+	}
+
+	user := w.service.backend.NewUser()
+	if err := w.service.backend.Storage().Get("id", token.UserID, user); err == sqlstorage.ErrItemNotFound {
+		return nil, nil, ErrUnauthorized
+	} else if err != nil {
+		return nil, nil, err
+	} else if !user.Enabled {
+		return nil, nil, ErrUnauthorized
+	}
+
+	// Keep track of API tokens usage, limiting back-end writes
+	if token.Kind == backend.TokenKindAPI && (token.LastUsed == nil || now.Sub(*token.LastUsed) > authLastUsedDelay) {
+		lastUsed := now.Round(time.Second)
+		token.LastUsed = &lastUsed
+
+		if err := w.service.backend.Storage().Save(token); err != nil {
+			w.log.Error("failed to update token: %s", err)
+		}
+	}
+
+	return user, token, nil
+}
+
+func (w *httpWorker) httpHandleAuthLogin(rw http.ResponseWriter, r *http.Request) {
+	var req authLoginRequest
+
+	if err := httputil.BindJSON(r, &req); err == httputil.ErrInvalidContentType {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusUnsupportedMediaType)
+		return
+	} else if err != nil {
+		w.log.Error("unable to unmarshal JSON data: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidJSON), http.StatusBadRequest)
+		return
+	}
+
+	user, err := w.httpLogin(req.Name, req.Password)
+	if err == ErrInvalidCredentials || err == nil && !user.Enabled {
+		w.log.Warning("failed login attempt for %q user from %s", req.Name, r.RemoteAddr)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidCredentials), http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		w.log.Error("failed to authenticate user: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	// Create new session token
+	token, secret, err := backend.NewToken(user.ID, backend.TokenKindSession)
+	if err != nil {
+		w.log.Error("failed to generate token: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now().UTC()
+
+	expires := now.Add(time.Duration(w.service.config.Auth.SessionTTL) * time.Second).Round(time.Second)
+	token.Expires = &expires
+
+	if err := w.service.backend.Storage().Save(token); err != nil {
+		w.log.Error("failed to insert item: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	w.httpPurgeTokens(user, now)
+
+	http.SetCookie(rw, &http.Cookie{
+		Name:     authCookieName,
+		Value:    secret,
+		Path:     w.service.config.RootPath + "/",
+		Expires:  expires,
+		Secure:   w.service.config.Auth.SecureCookie,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	httputil.WriteJSON(rw, user, http.StatusOK)
+}
+
+func (w *httpWorker) httpHandleAuthLogout(rw http.ResponseWriter, r *http.Request) {
+	if httpAuthUser(rw) == nil {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrAuthDisabled), http.StatusNotFound)
+		return
+	}
+
+	// Only delete session tokens, as API tokens are meant to be explicitly revoked and requests authenticated by a
+	// reverse proxy have none
+	if token := httpAuthToken(rw); token != nil && token.Kind == backend.TokenKindSession {
+		if err := w.service.backend.Storage().Delete(token); err != nil && err != sqlstorage.ErrItemNotFound {
+			w.log.Error("failed to delete item: %s", err)
+			httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	http.SetCookie(rw, &http.Cookie{
+		Name:     authCookieName,
+		Path:     w.service.config.RootPath + "/",
+		MaxAge:   -1,
+		Secure:   w.service.config.Auth.SecureCookie,
+		HttpOnly: true,
+	})
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (w *httpWorker) httpHandleAuthPassword(rw http.ResponseWriter, r *http.Request) {
+	var req authPasswordRequest
+
+	if httpAuthUser(rw) == nil {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrAuthDisabled), http.StatusNotFound)
+		return
+	}
+
+	if err := httputil.BindJSON(r, &req); err == httputil.ErrInvalidContentType {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusUnsupportedMediaType)
+		return
+	} else if err != nil {
+		w.log.Error("unable to unmarshal JSON data: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidJSON), http.StatusBadRequest)
+		return
+	}
+
+	// Only users authenticated with their back-end password can change it, the current one being required
+	user := *httpAuthUser(rw)
+	prev := user
+
+	if user.Origin != backend.OriginLocal {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrForbidden), http.StatusForbidden)
+		return
+	} else if !user.CheckPassword(req.Current) {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidCredentials), http.StatusForbidden)
+		return
+	} else if req.Password == "" {
+		httputil.WriteJSON(rw, httpBuildMessage(backend.ErrInvalidPassword), http.StatusBadRequest)
+		return
+	}
+
+	user.Password = req.Password
+
+	if err := w.service.backend.Storage().Save(&user); err != nil {
+		w.log.Error("failed to update item: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	w.httpAudit(rw, r, backend.AuditActionUpdate, "users", &user, &prev)
+
+	// Revoke the other sessions of the user, keeping the current one
+	tokens := []*backend.Token{}
+	if _, err := w.service.backend.Storage().List(&tokens, map[string]interface{}{"user": user.ID,
+		"kind": backend.TokenKindSession}, nil, 0, 0); err != nil {
+		w.log.Error("failed to fetch items: %s", err)
+	}
+
+	for _, token := range tokens {
+		if current := httpAuthToken(rw); current != nil && token.ID == current.ID {
+			continue
+		}
+
+		if err := w.service.backend.Storage().Delete(token); err != nil {
+			w.log.Error("failed to delete item: %s", err)
+		}
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (w *httpWorker) httpHandleAuthUser(rw http.ResponseWriter, r *http.Request) {
+	user := httpAuthUser(rw)
+	if user == nil {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrAuthDisabled), http.StatusNotFound)
+		return
+	}
+
+	httputil.WriteJSON(rw, user, http.StatusOK)
+}
+
+func (w *httpWorker) httpHandleAuthTokenList(rw http.ResponseWriter, r *http.Request) {
+	user := httpAuthUser(rw)
+	if user == nil {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrAuthDisabled), http.StatusNotFound)
+		return
+	}
+
+	tokens := []*backend.Token{}
+	if _, err := w.service.backend.Storage().List(&tokens, map[string]interface{}{"user": user.ID,
+		"kind": backend.TokenKindAPI}, []string{"created"}, 0, 0); err != nil {
+		w.log.Error("failed to fetch items: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	httputil.WriteJSON(rw, tokens, http.StatusOK)
+}
+
+func (w *httpWorker) httpHandleAuthTokenCreate(rw http.ResponseWriter, r *http.Request) {
+	var req authTokenRequest
+
+	user := httpAuthUser(rw)
+	if user == nil {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrAuthDisabled), http.StatusNotFound)
+		return
+	}
+
+	if err := httputil.BindJSON(r, &req); err == httputil.ErrInvalidContentType {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusUnsupportedMediaType)
+		return
+	} else if err != nil {
+		w.log.Error("unable to unmarshal JSON data: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidJSON), http.StatusBadRequest)
+		return
+	} else if req.Expires != nil && !req.Expires.After(time.Now()) {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return
+	}
+
+	token, secret, err := backend.NewToken(user.ID, backend.TokenKindAPI)
+	if err != nil {
+		w.log.Error("failed to generate token: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	token.Name = req.Name
+	token.Expires = req.Expires
+
+	if err := w.service.backend.Storage().Save(token); err != nil {
+		w.log.Error("failed to insert item: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	w.httpAudit(rw, r, backend.AuditActionCreate, "tokens", token, nil)
+
+	// Token secret is only returned once, as only its hash is stored
+	httputil.WriteJSON(rw, authTokenResponse{Token: token, Secret: secret}, http.StatusCreated)
+}
+
+func (w *httpWorker) httpHandleAuthTokenDelete(rw http.ResponseWriter, r *http.Request) {
+	id := httproute.ContextParam(r, "id").(string)
+
+	user := httpAuthUser(rw)
+	if user == nil {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrAuthDisabled), http.StatusNotFound)
+		return
+	}
+
+	token := &backend.Token{}
+	if err := w.service.backend.Storage().Get("id", id, token); err == sqlstorage.ErrItemNotFound ||
+		err == nil && (token.UserID != user.ID || token.Kind != backend.TokenKindAPI) {
+		httputil.WriteJSON(rw, httpBuildMessage(sqlstorage.ErrItemNotFound), http.StatusNotFound)
+		return
+	} else if err != nil {
+		w.log.Error("failed to fetch item: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	if err := w.service.backend.Storage().Delete(token); err != nil {
+		w.log.Error("failed to delete item: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	w.httpAudit(rw, r, backend.AuditActionDelete, "tokens", token, nil)
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// httpLogin checks user credentials, either against its back-end password or using the authentication backends
+// supporting it. Users are bound to the authentication backend they have been provisioned by.
+func (w *httpWorker) httpLogin(name, password string) (*backend.User, error) {
+	user := w.service.backend.NewUser()
+	if err := w.service.backend.Storage().Get("name", name, user); err == nil && user.Origin == backend.OriginLocal {
+		if !user.CheckPassword(password) {
+			return nil, ErrInvalidCredentials
+		}
+
+		return user, nil
+	} else if err != nil && err != sqlstorage.ErrItemNotFound {
+		return nil, err
+	}
+
+	for _, a := range w.authenticators {
+		pa, ok := a.(auth.PasswordAuthenticator)
+		if !ok || user.ID != "" && user.Origin != pa.Type() {
+			continue
+		}
+
+		identity, err := pa.Login(name, password)
+		if err == auth.ErrInvalidCredentials {
+			continue
+		} else if err != nil {
+			w.log.Error("failed to authenticate %q user using %s backend: %s", name, pa.Type(), err)
+			continue
+		}
+
+		return w.httpProvisionUser(identity, pa.Type())
+	}
+
+	return nil, ErrInvalidCredentials
+}
+
+// httpProvisionUser returns the back-end user matching an external identity, creating it if needed. User role, email
+// address and organization are kept in sync with the ones provided by the authentication backend, users being never
+// provisioned into the organization selected by the request.
+func (w *httpWorker) httpProvisionUser(identity *auth.Identity, origin string) (*backend.User, error) {
+	var organization string
+
+	if identity.Organization != "" {
+		org, err := w.httpGetOrganization(identity.Organization)
+		if err == sqlstorage.ErrItemNotFound {
+			w.log.Warning("refusing %q user identity from %s backend: unknown %q organization", identity.Name, origin,
+				identity.Organization)
+			return nil, ErrInvalidCredentials
+		} else if err != nil {
+			return nil, err
+		}
+
+		organization = org.ID
+	}
+
+	user := w.service.backend.NewUser()
+	if err := w.service.backend.Storage().Get("name", identity.Name, user); err == sqlstorage.ErrItemNotFound {
+		user.Name = identity.Name
+		user.Origin = origin
+		user.Enabled = true
+	} else if err != nil {
+		return nil, err
+	} else if user.Origin != origin {
+		// Prevent external identities from taking over users provisioned otherwise (i.e. local ones)
+		w.log.Warning("refusing %q user identity from %s backend, as it originates from %s", identity.Name, origin,
+			user.Origin)
+		return nil, ErrInvalidCredentials
+	}
+
+	if user.ID != "" && user.Role == identity.Role && (identity.Email == "" ||
+		user.Email != nil && *user.Email == identity.Email) && user.GetOrganization() == organization {
+		return user, nil
+	}
+
+	user.Role = identity.Role
+	if identity.Email != "" {
+		user.Email = &identity.Email
+	}
+	user.SetOrganization(organization)
+
+	if err := w.service.backend.Storage().Save(user); err == backend.ErrInvalidName {
+		w.log.Warning("refusing %q user identity from %s backend: %s", identity.Name, origin, err)
+		return nil, ErrInvalidCredentials
+	} else if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// httpPurgeTokens deletes the user expired tokens.
+func (w *httpWorker) httpPurgeTokens(user *backend.User, now time.Time) {
+	tokens := []*backend.Token{}
+	if _, err := w.service.backend.Storage().List(&tokens, map[string]interface{}{"user": user.ID}, nil, 0,
+		0); err != nil {
+		w.log.Error("failed to fetch items: %s", err)
+		return
+	}
+
+	for _, token := range tokens {
+		if token.Expired(now) {
+			if err := w.service.backend.Storage().Delete(token); err != nil {
+				w.log.Error("failed to delete expired token: %s", err)
+			}
+		}
+	}
+}
+
+func httpAuthUser(rw http.ResponseWriter) *backend.User {
+	if arw := httpAuthResponseWriter(rw); arw != nil {
+		return arw.user
+	}
+
+	return nil
+}
+
+func httpAuthToken(rw http.ResponseWriter) *backend.Token {
+	if arw := httpAuthResponseWriter(rw); arw != nil {
+		return arw.token
+	}
+
+	return nil
+}
+
+func httpAuthResponseWriter(rw http.ResponseWriter) *authResponseWriter {
+	for {
+		switch v := rw.(type) {
+		case authResponseWriter:
+			return &v
+
+		case orgResponseWriter:
+			rw = v.ResponseWriter
+
+		case responseWriter:
+			rw = v.ResponseWriter
+
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"facette/backend"
+)
+
+func Test_HTTP_HandleAuth_Organization(t *testing.T) {
+	org1 := &backend.Organization{ID: "00000000-0000-0000-0000-000000000001", Name: "org1"}
+	org2 := &backend.Organization{ID: "00000000-0000-0000-0000-000000000002", Name: "org2"}
+
+	w := &httpWorker{service: NewService(&config{}), prefix: apiPrefix}
+	w.service.config.Auth.Enabled = true
+
+	h := w.httpHandleAuth(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+
+	for _, test := range []struct {
+		role     string
+		userOrg  *backend.Organization
+		org      *backend.Organization
+		expected int
+	}{
+		// Users not belonging to any organization
+		{backend.RoleAdmin, nil, nil, http.StatusNoContent},
+		{backend.RoleAdmin, nil, org1, http.StatusNoContent},
+		{backend.RoleEditor, nil, nil, http.StatusNoContent},
+		{backend.RoleEditor, nil, org1, http.StatusForbidden},
+		{backend.RoleViewer, nil, org2, http.StatusForbidden},
+
+		// Users belonging to an organization
+		{backend.RoleAdmin, org1, org1, http.StatusNoContent},
+		{backend.RoleAdmin, org1, org2, http.StatusForbidden},
+		{backend.RoleEditor, org1, org1, http.StatusNoContent},
+		{backend.RoleEditor, org1, org2, http.StatusForbidden},
+	} {
+		user := &backend.User{Name: "user1", Role: test.role}
+		if test.userOrg != nil {
+			user.SetOrganization(test.userOrg.ID)
+		}
+
+		// Authenticated user is set as done for bulk sub-requests, thus skipping authentication
+		rec := httptest.NewRecorder()
+
+		var rw http.ResponseWriter = authResponseWriter{ResponseWriter: rec, user: user}
+		if test.org != nil {
+			rw = orgResponseWriter{rw, test.org}
+		}
+
+		h.ServeHTTP(rw, httptest.NewRequest("GET", apiPrefix+"/library/graphs/", nil))
+
+		if rec.Code != test.expected {
+			t.Logf("\nExpected %d\nbut got  %d (%s user of %v organization selecting %v)", test.expected, rec.Code,
+				test.role, test.userOrg, test.org)
+			t.Fail()
+		}
+	}
+}
+
+func Test_HTTP_HandleAuthPassword(t *testing.T) {
+	w, cleanup := testHTTPWorker(t)
+	defer cleanup()
+
+	w.service.config.Auth.Enabled = true
+	w.service.config.Auth.SessionTTL = 3600
+
+	for _, user := range []*backend.User{
+		{Item: backend.Item{Name: "admin"}, Password: "admin-password", Role: backend.RoleAdmin, Enabled: true},
+		{Item: backend.Item{Name: "user1"}, Password: "user1-password", Role: backend.RoleViewer, Enabled: true},
+	} {
+		if err := w.service.backend.Storage().Save(user); err != nil {
+			t.Fatalf("failed to save user: %s", err)
+		}
+	}
+
+	login := func(name, password string) http.Header {
+		rec := testHTTPRequest(w, "POST", "/auth/login", fmt.Sprintf(`{"name":%q,"password":%q}`, name, password),
+			nil)
+		if rec.Code != http.StatusOK {
+			return nil
+		}
+
+		return http.Header{"Cookie": {rec.Header().Get("Set-Cookie")}}
+	}
+
+	admin, session1, session2 := login("admin", "admin-password"), login("user1", "user1-password"),
+		login("user1", "user1-password")
+	if admin == nil || session1 == nil || session2 == nil {
+		t.Fatalf("failed to log in")
+	}
+
+	for _, test := range []struct {
+		body     string
+		expected int
+	}{
+		{`{"current":"invalid","password":"new-password"}`, http.StatusForbidden},
+		{`{"current":"user1-password","password":""}`, http.StatusBadRequest},
+		{`{"current":"user1-password","password":"new-password"}`, http.StatusNoContent},
+	} {
+		if rec := testHTTPRequest(w, "PUT", "/auth/password", test.body, session1); rec.Code != test.expected {
+			t.Logf("\nExpected %d\nbut got  %d (%s)", test.expected, rec.Code, test.body)
+			t.Fail()
+		}
+	}
+
+	// Ensure the other sessions are revoked and the new password is effective
+	if rec := testHTTPRequest(w, "GET", "/auth/user", "", session2); rec.Code != http.StatusUnauthorized {
+		t.Logf("\nExpected %d\nbut got  %d", http.StatusUnauthorized, rec.Code)
+		t.Fail()
+	} else if login("user1", "user1-password") != nil || login("user1", "new-password") == nil {
+		t.Logf("\nExpected new password to be effective\nbut got  previous one")
+		t.Fail()
+	}
+
+	// Ensure users updated without password keep their password and origin
+	user := w.service.backend.NewUser()
+	if err := w.service.backend.Storage().Get("name", "user1", user); err != nil {
+		t.Fatalf("failed to fetch user: %s", err)
+	}
+
+	rec := testHTTPRequest(w, "PUT", "/users/"+user.ID, `{"name":"user1","role":"editor","enabled":true}`, admin)
+	if rec.Code != http.StatusNoContent {
+		t.Logf("\nExpected %d\nbut got  %d (%s)", http.StatusNoContent, rec.Code, rec.Body.String())
+		t.Fail()
+	} else if err := w.service.backend.Storage().Get("name", "user1", user); err != nil {
+		t.Fatalf("failed to fetch user: %s", err)
+	} else if user.Role != backend.RoleEditor || user.Origin != backend.OriginLocal ||
+		!user.CheckPassword("new-password") {
+		t.Logf("\nExpected editor local user with unchanged password\nbut got  %#v", user)
+		t.Fail()
+	}
+}
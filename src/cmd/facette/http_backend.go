@@ -22,6 +22,11 @@ var backendTypes = []string{
 	"graphs",
 	"sourcegroups",
 	"metricgroups",
+	"alerts",
+	"channels",
+	"annotations",
+	"reports",
+	"users",
 }
 
 func (w *httpWorker) httpHandleBackendCreate(rw http.ResponseWriter, r *http.Request) {
@@ -50,6 +55,9 @@ func (w *httpWorker) httpHandleBackendCreate(rw http.ResponseWriter, r *http.Req
 			w.log.Error("failed to fetch item for deletion: %s", err)
 			httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
 			return
+		} else if !w.httpCheckOrganization(rw, rv.Interface()) ||
+			!w.httpCheckAccess(rw, rv.Interface(), backend.AccessRead) {
+			return
 		}
 
 		for _, name := range []string{"ID", "Created", "Modifed", "Alias"} {
@@ -59,6 +67,12 @@ func (w *httpWorker) httpHandleBackendCreate(rw http.ResponseWriter, r *http.Req
 		}
 	}
 
+	// Set alert rule, channel, report and user enabled unless explicitly disabled
+	if (typ == "alerts" || typ == "channels" || typ == "reports" || typ == "users") &&
+		r.URL.Query().Get("inherit") == "" {
+		reflect.Indirect(rv).FieldByName("Enabled").SetBool(true)
+	}
+
 	// Fill item with data received from request
 	if err := httputil.BindJSON(r, rv.Interface()); err == httputil.ErrInvalidContentType {
 		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusUnsupportedMediaType)
@@ -82,19 +96,43 @@ func (w *httpWorker) httpHandleBackendCreate(rw http.ResponseWriter, r *http.Req
 		}
 	}
 
+	// Check notification channel settings
+	if typ == "channels" && !w.httpCheckChannel(rw, rv.Interface().(*backend.Channel), nil) {
+		return
+	}
+
+	// Check report output directory
+	if typ == "reports" && !w.httpCheckReport(rw, rv.Interface().(*backend.Report)) {
+		return
+	}
+
 	// Set provider enabled by default
 	if typ == "providers" {
 		reflect.Indirect(rv).FieldByName("Enabled").SetBool(true)
 	}
 
+	// Set item organization and owner upon creation
+	w.httpApplyOrganization(rw, rv.Interface())
+	w.httpApplyACL(rw, rv.Interface(), nil)
+
+	if typ == "reports" {
+		w.httpApplyReportOwner(rw, rv.Interface().(*backend.Report), nil)
+	}
+
+	if !w.httpCheckReferences(rw, rv.Interface()) {
+		return
+	}
+
 	// Insert item into back-end
 	if err := w.service.backend.Storage().Save(rv.Interface()); err != nil {
 		switch err {
 		case sqlstorage.ErrItemConflict:
 			httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusConflict)
 
-		case backend.ErrInvalidAlias, backend.ErrInvalidID, backend.ErrInvalidName, sqlstorage.ErrMissingField,
-			sqlstorage.ErrUnknownReference:
+		case backend.ErrInvalidAlias, backend.ErrInvalidCondition, backend.ErrInvalidID, backend.ErrInvalidInterval,
+			backend.ErrInvalidGrant, backend.ErrInvalidName, backend.ErrInvalidPassword, backend.ErrInvalidRole,
+			backend.ErrInvalidSchedule, backend.ErrInvalidTarget, backend.ErrInvalidThreshold,
+			backend.ErrInvalidTimerange, sqlstorage.ErrMissingField, sqlstorage.ErrUnknownReference:
 			httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadRequest)
 
 		default:
@@ -107,8 +145,13 @@ func (w *httpWorker) httpHandleBackendCreate(rw http.ResponseWriter, r *http.Req
 
 	id := reflect.Indirect(rv).FieldByName("ID").String()
 
+	w.httpSaveRevision(rw, typ, rv.Interface())
+
 	w.log.Debug("inserted %q item into backend", id)
 
+	w.httpSetETag(rw, typ, id)
+	w.httpAudit(rw, r, backend.AuditActionCreate, typ, rv.Interface(), nil)
+
 	// Start new provider upon creation
 	if typ == "providers" {
 		go w.service.poller.StartProvider(rv.Interface().(*backend.Provider))
@@ -140,6 +183,9 @@ func (w *httpWorker) httpHandleBackendGet(rw http.ResponseWriter, r *http.Reques
 		w.log.Error("failed to fetch item: %s", err)
 		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
 		return
+	} else if !w.httpCheckOrganization(rw, rv.Interface()) ||
+		!w.httpCheckAccess(rw, rv.Interface(), backend.AccessRead) {
+		return
 	}
 
 	// Handle collection expansion request
@@ -149,9 +195,19 @@ func (w *httpWorker) httpHandleBackendGet(rw http.ResponseWriter, r *http.Reques
 	}
 
 	if fields := httpGetListParam(r, "fields", nil); fields != nil {
-		result = jsonutil.FilterStruct(rv.Interface(), fields)
+		result = jsonutil.FilterStruct(httpMaskItem(rv.Interface()), fields)
 	} else {
-		result = rv.Interface()
+		result = httpMaskItem(rv.Interface())
+	}
+
+	// Handle conditional request, the entity tag being derived from the returned representation as expanded and
+	// filtered items differ from the stored item (thus matching the stored item one otherwise)
+	etag := httpItemETag(result)
+	rw.Header().Set("ETag", etag)
+
+	if v := r.Header.Get("If-None-Match"); v != "" && httpMatchETag(v, etag, true) {
+		rw.WriteHeader(http.StatusNotModified)
+		return
 	}
 
 	httputil.WriteJSON(rw, result, http.StatusOK)
@@ -189,6 +245,45 @@ func (w *httpWorker) httpHandleBackendUpdate(rw http.ResponseWriter, r *http.Req
 		reflect.Indirect(rv).FieldByName("ID").SetString(id)
 	}
 
+	// Check for existing item organization and access permissions, keeping track of its original state and access
+	// control list
+	var acl *backend.ACL
+
+	orig, _ := w.httpBackendNewItem(typ)
+	if err := w.service.backend.Storage().Get("id", id, orig); err == sqlstorage.ErrItemNotFound {
+		orig = nil
+	} else if err != nil {
+		w.log.Error("failed to fetch item: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	if orig != nil {
+		if !w.httpCheckOrganization(rw, orig) {
+			return
+		}
+
+		if v, ok := orig.(backend.ACLItem); ok {
+			if !w.httpCheckAccess(rw, v, backend.AccessWrite) {
+				return
+			}
+
+			acl = v.GetACL().Clone()
+		}
+	}
+
+	// Ensure item hasn't changed since retrieved by the client if requested, the update then being conditional to it
+	// not changing until saved (the patched item being retrieved prior to checking preconditions)
+	if !httpCheckPreconditions(rw, r, orig) {
+		return
+	}
+
+	if r.Method == "PATCH" {
+		httpExpectModified(rv.Interface(), rv.Interface())
+	} else if orig != nil {
+		httpExpectModified(rv.Interface(), orig)
+	}
+
 	// Fill item with data received from request
 	if err := httputil.BindJSON(r, rv.Interface()); err == httputil.ErrInvalidContentType {
 		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusUnsupportedMediaType)
@@ -212,14 +307,65 @@ func (w *httpWorker) httpHandleBackendUpdate(rw http.ResponseWriter, r *http.Req
 		}
 	}
 
+	// Check notification channel settings
+	if typ == "channels" {
+		var prev *backend.Channel
+		if orig != nil {
+			prev = orig.(*backend.Channel)
+		}
+
+		// Keep secret settings sent back masked
+		rv.Interface().(*backend.Channel).Unmask(prev)
+
+		if !w.httpCheckChannel(rw, rv.Interface().(*backend.Channel), prev) {
+			return
+		}
+	}
+
+	// Check report output directory
+	if typ == "reports" && !w.httpCheckReport(rw, rv.Interface().(*backend.Report)) {
+		return
+	}
+
+	// Keep user password and origin unless provided, as neither is returned to clients
+	if typ == "users" && orig != nil {
+		user, prev := rv.Interface().(*backend.User), orig.(*backend.User)
+
+		user.PasswordHash = prev.PasswordHash
+		if user.Origin == "" {
+			user.Origin = prev.Origin
+		}
+	}
+
+	w.httpApplyOrganization(rw, rv.Interface())
+	w.httpApplyACL(rw, rv.Interface(), acl)
+
+	if typ == "reports" {
+		var prev *backend.Report
+		if orig != nil {
+			prev = orig.(*backend.Report)
+		}
+
+		w.httpApplyReportOwner(rw, rv.Interface().(*backend.Report), prev)
+	}
+
+	if !w.httpCheckReferences(rw, rv.Interface()) {
+		return
+	}
+
 	// Update item in back-end
 	if err := w.service.backend.Storage().Save(rv.Interface()); err != nil {
 		switch err {
 		case sqlstorage.ErrItemConflict:
 			httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusConflict)
 
-		case backend.ErrInvalidAlias, backend.ErrInvalidID, backend.ErrInvalidName, sqlstorage.ErrMissingField,
-			sqlstorage.ErrUnknownReference:
+		case backend.ErrItemModified:
+			httpWriteItemModified(rw, r)
+
+		case backend.ErrInvalidAlias, backend.ErrInvalidCondition, backend.ErrInvalidID, backend.ErrInvalidInterval,
+			backend.ErrInvalidGrant, backend.ErrInvalidName, backend.ErrInvalidPassword, backend.ErrInvalidRole,
+			backend.ErrInvalidSchedule, backend.ErrInvalidTarget, backend.ErrInvalidThreshold,
+			backend.ErrInvalidTimerange, sqlstorage.ErrMissingField, sqlstorage.ErrUnknownReference:
 			httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadRequest)
 
 		default:
@@ -230,8 +376,18 @@ func (w *httpWorker) httpHandleBackendUpdate(rw http.ResponseWriter, r *http.Req
 		return
 	}
 
+	w.httpSaveRevision(rw, typ, rv.Interface())
+
 	w.log.Debug("updated %s item from back-end", id)
 
+	w.httpSetETag(rw, typ, id)
+
+	if orig != nil {
+		w.httpAudit(rw, r, backend.AuditActionUpdate, typ, rv.Interface(), orig)
+	} else {
+		w.httpAudit(rw, r, backend.AuditActionCreate, typ, rv.Interface(), nil)
+	}
+
 	// Restart provider on update
 	if typ == "providers" {
 		if err := w.service.backend.Storage().Get("id", id, rv.Interface()); err == nil {
@@ -268,10 +424,14 @@ func (w *httpWorker) httpHandleBackendDelete(rw http.ResponseWriter, r *http.Req
 		w.log.Error("failed to fetch item for deletion: %s", err)
 		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
 		return
+	} else if !w.httpCheckOrganization(rw, rv.Interface()) ||
+		!w.httpCheckAccess(rw, rv.Interface(), backend.AccessWrite) ||
+		!httpCheckPreconditions(rw, r, rv.Interface()) {
+		return
 	}
 
 	// Delete item from back-end
-	err := w.service.backend.Storage().Delete(rv.Interface())
+	err := w.httpDeleteItem(rw, typ, rv.Interface())
 	if err == sqlstorage.ErrItemNotFound {
 		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusNotFound)
 		return
@@ -283,17 +443,12 @@ func (w *httpWorker) httpHandleBackendDelete(rw http.ResponseWriter, r *http.Req
 
 	w.log.Debug("deleted %s item from back-end", id)
 
-	// Stop provider upon deletion
-	if typ == "providers" {
-		go w.service.poller.StopProvider(rv.Interface().(*backend.Provider), false)
-	}
+	w.httpAudit(rw, r, backend.AuditActionDelete, typ, rv.Interface(), nil)
 
 	rw.WriteHeader(http.StatusNoContent)
 }
 
 func (w *httpWorker) httpHandleBackendDeleteAll(rw http.ResponseWriter, r *http.Request) {
-	var rv reflect.Value
-
 	if w.service.config.ReadOnly {
 		httputil.WriteJSON(rw, httpBuildMessage(ErrReadOnly), http.StatusForbidden)
 		return
@@ -314,32 +469,44 @@ func (w *httpWorker) httpHandleBackendDeleteAll(rw http.ResponseWriter, r *http.
 		return
 	}
 
-	// Request items list from back-end
-	if typ == "providers" {
-		rv = reflect.New(reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(item)), 0, 0).Type())
+	// Request items list from back-end, only deleting the ones belonging to the selected organization
+	filters := make(map[string]interface{})
+	w.httpApplyOrganizationFilter(rw, item, filters)
 
-		_, err := w.service.backend.Storage().List(rv.Interface(), nil, nil, 0, 0)
-		if err == sqlstorage.ErrUnknownColumn {
-			httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadRequest)
-			return
-		} else if err != nil {
-			w.log.Error("failed to fetch items for deletion: %s", err)
+	rv := reflect.New(reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(item)), 0, 0).Type())
+
+	_, err := w.service.backend.Storage().List(rv.Interface(), filters, nil, 0, 0)
+	if err == sqlstorage.ErrUnknownColumn {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		w.log.Error("failed to fetch items for deletion: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	for i, n := 0, reflect.Indirect(rv).Len(); i < n; i++ {
+		// Fetch item again, as it might have already been deleted by the database cascading rules, and as items are
+		// listed without their associations
+		v, _ := w.httpBackendNewItem(typ)
+
+		err := w.service.backend.Storage().Get("id", reflect.Indirect(reflect.Indirect(rv).Index(i)).FieldByName("ID").
+			String(), v)
+		if err == nil {
+			if err = w.httpDeleteItem(rw, typ, v); err == nil {
+				w.httpAudit(rw, r, backend.AuditActionDelete, typ, v, nil)
+			}
+		}
+
+		if err != nil && err != sqlstorage.ErrItemNotFound {
+			w.log.Error("failed to delete item: %s", err)
 			httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
 			return
 		}
 	}
 
-	w.service.backend.Storage().Delete(reflect.ValueOf(item).Interface())
-
 	w.log.Debug("deleted %s from back-end", typ)
 
-	// Stop provider upon deletion
-	if typ == "providers" {
-		for i, n := 0, reflect.Indirect(rv).Len(); i < n; i++ {
-			go w.service.poller.StopProvider(reflect.Indirect(rv).Index(i).Interface().(*backend.Provider), false)
-		}
-	}
-
 	rw.WriteHeader(http.StatusNoContent)
 }
 
@@ -355,6 +522,7 @@ func (w *httpWorker) httpHandleBackendList(rw http.ResponseWriter, r *http.Reque
 
 	// Check for list filter
 	filters := make(map[string]interface{})
+	w.httpApplyOrganizationFilter(rw, item, filters)
 
 	if v := r.URL.Query().Get("filter"); v != "" {
 		filters["name"] = filterApplyModifier(v)
@@ -404,7 +572,22 @@ func (w *httpWorker) httpHandleBackendList(rw http.ResponseWriter, r *http.Reque
 
 	sort := httpGetListParam(r, "sort", []string{"name"})
 
-	count, err := w.service.backend.Storage().List(rv.Interface(), filters, sort, offset, limit)
+	// Retrieve items hidden to the authenticated user, requiring to paginate results once filtered
+	hidden, err := w.httpHiddenItems(rw)
+	if err != nil {
+		w.log.Error("failed to fetch items: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	} else if _, ok := item.(backend.ACLItem); !ok || len(hidden) == 0 {
+		hidden = nil
+	}
+
+	listOffset, listLimit := offset, limit
+	if hidden != nil {
+		listOffset, listLimit = 0, 0
+	}
+
+	count, err := w.service.backend.Storage().List(rv.Interface(), filters, sort, listOffset, listLimit)
 	if err == sqlstorage.ErrUnknownColumn {
 		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadRequest)
 		return
@@ -414,12 +597,30 @@ func (w *httpWorker) httpHandleBackendList(rw http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	if hidden != nil {
+		items := reflect.MakeSlice(reflect.Indirect(rv).Type(), 0, 0)
+		for i, n := 0, reflect.Indirect(rv).Len(); i < n; i++ {
+			if id := reflect.Indirect(reflect.Indirect(rv).Index(i)).FieldByName("ID").String(); !hidden[id] {
+				items = reflect.Append(items, reflect.Indirect(rv).Index(i))
+			}
+		}
+
+		count = items.Len()
+
+		start, end := httpGetPageBounds(count, offset, limit)
+		reflect.Indirect(rv).Set(items.Slice(start, end))
+	}
+
 	// Parse requested fields list or set defaults
 	fields := httpGetListParam(r, "fields", nil)
 	if fields == nil {
 		fields = []string{"id", "name", "description", "created", "modified"}
-		if typ == "providers" {
+		if typ == "providers" || typ == "alerts" || typ == "channels" || typ == "reports" {
 			fields = append(fields, "enabled")
+		} else if typ == "users" {
+			fields = append(fields, "role", "origin", "enabled")
+		} else if typ == "annotations" {
+			fields = append(fields, "start", "end", "text", "tags")
 		}
 	}
 
@@ -433,7 +634,8 @@ func (w *httpWorker) httpHandleBackendList(rw http.ResponseWriter, r *http.Reque
 
 			result = append(result, jsonutil.FilterStruct(collection, fields))
 		} else {
-			result = append(result, jsonutil.FilterStruct(reflect.Indirect(rv).Index(i).Interface(), fields))
+			result = append(result, jsonutil.FilterStruct(httpMaskItem(reflect.Indirect(rv).Index(i).Interface()),
+				fields))
 		}
 	}
 
@@ -441,6 +643,36 @@ func (w *httpWorker) httpHandleBackendList(rw http.ResponseWriter, r *http.Reque
 	httputil.WriteJSON(rw, result, http.StatusOK)
 }
 
+// httpDeleteItem deletes a back-end item, moving it to trash first if its type supports it.
+func (w *httpWorker) httpDeleteItem(rw http.ResponseWriter, typ string, item interface{}) error {
+	entry, err := w.httpMoveToTrash(rw, typ, item)
+	if err != nil {
+		return err
+	}
+
+	if err := w.service.backend.Storage().Delete(item); err != nil {
+		// Discard trash entry as item hasn't been deleted
+		if entry != nil {
+			w.service.backend.Storage().Delete(entry)
+		}
+
+		return err
+	}
+
+	id := reflect.Indirect(reflect.ValueOf(item)).FieldByName("ID").String()
+
+	w.httpDeleteRevisions(typ, id)
+
+	// Stop provider upon deletion, along with the ones belonging to a deleted organization
+	if typ == "providers" {
+		go w.service.poller.StopProvider(item.(*backend.Provider), false)
+	} else if typ == "organizations" {
+		go w.service.poller.StopOrganization(id)
+	}
+
+	return nil
+}
+
 func (w *httpWorker) httpBackendNewItem(typ string) (interface{}, bool) {
 	switch typ {
 	case "providers":
@@ -458,6 +690,23 @@ func (w *httpWorker) httpBackendNewItem(typ string) (interface{}, bool) {
 	case "metricgroups":
 		return w.service.backend.NewMetricGroup(), true
 
+	case "alerts":
+		return w.service.backend.NewAlertRule(), true
+
+	case "channels":
+		return w.service.backend.NewChannel(), true
+
+	case "annotations":
+		return w.service.backend.NewAnnotation(), true
+
+	case "reports":
+		return w.service.backend.NewReport(), true
+
+	case "users":
+		return w.service.backend.NewUser(), true
+
+	case "organizations":
+		return w.service.backend.NewOrganization(), true
 	}
 
 	return nil, false
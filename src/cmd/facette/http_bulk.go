@@ -44,6 +44,8 @@ func (w *httpWorker) httpHandleBulk(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	addr := r.RemoteAddr
+
 	result := make(bulkResponse, len(req))
 	for idx, entry := range req {
 		// Prepare sub-request
@@ -69,10 +71,11 @@ func (w *httpWorker) httpHandleBulk(rw http.ResponseWriter, r *http.Request) {
 		}
 		r.URL.RawQuery = q.Encode()
 
-		// Set remote address to internal (displayed in debugging logs)
-		r.RemoteAddr = "<internal>"
+		// Forward remote address to sub-request (displayed in debugging logs and recorded in audit log)
+		r.RemoteAddr = addr
 
-		w.router.ServeHTTP(rec, r)
+		// Forward authenticated user and selected organization to sub-request
+		w.router.ServeHTTP(httpForwardResponseWriter(rw, rec), r)
 
 		// Generate response entry
 		result[idx] = bulkResponseEntry{
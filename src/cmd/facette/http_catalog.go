@@ -18,9 +18,9 @@ func (w *httpWorker) httpHandleCatalogRoot(rw http.ResponseWriter, r *http.Reque
 
 	// Get item types list and information
 	result := map[string]int{
-		"origins": len(w.httpCatalogSearch("origins", "", r)),
-		"sources": len(w.httpCatalogSearch("sources", "", r)),
-		"metrics": len(w.httpCatalogSearch("metrics", "", r)),
+		"origins": len(w.httpCatalogSearch(rw, r, "origins", "")),
+		"sources": len(w.httpCatalogSearch(rw, r, "sources", "")),
+		"metrics": len(w.httpCatalogSearch(rw, r, "metrics", "")),
 	}
 
 	httputil.WriteJSON(rw, result, http.StatusOK)
@@ -31,7 +31,7 @@ func (w *httpWorker) httpHandleCatalogType(rw http.ResponseWriter, r *http.Reque
 
 	typ := httproute.ContextParam(r, "type").(string)
 
-	search := w.httpCatalogSearch(typ, "", r)
+	search := w.httpCatalogSearch(rw, r, typ, "")
 	if search == nil {
 		rw.WriteHeader(http.StatusNotFound)
 		return
@@ -88,7 +88,7 @@ func (w *httpWorker) httpHandleCatalogEntry(rw http.ResponseWriter, r *http.Requ
 	typ := httproute.ContextParam(r, "type").(string)
 	name := httproute.ContextParam(r, "name").(string)
 
-	search := w.httpCatalogSearch(typ, name, r)
+	search := w.httpCatalogSearch(rw, r, typ, name)
 	if search == nil || len(search) == 0 {
 		rw.WriteHeader(http.StatusNotFound)
 		return
@@ -176,12 +176,15 @@ func (w *httpWorker) httpHandleCatalogEntry(rw http.ResponseWriter, r *http.Requ
 	httputil.WriteJSON(rw, result, http.StatusOK)
 }
 
-func (w *httpWorker) httpCatalogSearch(typ, name string, r *http.Request) []interface{} {
+func (w *httpWorker) httpCatalogSearch(rw http.ResponseWriter, r *http.Request, typ, name string) []interface{} {
 	search := []interface{}{}
 
+	// Search within the catalogs of the providers belonging to the selected organization
+	searcher := w.service.Searcher(httpOrganizationID(rw))
+
 	switch typ {
 	case "origins":
-		for _, o := range w.service.searcher.Origins(
+		for _, o := range searcher.Origins(
 			name,
 			-1,
 		) {
@@ -189,7 +192,7 @@ func (w *httpWorker) httpCatalogSearch(typ, name string, r *http.Request) []inte
 		}
 
 	case "sources":
-		for _, s := range w.service.searcher.Sources(
+		for _, s := range searcher.Sources(
 			r.URL.Query().Get("origin"),
 			name,
 			-1,
@@ -198,7 +201,7 @@ func (w *httpWorker) httpCatalogSearch(typ, name string, r *http.Request) []inte
 		}
 
 	case "metrics":
-		for _, m := range w.service.searcher.Metrics(
+		for _, m := range searcher.Metrics(
 			r.URL.Query().Get("origin"),
 			r.URL.Query().Get("source"),
 			name,
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"facette/backend"
+	"facette/notifier"
+
+	"github.com/facette/httproute"
+	"github.com/facette/httputil"
+	"github.com/facette/sqlstorage"
+)
+
+func (w *httpWorker) httpHandleChannelTest(rw http.ResponseWriter, r *http.Request) {
+	id := httproute.ContextParam(r, "id").(string)
+
+	// Request item from back-end
+	channel := w.service.backend.NewChannel()
+	if err := w.service.backend.Storage().Get("id", id, channel); err == sqlstorage.ErrItemNotFound {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		w.log.Error("failed to fetch item: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	} else if !w.httpCheckOrganization(rw, channel) || !w.httpCheckCommandChannel(rw, channel) {
+		return
+	}
+
+	n, err := w.service.NewNotifier(channel, w.log)
+	if err != nil {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	// Send test notification, reporting delivery failures to the client
+	if err := n.Notify(&notifier.Notification{
+		Subject: "Test notification",
+		Message: "This is a test notification sent from Facette.",
+		Time:    time.Now().UTC(),
+	}); err != nil {
+		w.log.Warning("failed to send test notification to %q channel: %s", channel.Name, err)
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadGateway)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// httpMaskItem returns a back-end item as exposed to clients, notification channels having their secret settings
+// masked.
+func httpMaskItem(item interface{}) interface{} {
+	if channel, ok := item.(*backend.Channel); ok && channel != nil {
+		return channel.Masked()
+	}
+
+	return item
+}
+
+// httpCheckChannel returns whether or not a notification channel can be saved by the authenticated user given its
+// original state (nil upon creation), writing an error response otherwise.
+func (w *httpWorker) httpCheckChannel(rw http.ResponseWriter, channel, orig *backend.Channel) bool {
+	if !w.httpCheckCommandChannel(rw, channel) || orig != nil && !w.httpCheckCommandChannel(rw, orig) {
+		return false
+	}
+
+	if _, err := w.service.NewNotifier(channel, w.log); err != nil {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
+
+// httpCheckCommandChannel returns whether or not the authenticated user is allowed to manage a notification channel,
+// writing an error response otherwise. Command channels executing local commands on the server, they are restricted
+// to administrators.
+func (w *httpWorker) httpCheckCommandChannel(rw http.ResponseWriter, channel *backend.Channel) bool {
+	if channel.Kind != "command" {
+		return true
+	} else if user := httpAuthUser(rw); user != nil && user.Role != backend.RoleAdmin {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrForbidden), http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"facette/backend"
+
+	"github.com/facette/maputil"
+)
+
+func Test_HTTP_ChannelSecrets(t *testing.T) {
+	w, cleanup := testHTTPWorker(t)
+	defer cleanup()
+
+	url := "http://localhost/hook?token=abc"
+
+	rec := testHTTPRequest(w, "POST", "/library/channels/", `{"name":"channel1","kind":"webhook","settings":{"url":"`+
+		url+`","headers":{"Authorization":"Bearer abc"}}}`, nil)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("failed to create channel: %s", rec.Body.String())
+	}
+
+	path := strings.TrimPrefix(rec.Header().Get("Location"), apiPrefix)
+	id := path[strings.LastIndex(path, "/")+1:]
+
+	expected := map[string]interface{}{
+		"url":     backend.ChannelSecretMask,
+		"headers": map[string]interface{}{"Authorization": backend.ChannelSecretMask},
+	}
+
+	// Secret settings are masked when returned
+	for _, p := range []string{path, "/library/channels/?fields=settings"} {
+		var result interface{}
+
+		rec = testHTTPRequest(w, "GET", p, "", nil)
+		json.Unmarshal(rec.Body.Bytes(), &result)
+
+		if v, ok := result.([]interface{}); ok && len(v) == 1 {
+			result = v[0]
+		}
+
+		if v, _ := result.(map[string]interface{}); v == nil || !reflect.DeepEqual(v["settings"], expected) {
+			t.Logf("\nExpected %#v\nbut got  %s", expected, rec.Body.String())
+			t.Fail()
+		}
+	}
+
+	// Masked settings sent back keep their stored values
+	rec = testHTTPRequest(w, "GET", path, "", nil)
+	if rec = testHTTPRequest(w, "PUT", path, rec.Body.String(), nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("failed to update channel: %s", rec.Body.String())
+	}
+
+	channel := w.service.backend.NewChannel()
+	if err := w.service.backend.Storage().Get("id", id, channel); err != nil {
+		t.Fatalf("failed to fetch channel: %s", err)
+	} else if v, _ := channel.Settings.GetString("url", ""); v != url {
+		t.Logf("\nExpected %q\nbut got  %q", url, v)
+		t.Fail()
+	} else if v, _ := channel.Settings["headers"].(map[string]interface{}); v["Authorization"] != "Bearer abc" {
+		t.Logf("\nExpected %q\nbut got  %#v", "Bearer abc", v)
+		t.Fail()
+	}
+}
+
+func Test_HTTP_CheckChannel(t *testing.T) {
+	w := &httpWorker{service: NewService(&config{})}
+	w.service.config.Channels.Command.Enabled = true
+
+	command := &backend.Channel{
+		Name:     "channel1",
+		Kind:     "command",
+		Settings: maputil.Map{"command": "/bin/true"},
+	}
+
+	webhook := &backend.Channel{
+		Name:     "channel2",
+		Kind:     "webhook",
+		Settings: maputil.Map{"url": "http://localhost/"},
+	}
+
+	for _, test := range []struct {
+		role     string
+		channel  *backend.Channel
+		orig     *backend.Channel
+		expected int
+	}{
+		{backend.RoleAdmin, command, nil, http.StatusOK},
+		{backend.RoleEditor, command, nil, http.StatusForbidden},
+		{backend.RoleEditor, webhook, nil, http.StatusOK},
+		{backend.RoleEditor, webhook, command, http.StatusForbidden},
+		{backend.RoleAdmin, webhook, command, http.StatusOK},
+		{backend.RoleAdmin, &backend.Channel{Name: "channel3", Kind: "command"}, nil, http.StatusBadRequest},
+	} {
+		rec := httptest.NewRecorder()
+		rw := authResponseWriter{ResponseWriter: rec, user: &backend.User{Name: "user1", Role: test.role}}
+
+		w.httpCheckChannel(rw, test.channel, test.orig)
+		if rec.Code != test.expected {
+			t.Logf("\nExpected %d\nbut got  %d (role %q, kind %q)", test.expected, rec.Code, test.role,
+				test.channel.Kind)
+			t.Fail()
+		}
+	}
+
+	// Command channels are rejected for all users if disabled
+	w.service.config.Channels.Command.Enabled = false
+
+	rec := httptest.NewRecorder()
+	rw := authResponseWriter{ResponseWriter: rec, user: &backend.User{Name: "user1", Role: backend.RoleAdmin}}
+
+	if w.httpCheckChannel(rw, command, nil) || rec.Code != http.StatusBadRequest {
+		t.Logf("\nExpected %d\nbut got  %d", http.StatusBadRequest, rec.Code)
+		t.Fail()
+	}
+}
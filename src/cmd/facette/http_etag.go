@@ -0,0 +1,94 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"facette/backend"
+
+	"github.com/facette/httputil"
+)
+
+// httpItemETag returns the entity tag of a back-end item, derived from its stored content as exposed to clients.
+func httpItemETag(item interface{}) string {
+	data, err := json.Marshal(httpMaskItem(item))
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// httpSetETag sets the response entity tag of a back-end item given its type and identifier, as stored once saved.
+func (w *httpWorker) httpSetETag(rw http.ResponseWriter, typ, id string) {
+	item, _ := w.httpBackendNewItem(typ)
+	if err := w.service.backend.Storage().Get("id", id, item); err != nil {
+		w.log.Error("failed to fetch item: %s", err)
+		return
+	}
+
+	rw.Header().Set("ETag", httpItemETag(item))
+}
+
+// httpCheckPreconditions returns whether or not the "If-Match" and "If-None-Match" request conditions are met given
+// the current state of a back-end item (nil if not existing), writing a "412 Precondition Failed" response otherwise.
+func httpCheckPreconditions(rw http.ResponseWriter, r *http.Request, item interface{}) bool {
+	etag := ""
+	if item != nil {
+		etag = httpItemETag(item)
+	}
+
+	if v := r.Header.Get("If-Match"); v != "" && !httpMatchETag(v, etag, false) ||
+		r.Header.Get("If-None-Match") == "*" && etag != "" {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrPreconditionFailed), http.StatusPreconditionFailed)
+		return false
+	}
+
+	return true
+}
+
+// httpExpectModified makes the save of a back-end item conditional to the stored item not being modified since
+// retrieved as orig, the preconditions being checked against orig prior to saving.
+func httpExpectModified(item, orig interface{}) {
+	if v, ok := item.(backend.ConditionalItem); ok {
+		v.ExpectModified(reflect.Indirect(reflect.ValueOf(orig)).FieldByName("Modified").Interface().(time.Time))
+	}
+}
+
+// httpWriteItemModified writes the response to a conditional save having failed because of the item being modified
+// in the meantime, either a "412 Precondition Failed" if requested or a "409 Conflict" otherwise.
+func httpWriteItemModified(rw http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("If-Match") != "" {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrPreconditionFailed), http.StatusPreconditionFailed)
+	} else {
+		httputil.WriteJSON(rw, httpBuildMessage(backend.ErrItemModified), http.StatusConflict)
+	}
+}
+
+// httpMatchETag returns whether or not an entity tag matches a list of entity tags as set in conditional requests
+// headers, weak comparison ignoring the tags weakness indicator.
+func httpMatchETag(list, etag string, weak bool) bool {
+	if etag == "" {
+		return false
+	}
+
+	for _, v := range strings.Split(list, ",") {
+		v = strings.TrimSpace(v)
+		if weak {
+			v = strings.TrimPrefix(v, "W/")
+		}
+
+		if v == "*" || v == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"facette/backend"
+
+	"github.com/facette/logger"
+	"github.com/facette/maputil"
+)
+
+func Test_HTTP_ETag(t *testing.T) {
+	w, cleanup := testHTTPWorker(t)
+	defer cleanup()
+
+	request := func(method, path, body string, header http.Header) *httptest.ResponseRecorder {
+		return testHTTPRequest(w, method, path, body, header)
+	}
+
+	check := func(rec *httptest.ResponseRecorder, expected int, desc string) {
+		if rec.Code != expected {
+			t.Logf("\nExpected %d\nbut got  %d (%s: %s)", expected, rec.Code, desc, rec.Body.String())
+			t.Fail()
+		}
+	}
+
+	rec := request("POST", "/library/graphs/", `{"name":"graph1"}`, nil)
+	check(rec, http.StatusCreated, "create")
+
+	path := strings.TrimPrefix(rec.Header().Get("Location"), apiPrefix)
+
+	// Entity tags depend on the requested representation
+	rec = request("GET", path, "", nil)
+	check(rec, http.StatusOK, "get")
+
+	etag := rec.Header().Get("ETag")
+
+	rec = request("GET", path+"?fields=id,name", "", nil)
+	if v := rec.Header().Get("ETag"); v == "" || v == etag {
+		t.Logf("\nExpected (entity tag other than %s)\nbut got  %q", etag, v)
+		t.Fail()
+	}
+
+	check(request("GET", path, "", http.Header{"If-None-Match": {etag}}), http.StatusNotModified, "get unchanged")
+	check(request("GET", path+"?fields=id,name", "", http.Header{"If-None-Match": {etag}}), http.StatusOK,
+		"get fields")
+
+	// Updates are rejected if the item has changed since retrieved
+	check(request("PUT", path, `{"name":"graph1","description":"desc"}`, http.Header{"If-Match": {`"stale"`}}),
+		http.StatusPreconditionFailed, "update stale")
+
+	rec = request("PUT", path, `{"name":"graph1","description":"desc"}`, http.Header{"If-Match": {etag}})
+	check(rec, http.StatusNoContent, "update")
+
+	updated := rec.Header().Get("ETag")
+	if updated == "" || updated == etag {
+		t.Logf("\nExpected (entity tag other than %s)\nbut got  %q", etag, updated)
+		t.Fail()
+	}
+
+	// Revision restoration honors preconditions and returns the restored item entity tag
+	check(request("POST", path+"/revisions/1/restore", "", http.Header{"If-Match": {etag}}),
+		http.StatusPreconditionFailed, "restore stale")
+
+	rec = request("POST", path+"/revisions/1/restore", "", http.Header{"If-Match": {updated}})
+	check(rec, http.StatusNoContent, "restore")
+
+	restored := rec.Header().Get("ETag")
+
+	rec = request("GET", path, "", nil)
+	if v := rec.Header().Get("ETag"); restored == "" || v != restored {
+		t.Logf("\nExpected %q\nbut got  %q", v, restored)
+		t.Fail()
+	}
+}
+
+// testHTTPWorker returns an HTTP worker relying on a temporary SQLite back-end, along with a function removing it.
+func testHTTPWorker(t *testing.T) (*httpWorker, func()) {
+	tmpFile, err := ioutil.TempFile("", "facette")
+	if err != nil {
+		t.Fatalf("failed to create temporary file: %s", err)
+	}
+
+	log, err := logger.NewLogger(logger.FileConfig{})
+	if err != nil {
+		t.Fatalf("failed to initialize logger: %s", err)
+	}
+
+	s := NewService(&config{})
+	s.log = log
+
+	if s.backend, err = backend.NewBackend(&maputil.Map{"driver": "sqlite", "path": tmpFile.Name()}, log); err != nil {
+		t.Fatalf("failed to initialize backend: %s", err)
+	}
+
+	return newHTTPWorker(s), func() {
+		s.backend.Close()
+		os.Remove(tmpFile.Name())
+	}
+}
+
+// testHTTPRequest executes a request against an HTTP worker router, returning the recorded response.
+func testHTTPRequest(w *httpWorker, method, path, body string, header http.Header) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, apiPrefix+path, strings.NewReader(body))
+	if body != "" {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	for key, values := range header {
+		r.Header[key] = values
+	}
+
+	rec := httptest.NewRecorder()
+	w.router.ServeHTTP(rec, r)
+
+	return rec
+}
+
+func Test_HTTP_WriteItemModified(t *testing.T) {
+	for _, test := range []struct {
+		header   http.Header
+		expected int
+	}{
+		{http.Header{}, http.StatusConflict},
+		{http.Header{"If-Match": {`"abc"`}}, http.StatusPreconditionFailed},
+	} {
+		r := httptest.NewRequest("PUT", "/", nil)
+		r.Header = test.header
+
+		rec := httptest.NewRecorder()
+
+		if httpWriteItemModified(rec, r); rec.Code != test.expected {
+			t.Logf("\nExpected %d\nbut got  %d", test.expected, rec.Code)
+			t.Fail()
+		}
+	}
+}
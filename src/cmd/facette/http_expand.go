@@ -9,6 +9,7 @@ import (
 	"facette/backend"
 
 	"github.com/facette/httputil"
+	"github.com/facette/sqlstorage"
 	"github.com/fatih/set"
 )
 
@@ -38,7 +39,7 @@ func (w *httpWorker) httpHandleExpand(rw http.ResponseWriter, r *http.Request) {
 		}
 
 		result[i] = []expandListEntry{}
-		for _, s := range w.expandSeries(s, false) {
+		for _, s := range w.expandSeries(httpOrganizationID(rw), s, false) {
 			result[i] = append(result[i], expandListEntry{s.Origin, s.Source, s.Metric})
 		}
 	}
@@ -46,22 +47,28 @@ func (w *httpWorker) httpHandleExpand(rw http.ResponseWriter, r *http.Request) {
 	httputil.WriteJSON(rw, result, http.StatusOK)
 }
 
-func (w *httpWorker) expandSeries(series *backend.Series, existOnly bool) []*backend.Series {
+func (w *httpWorker) expandSeries(organization string, series *backend.Series, existOnly bool) []*backend.Series {
 	out := []*backend.Series{}
 
+	searcher := w.service.Searcher(organization)
+
 	sourcesSet := set.New()
 	if strings.HasPrefix(series.Source, backend.GroupPrefix) {
 		id := strings.TrimPrefix(series.Source, backend.GroupPrefix)
 
 		// Request source group from back-end
 		group := backend.SourceGroup{}
-		if err := w.service.backend.Storage().Get("id", id, &group); err != nil {
+		if err := w.service.backend.Storage().Get("id", id, &group); err == nil &&
+			group.GetOrganization() != organization {
+			w.log.Warning("unable to expand %s source group: %s", id, sqlstorage.ErrItemNotFound)
+			return nil
+		} else if err != nil {
 			w.log.Warning("unable to expand %s source group: %s", id, err)
 			return nil
 		}
 
 		// Loop through sources checking for patterns matching
-		for _, s := range w.service.searcher.Sources(series.Origin, "", -1) {
+		for _, s := range searcher.Sources(series.Origin, "", -1) {
 			for _, p := range group.Patterns {
 				if filterMatch(p, s.Name) {
 					sourcesSet.Add(s.Name)
@@ -78,13 +85,17 @@ func (w *httpWorker) expandSeries(series *backend.Series, existOnly bool) []*bac
 
 		// Request metric group from back-end
 		group := backend.MetricGroup{}
-		if err := w.service.backend.Storage().Get("id", id, &group); err != nil {
+		if err := w.service.backend.Storage().Get("id", id, &group); err == nil &&
+			group.GetOrganization() != organization {
+			w.log.Warning("unable to expand %s metric group: %s", id, sqlstorage.ErrItemNotFound)
+			return nil
+		} else if err != nil {
 			w.log.Warning("unable to expand %s metric group: %s", id, err)
 			return nil
 		}
 
 		// Loop through metrics checking for patterns matching
-		for _, m := range w.service.searcher.Metrics(series.Origin, "", "", -1) {
+		for _, m := range searcher.Metrics(series.Origin, "", "", -1) {
 			// Skip if metric source does not match an existing metric
 			if existOnly && !sourcesSet.Has(m.Source().Name) {
 				continue
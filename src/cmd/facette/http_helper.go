@@ -36,6 +36,32 @@ func httpGetListParam(r *http.Request, name string, fallback []string) []string
 	return list
 }
 
+func httpGetPageBounds(count, offset, limit int) (int, int) {
+	if offset > count {
+		offset = count
+	}
+
+	if limit == 0 || offset+limit > count {
+		return offset, count
+	}
+
+	return offset, offset + limit
+}
+
+// httpForwardResponseWriter wraps the response writer of an internal sub-request, forwarding it the authenticated user
+// and the organization selected by the parent request.
+func httpForwardResponseWriter(rw, sub http.ResponseWriter) http.ResponseWriter {
+	if arw := httpAuthResponseWriter(rw); arw != nil {
+		sub = authResponseWriter{sub, arw.user, arw.token}
+	}
+
+	if org := httpOrganization(rw); org != nil {
+		sub = orgResponseWriter{sub, org}
+	}
+
+	return sub
+}
+
 func httpHandleNotFound(rw http.ResponseWriter, r *http.Request) {
 	httputil.WriteJSON(rw, httpBuildMessage(ErrUnknownEndpoint), http.StatusNotFound)
 }
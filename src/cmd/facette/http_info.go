@@ -19,6 +19,7 @@ type httpInfo struct {
 	Drivers    []string `json:"drivers"`
 	Connectors []string `json:"connectors"`
 	ReadOnly   bool     `json:"read_only,omitempty"`
+	Auth       bool     `json:"auth,omitempty"`
 }
 
 func (w *httpWorker) httpHandleInfo(rw http.ResponseWriter, r *http.Request) {
@@ -32,6 +33,7 @@ func (w *httpWorker) httpHandleInfo(rw http.ResponseWriter, r *http.Request) {
 			Drivers:    sqlstorage.Drivers(),
 			Connectors: connector.Connectors(),
 			ReadOnly:   w.service.config.ReadOnly,
+			Auth:       w.service.config.Auth.Enabled,
 		}
 	} else {
 		result = httpInfo{
@@ -42,6 +44,7 @@ func (w *httpWorker) httpHandleInfo(rw http.ResponseWriter, r *http.Request) {
 			Drivers:    sqlstorage.Drivers(),
 			Connectors: connector.Connectors(),
 			ReadOnly:   w.service.config.ReadOnly,
+			Auth:       w.service.config.Auth.Enabled,
 		}
 	}
 
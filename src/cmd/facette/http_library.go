@@ -2,8 +2,11 @@ package main
 
 import (
 	"net/http"
+	"reflect"
 
+	"github.com/facette/httproute"
 	"github.com/facette/httputil"
+	"github.com/facette/sliceutil"
 )
 
 var libraryTypes = []string{
@@ -11,17 +14,26 @@ var libraryTypes = []string{
 	"graphs",
 	"sourcegroups",
 	"metricgroups",
+	"alerts",
+	"channels",
+	"annotations",
+	"reports",
 }
 
 func (w *httpWorker) httpHandleLibraryRoot(rw http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
-	// Get item types list and information
+	// Get item types list and information, counting items belonging to the selected organization
 	result := map[string]int{}
 	for _, typ := range libraryTypes {
 		item, _ := w.httpBackendNewItem(typ)
 
-		count, err := w.service.backend.Storage().Count(item)
+		filters := make(map[string]interface{})
+		w.httpApplyOrganizationFilter(rw, item, filters)
+
+		rv := reflect.New(reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(item)), 0, 0).Type())
+
+		count, err := w.service.backend.Storage().List(rv.Interface(), filters, nil, 0, 1)
 		if err != nil {
 			w.log.Error("failed to fetch count: %s", err)
 			httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
@@ -33,3 +45,16 @@ func (w *httpWorker) httpHandleLibraryRoot(rw http.ResponseWriter, r *http.Reque
 
 	httputil.WriteJSON(rw, result, http.StatusOK)
 }
+
+// httpHandleLibraryType restricts a library endpoint handler to the library item types, other back-end items being
+// managed through their dedicated endpoints (e.g. providers and users).
+func (w *httpWorker) httpHandleLibraryType(h httproute.Handler) httproute.Handler {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		if !sliceutil.Has(libraryTypes, httproute.ContextParam(r, "type").(string)) {
+			httpHandleNotFound(rw, r)
+			return
+		}
+
+		h(rw, r)
+	}
+}
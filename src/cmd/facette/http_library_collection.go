@@ -3,18 +3,48 @@ package main
 import (
 	"net/http"
 
+	"facette/backend"
+
 	"github.com/facette/httputil"
 )
 
 func (w *httpWorker) httpHandleLibraryCollectionTree(rw http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
-	tree, err := w.service.backend.NewCollectionTree(r.URL.Query().Get("parent"))
+	tree, err := w.service.backend.NewCollectionTree(httpOrganizationID(rw), r.URL.Query().Get("parent"))
 	if err != nil {
 		w.log.Error("unable to get collections tree: %s", err)
 		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
 		return
 	}
 
+	// Remove collections hidden to the authenticated user, along with their children
+	hidden, err := w.httpHiddenItems(rw)
+	if err != nil {
+		w.log.Error("failed to fetch items: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	} else if len(hidden) > 0 {
+		tree = httpFilterCollectionTree(tree, hidden)
+	}
+
 	httputil.WriteJSON(rw, tree, http.StatusOK)
 }
+
+func httpFilterCollectionTree(tree *backend.CollectionTree, hidden map[string]bool) *backend.CollectionTree {
+	result := &backend.CollectionTree{}
+
+	for _, entry := range *tree {
+		if hidden[entry.ID] {
+			continue
+		}
+
+		if entry.Children != nil {
+			entry.Children = httpFilterCollectionTree(entry.Children, hidden)
+		}
+
+		*result = append(*result, entry)
+	}
+
+	return result
+}
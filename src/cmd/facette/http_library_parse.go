@@ -46,7 +46,8 @@ func (w *httpWorker) httpHandleLibraryParse(rw http.ResponseWriter, r *http.Requ
 
 		if req.Type == "collections" {
 			collection := backend.Collection{}
-			if err := w.service.backend.Storage().Get("id", req.ID, &collection); err == nil {
+			if err := w.service.backend.Storage().Get("id", req.ID, &collection); err == nil &&
+				collection.GetOrganization() == httpOrganizationID(rw) {
 				for _, entry := range collection.Entries {
 					paths = append(paths, w.prefix+"/library/graphs/"+entry.GraphID)
 				}
@@ -66,7 +67,7 @@ func (w *httpWorker) httpHandleLibraryParse(rw http.ResponseWriter, r *http.Requ
 			// Set remote address to internal (displayed in debugging logs)
 			r.RemoteAddr = "<internal>"
 
-			w.router.ServeHTTP(rec, r)
+			w.router.ServeHTTP(httpForwardResponseWriter(rw, rec), r)
 
 			data += rec.Body.String()
 		}
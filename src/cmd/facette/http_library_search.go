@@ -40,10 +40,19 @@ func (w *httpWorker) httpHandleLibrarySearch(rw http.ResponseWriter, r *http.Req
 		}
 	}
 
+	// Only search for items scoped to the selected organization, providers being restricted to administrators
+	user := httpAuthUser(rw)
+
 	types := []interface{}{}
 	for _, typ := range req.Types {
+		if typ == "providers" && user != nil && user.Role != backend.RoleAdmin {
+			continue
+		}
+
 		if item, ok := w.httpBackendNewItem(typ); ok {
-			types = append(types, item)
+			if _, ok := item.(backend.OrganizationItem); ok {
+				types = append(types, item)
+			}
 		}
 	}
 
@@ -68,16 +77,45 @@ func (w *httpWorker) httpHandleLibrarySearch(rw http.ResponseWriter, r *http.Req
 		}
 	}
 
+	req.Terms["organization"] = backend.OrganizationFilter(httpOrganizationID(rw))
+
+	// Retrieve items hidden to the authenticated user, requiring to paginate results once filtered
+	hidden, err := w.httpHiddenItems(rw)
+	if err != nil {
+		w.log.Error("failed to fetch items: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	searchOffset, searchLimit := offset, limit
+	if len(hidden) > 0 {
+		searchOffset, searchLimit = 0, 0
+	}
+
 	// Execute search request
 	result := []*backend.Item{}
 
-	count, err := w.service.backend.Storage().Search(types, &result, req.Terms, sort, offset, limit)
+	count, err := w.service.backend.Storage().Search(types, &result, req.Terms, sort, searchOffset, searchLimit)
 	if err != nil {
 		w.log.Error("failed to perform search: %s", err)
 		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
 		return
 	}
 
+	if len(hidden) > 0 {
+		items := []*backend.Item{}
+		for _, item := range result {
+			if !hidden[item.ID] {
+				items = append(items, item)
+			}
+		}
+
+		count = len(items)
+
+		start, end := httpGetPageBounds(count, offset, limit)
+		result = items[start:end]
+	}
+
 	rw.Header().Set("X-Total-Records", fmt.Sprintf("%d", count))
 	httputil.WriteJSON(rw, result, http.StatusOK)
 }
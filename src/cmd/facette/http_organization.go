@@ -0,0 +1,186 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"facette/backend"
+
+	"github.com/facette/httputil"
+	"github.com/facette/sqlstorage"
+	"github.com/hashicorp/go-uuid"
+)
+
+const organizationHeader = "X-Facette-Organization"
+
+// orgResponseWriter carries the organization selected by a request down to the handlers, as the router replaces the
+// requests context with the endpoints one.
+type orgResponseWriter struct {
+	http.ResponseWriter
+	org *backend.Organization
+}
+
+// httpHandleOrganization selects the organization a request applies to, given its name or identifier either set in
+// the "X-Facette-Organization" header or as a "/organizations/<name>/" path prefix. Prefixed paths are rewritten to
+// the matching endpoints, while organizations management ones are kept as is.
+func (w *httpWorker) httpHandleOrganization(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		name := r.Header.Get(organizationHeader)
+
+		if path := strings.TrimPrefix(r.URL.Path, w.prefix+"/organizations/"); path != r.URL.Path {
+			if parts := strings.SplitN(path, "/", 2); len(parts) == 2 && parts[1] != "" {
+				name = parts[0]
+				r.URL.Path = w.prefix + "/" + parts[1]
+				r.URL.RawPath = ""
+			}
+		}
+
+		// Keep organization selected by parent request if none (i.e. bulk sub-requests)
+		if name == "" {
+			h.ServeHTTP(rw, r)
+			return
+		}
+
+		org, err := w.httpGetOrganization(name)
+		if err == sqlstorage.ErrItemNotFound {
+			httputil.WriteJSON(rw, httpBuildMessage(ErrUnknownOrganization), http.StatusNotFound)
+			return
+		} else if err != nil {
+			w.log.Error("failed to fetch organization: %s", err)
+			httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+			return
+		}
+
+		h.ServeHTTP(orgResponseWriter{rw, org}, r)
+	})
+}
+
+// httpGetOrganization retrieves an organization from the back-end given its name or identifier.
+func (w *httpWorker) httpGetOrganization(name string) (*backend.Organization, error) {
+	column := "name"
+	if _, err := uuid.ParseUUID(name); err == nil {
+		column = "id"
+	}
+
+	org := w.service.backend.NewOrganization()
+	if err := w.service.backend.Storage().Get(column, name, org); err != nil {
+		return nil, err
+	}
+
+	return org, nil
+}
+
+// httpCheckOrganization returns whether or not a back-end item belongs to the organization selected by the request,
+// writing an error response otherwise. Items belonging to other organizations are reported as not found.
+func (w *httpWorker) httpCheckOrganization(rw http.ResponseWriter, item interface{}) bool {
+	if v, ok := item.(backend.OrganizationItem); ok && v.GetOrganization() != httpOrganizationID(rw) {
+		httputil.WriteJSON(rw, httpBuildMessage(sqlstorage.ErrItemNotFound), http.StatusNotFound)
+		return false
+	}
+
+	return true
+}
+
+// httpApplyOrganization sets the organization of a back-end item being saved to the one selected by the request.
+func (w *httpWorker) httpApplyOrganization(rw http.ResponseWriter, item interface{}) {
+	if v, ok := item.(backend.OrganizationItem); ok {
+		v.SetOrganization(httpOrganizationID(rw))
+	}
+}
+
+// httpApplyOrganizationFilter restricts a back-end items list filters to the organization selected by the request.
+func (w *httpWorker) httpApplyOrganizationFilter(rw http.ResponseWriter, item interface{},
+	filters map[string]interface{}) {
+
+	if _, ok := item.(backend.OrganizationItem); ok {
+		filters["organization"] = backend.OrganizationFilter(httpOrganizationID(rw))
+	}
+}
+
+// httpCheckReferences returns whether or not the items referenced by a back-end item being saved belong to the
+// organization selected by the request and are visible to the authenticated user, writing an error response
+// otherwise.
+func (w *httpWorker) httpCheckReferences(rw http.ResponseWriter, item interface{}) bool {
+	type reference struct {
+		item interface{}
+		id   *string
+	}
+
+	refs := []reference{}
+
+	switch v := item.(type) {
+	case *backend.Graph:
+		refs = append(refs, reference{w.service.backend.NewGraph(), v.LinkID})
+
+	case *backend.Collection:
+		refs = append(refs, reference{w.service.backend.NewCollection(), v.LinkID},
+			reference{w.service.backend.NewCollection(), v.ParentID})
+
+		for i := range v.Entries {
+			refs = append(refs, reference{w.service.backend.NewGraph(), &v.Entries[i].GraphID})
+		}
+
+	case *backend.AlertRule:
+		refs = append(refs, reference{w.service.backend.NewGraph(), v.GraphID})
+
+		for i := range v.Channels {
+			refs = append(refs, reference{w.service.backend.NewChannel(), &v.Channels[i]})
+		}
+
+	case *backend.Annotation:
+		refs = append(refs, reference{w.service.backend.NewGraph(), v.GraphID},
+			reference{w.service.backend.NewCollection(), v.CollectionID})
+
+	case *backend.Report:
+		refs = append(refs, reference{w.service.backend.NewCollection(), &v.CollectionID},
+			reference{w.service.backend.NewChannel(), v.ChannelID})
+	}
+
+	for _, ref := range refs {
+		if ref.id == nil || *ref.id == "" {
+			continue
+		}
+
+		// Unknown references are reported by the back-end upon saving
+		if err := w.service.backend.Storage().Get("id", *ref.id, ref.item); err == sqlstorage.ErrItemNotFound {
+			continue
+		} else if err != nil {
+			w.log.Error("failed to fetch item: %s", err)
+			httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+			return false
+		} else if ref.item.(backend.OrganizationItem).GetOrganization() != httpOrganizationID(rw) {
+			httputil.WriteJSON(rw, httpBuildMessage(sqlstorage.ErrUnknownReference), http.StatusBadRequest)
+			return false
+		} else if !w.httpCheckAccess(rw, ref.item, backend.AccessRead) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func httpOrganization(rw http.ResponseWriter) *backend.Organization {
+	for {
+		switch v := rw.(type) {
+		case orgResponseWriter:
+			return v.org
+
+		case authResponseWriter:
+			rw = v.ResponseWriter
+
+		case responseWriter:
+			rw = v.ResponseWriter
+
+		default:
+			return nil
+		}
+	}
+}
+
+func httpOrganizationID(rw http.ResponseWriter) string {
+	if org := httpOrganization(rw); org != nil {
+		return org.ID
+	}
+
+	return ""
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"bytes"
+	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -11,6 +14,8 @@ import (
 	"facette/timerange"
 
 	"github.com/facette/httputil"
+	"github.com/facette/maputil"
+	"github.com/facette/sliceutil"
 	"github.com/facette/sqlstorage"
 )
 
@@ -18,6 +23,39 @@ const (
 	defaultTimeRange = "-1h"
 )
 
+var plotConsolidations = map[string]int{
+	"average":    plot.ConsolidateAverage,
+	"first":      plot.ConsolidateFirst,
+	"last":       plot.ConsolidateLast,
+	"max":        plot.ConsolidateMax,
+	"min":        plot.ConsolidateMin,
+	"sum":        plot.ConsolidateSum,
+	"median":     plot.ConsolidateMedian,
+	"percentile": plot.ConsolidatePercentile,
+	"count":      plot.ConsolidateCount,
+	"stddev":     plot.ConsolidateStddev,
+	"envelope":   plot.ConsolidateEnvelope,
+}
+
+var plotDownsamplings = map[string]int{
+	"bucket": plot.DownsampleBucket,
+	"lttb":   plot.DownsampleLTTB,
+}
+
+const (
+	plotFormatJSON      = "json"
+	plotFormatCSV       = "csv"
+	plotFormatTSV       = "tsv"
+	plotFormatJSONLines = "jsonl"
+)
+
+var plotFormats = map[string]string{
+	plotFormatJSON:      "application/json",
+	plotFormatCSV:       "text/csv",
+	plotFormatTSV:       "text/tab-separated-values",
+	plotFormatJSONLines: "application/x-ndjson",
+}
+
 type plotQuery struct {
 	query     plot.Query
 	queryMap  [][2]int
@@ -25,8 +63,6 @@ type plotQuery struct {
 }
 
 func (w *httpWorker) httpHandlePlots(rw http.ResponseWriter, r *http.Request) {
-	var err error
-
 	defer r.Body.Close()
 
 	// Get plot request from received data
@@ -40,31 +76,99 @@ func (w *httpWorker) httpHandlePlots(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.writePlotResponse(rw, r, req)
+}
+
+func (w *httpWorker) httpHandlePlotsGet(rw http.ResponseWriter, r *http.Request) {
+	req := &plot.Request{ID: r.URL.Query().Get("id")}
+	if req.ID == "" {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return
+	}
+
+	if err := httpParsePlotQuery(r, req); err != nil {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	w.writePlotResponse(rw, r, req)
+}
+
+func (w *httpWorker) writePlotResponse(rw http.ResponseWriter, r *http.Request, req *plot.Request) {
+	format, ok := httpPlotFormat(r)
+	if !ok {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return
+	}
+
+	if !w.httpCheckGraphAccess(rw, req.ID) {
+		return
+	}
+
+	plots, err := w.executePlotRequest(req, httpOrganizationID(rw))
+	if err != nil {
+		httpWritePlotError(rw, err)
+		return
+	}
+
+	if format == plotFormatJSON {
+		httputil.WriteJSON(rw, plots, http.StatusOK)
+		return
+	}
+
+	// Export series plots in the requested tabular or line-based format
+	buf := bytes.NewBuffer(nil)
+
+	switch format {
+	case plotFormatCSV:
+		err = plots.WriteCSV(buf, ',')
+
+	case plotFormatTSV:
+		err = plots.WriteCSV(buf, '\t')
+
+	case plotFormatJSONLines:
+		err = plots.WriteJSONLines(buf)
+	}
+
+	if err != nil {
+		w.log.Error("failed to export plots: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", plotFormats[format])
+	rw.WriteHeader(http.StatusOK)
+	buf.WriteTo(rw)
+}
+
+// executePlotRequest fetches the request graph if needed, sets the request defaults from the graph options and
+// executes it, returning the plots response. Graphs are resolved and queried within the given organization.
+func (w *httpWorker) executePlotRequest(req *plot.Request, organization string) (*plot.Response, error) {
+	var err error
+
 	// Request item from backend
 	if req.ID != "" {
 		req.Graph = w.service.backend.NewGraph()
 
-		if err := w.service.backend.Storage().Get("id", req.ID, req.Graph); err == sqlstorage.ErrItemNotFound {
-			httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusNotFound)
-			return
+		if err := w.service.backend.Storage().Get("id", req.ID, req.Graph); err == sqlstorage.ErrItemNotFound ||
+			err == nil && req.Graph.GetOrganization() != organization {
+			return nil, sqlstorage.ErrItemNotFound
 		} else if err != nil {
 			w.log.Error("failed to fetch item: %s", err)
-			httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
-			return
+			return nil, ErrUnhandledError
 		}
 	} else if req.Graph != nil {
 		// Register back-end (needed for graph expansion)
 		req.Graph.Item.SetBackend(w.service.backend)
+		req.Graph.SetOrganization(organization)
 	} else {
-		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
-		return
+		return nil, ErrInvalidParameter
 	}
 
 	// Expand graph template if linked
 	if err := req.Graph.Expand(req.Attributes); err != nil {
 		w.log.Warning("%s", err)
-		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusInternalServerError)
-		return
+		return nil, err
 	}
 
 	// Set request time boundaries and range
@@ -87,20 +191,17 @@ func (w *httpWorker) httpHandlePlots(rw http.ResponseWriter, r *http.Request) {
 			req.EndTime = req.Time
 			if req.StartTime, err = timerange.Apply(req.Time, req.Range); err != nil {
 				w.log.Warning("unable to apply time range: %s", err)
-				httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
-				return
+				return nil, ErrInvalidParameter
 			}
 		} else {
 			req.StartTime = req.Time
 			if req.EndTime, err = timerange.Apply(req.Time, req.Range); err != nil {
 				w.log.Warning("unable to apply time range: %s", err)
-				httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
-				return
+				return nil, ErrInvalidParameter
 			}
 		}
 	} else if (req.StartTime.IsZero() || req.EndTime.IsZero()) || req.Range != "" {
-		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidTimerange), http.StatusBadRequest)
-		return
+		return nil, ErrInvalidTimerange
 	}
 
 	// Set default plot sample if none provided
@@ -108,14 +209,64 @@ func (w *httpWorker) httpHandlePlots(rw http.ResponseWriter, r *http.Request) {
 		req.Sample = plot.DefaultSample
 	}
 
+	// Set downsampling type from graph options if none provided
+	if req.Downsample == 0 {
+		req.Downsample = plot.DownsampleBucket
+		if v, ok := req.Graph.Options["downsample"]; ok {
+			if req.Downsample, ok = plotDownsampling(v); !ok {
+				return nil, ErrInvalidParameter
+			}
+		}
+	} else if req.Downsample != plot.DownsampleBucket && req.Downsample != plot.DownsampleLTTB {
+		return nil, ErrInvalidParameter
+	}
+
+	// Set time shifts from graph options if none provided
+	if len(req.TimeShifts) == 0 {
+		if slice, ok := req.Graph.Options["timeshifts"].([]interface{}); ok {
+			for _, entry := range slice {
+				if shift, ok := entry.(string); ok {
+					req.TimeShifts = append(req.TimeShifts, shift)
+				}
+			}
+		}
+	}
+
+	for _, shift := range req.TimeShifts {
+		if _, err := timerange.Apply(req.StartTime, shift); err != nil {
+			w.log.Warning("unable to apply time shift: %s", err)
+			return nil, ErrInvalidTimerange
+		}
+	}
+
+	// Keep a copy of the graph before executing request, as series get expanded during execution
+	graph := req.Graph.Clone()
+
 	// Execute plots request
 	plots := plot.Response{
 		Start:   req.StartTime.Format(time.RFC3339),
 		End:     req.EndTime.Format(time.RFC3339),
-		Series:  w.executeRequest(req),
 		Options: req.Graph.Options,
 	}
 
+	plots.Series, plots.Histograms = w.executeRequest(req)
+
+	// Retrieve annotations matching request time range unless disabled in graph options
+	if v, ok := req.Graph.Options["annotations"].(bool); !ok || v {
+		plots.Annotations = w.fetchAnnotations(req)
+	}
+
+	// Execute time-shifted plots requests
+	for _, shift := range req.TimeShifts {
+		series, err := w.executeShiftedRequest(req, graph.Clone(), shift)
+		if err != nil {
+			w.log.Error("failed to execute time-shifted request: %s", err)
+			continue
+		}
+
+		plots.Series = append(plots.Series, series...)
+	}
+
 	// Set fallback title to graph name if none provided
 	if plots.Options == nil {
 		plots.Options = make(map[string]interface{})
@@ -125,15 +276,116 @@ func (w *httpWorker) httpHandlePlots(rw http.ResponseWriter, r *http.Request) {
 		plots.Options["title"] = req.Graph.Name
 	}
 
-	httputil.WriteJSON(rw, plots, http.StatusOK)
+	return &plots, nil
+}
+
+// httpPlotFormat returns the plots response format either requested through the "format" parameter or negotiated
+// using the "Accept" header, falling back to JSON.
+func httpPlotFormat(r *http.Request) (string, bool) {
+	if v := r.URL.Query().Get("format"); v != "" {
+		_, ok := plotFormats[v]
+		return v, ok
+	}
+
+	for _, entry := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(entry, ";", 2)[0])
+
+		for format, contentType := range plotFormats {
+			if mediaType == contentType || format == plotFormatJSONLines && mediaType == "application/jsonl" {
+				return format, true
+			}
+		}
+	}
+
+	return plotFormatJSON, true
 }
 
-func (w *httpWorker) executeRequest(req *plot.Request) []plot.SeriesResponse {
+func httpWritePlotError(rw http.ResponseWriter, err error) {
+	switch err {
+	case sqlstorage.ErrItemNotFound:
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusNotFound)
+
+	case ErrInvalidParameter, ErrInvalidTimerange:
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadRequest)
+
+	default:
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusInternalServerError)
+	}
+}
+
+func (w *httpWorker) fetchAnnotations(req *plot.Request) []plot.Annotation {
+	tags, _ := req.Graph.Options.GetStringSlice("annotation_tags", nil)
+
+	result := []plot.Annotation{}
+
+	annotations, err := w.service.backend.Annotations(req.Graph.GetOrganization(), req.StartTime, req.EndTime,
+		req.Graph.ID, tags)
+	if err != nil {
+		w.log.Error("failed to fetch annotations: %s", err)
+	}
+
+	for _, a := range annotations {
+		result = append(result, plot.Annotation{
+			ID:    a.ID,
+			Start: a.Start,
+			End:   a.End,
+			Text:  a.Text,
+			Tags:  a.Tags,
+		})
+	}
+
+	// Retrieve annotations from connectors referenced in graph options
+	sources, _ := req.Graph.Options["annotation_sources"].([]interface{})
+	for _, entry := range sources {
+		m, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		source := maputil.Map(m)
+
+		name, _ := source.GetString("provider", "")
+		if name == "" {
+			continue
+		}
+
+		c, ok := w.service.poller.AnnotationConnector(req.Graph.GetOrganization(), name)
+		if !ok {
+			w.log.Warning("provider %q does not provide annotations", name)
+			continue
+		}
+
+		q := &plot.AnnotationQuery{
+			StartTime: req.StartTime,
+			EndTime:   req.EndTime,
+			Tags:      tags,
+		}
+
+		q.Query, _ = source.GetString("query", "")
+		if v, _ := source.GetStringSlice("tags", nil); v != nil {
+			q.Tags = v
+		}
+
+		annotations, err := c.Annotations(q)
+		if err != nil {
+			w.log.Error("failed to fetch %q provider annotations: %s", name, err)
+			continue
+		}
+
+		result = append(result, annotations...)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Start.Before(result[j].Start) })
+
+	return result
+}
+
+func (w *httpWorker) executeRequest(req *plot.Request) ([]plot.SeriesResponse, []plot.HistogramResponse) {
 	// Expand groups series
 	for _, group := range req.Graph.Groups {
 		expandedSeries := []*backend.Series{}
 		for _, series := range group.Series {
-			expandedSeries = append(expandedSeries, w.expandSeries(series, true)...)
+			expandedSeries = append(expandedSeries, w.expandSeries(req.Graph.GetOrganization(), series, true)...)
 		}
 		group.Series = expandedSeries
 	}
@@ -164,11 +416,32 @@ func (w *httpWorker) executeRequest(req *plot.Request) []plot.SeriesResponse {
 		}
 	}
 
+	// Get requested summary percentiles and extended statistics
+	percentiles := []float64{}
+	if slice, ok := req.Graph.Options["percentiles"].([]interface{}); ok {
+		for _, entry := range slice {
+			if val, ok := entry.(float64); ok {
+				percentiles = append(percentiles, val)
+			}
+		}
+	}
+
+	stats := []string{}
+	if slice, ok := req.Graph.Options["summary"].([]interface{}); ok {
+		for _, entry := range slice {
+			if val, ok := entry.(string); ok && sliceutil.Has(plot.SummaryStats, val) {
+				stats = append(stats, val)
+			}
+		}
+	}
+
 	// Generate plots series
 	result := []plot.SeriesResponse{}
+	histograms := []plot.HistogramResponse{}
 	for i, group := range req.Graph.Groups {
 		var (
 			consolidate int
+			percentile  float64
 			interpolate bool
 			err         error
 		)
@@ -185,15 +458,48 @@ func (w *httpWorker) executeRequest(req *plot.Request) []plot.SeriesResponse {
 			}
 		}
 
-		// Skip operations if none requested
+		// Generate histogram instead of series if requested
+		if group.Histogram != nil {
+			histogram, err := w.executeHistogram(req, group, data[i])
+			if err != nil {
+				w.log.Error("failed to generate histogram: %s", err)
+				continue
+			}
+
+			histograms = append(histograms, histogram)
+			continue
+		}
+
+		// Skip operations if none requested, downsampling series independently if needed
 		if group.Operator == plot.OperatorNone {
+			if req.Downsample == plot.DownsampleLTTB {
+				for j := range data[i] {
+					if data[i][j], err = plot.LTTB(data[i][j], req.StartTime, req.EndTime, req.Sample); err != nil {
+						w.log.Error("failed to downsample series: %s", err)
+						break
+					}
+				}
+			}
+
 			goto finalize
 		}
 
 		// Get group consolidation mode and interpolation options
 		consolidate = plot.ConsolidateAverage
-		if v, ok := group.Options["consolidate"].(int); ok {
-			consolidate = v
+		if group.Consolidate != 0 {
+			consolidate = group.Consolidate
+		}
+
+		if v, ok := group.Options["consolidate"]; ok {
+			if consolidate, ok = plotConsolidation(v); !ok {
+				w.log.Warning("unknown %v consolidation type", v)
+				continue
+			}
+		}
+
+		percentile = plot.DefaultPercentile
+		if v, ok := group.Options["percentile"].(float64); ok {
+			percentile = v
 		}
 
 		interpolate = true
@@ -201,24 +507,48 @@ func (w *httpWorker) executeRequest(req *plot.Request) []plot.SeriesResponse {
 			interpolate = v
 		}
 
-		// Normalize series and apply operations
-		data[i], err = plot.Normalize(data[i], req.StartTime, req.EndTime, req.Sample, consolidate, interpolate)
+		// Normalize series and apply operations (single series don't need to be aligned, thus can be downsampled
+		// keeping their original timestamps)
+		if req.Downsample == plot.DownsampleLTTB && len(data[i]) == 1 && consolidate != plot.ConsolidateEnvelope {
+			data[i][0], err = plot.LTTB(data[i][0], req.StartTime, req.EndTime, req.Sample)
+		} else {
+			data[i], err = plot.Normalize(data[i], req.StartTime, req.EndTime, req.Sample, consolidate, percentile,
+				interpolate)
+		}
+
 		if err != nil {
 			w.log.Error("failed to normalize series: %s", err)
 			continue
 		}
 
+		// Duplicate group series entries as envelope consolidation returns lower and upper bounds series
+		if consolidate == plot.ConsolidateEnvelope {
+			group.Series = plotEnvelopeSeries(group.Series)
+		}
+
 		switch group.Operator {
 		case plot.OperatorAverage, plot.OperatorSum:
 			var (
-				series plot.Series
+				series []plot.Series
 				err    error
 			)
 
-			if group.Operator == plot.OperatorAverage {
-				series, err = plot.Average(data[i])
+			// Apply operation on each bound separately in case of envelope consolidation
+			if consolidate == plot.ConsolidateEnvelope {
+				bounds := [2][]plot.Series{}
+				for j := range data[i] {
+					bounds[j%2] = append(bounds[j%2], data[i][j])
+				}
+
+				series = make([]plot.Series, 2)
+				for j := range bounds {
+					if series[j], err = plotApplyOperator(bounds[j], group.Operator); err != nil {
+						break
+					}
+				}
 			} else {
-				series, err = plot.Sum(data[i])
+				series = make([]plot.Series, 1)
+				series[0], err = plotApplyOperator(data[i], group.Operator)
 			}
 
 			if err != nil {
@@ -227,10 +557,15 @@ func (w *httpWorker) executeRequest(req *plot.Request) []plot.SeriesResponse {
 			}
 
 			// Set series name to group name
-			group.Series[0].Name = group.Name
+			if consolidate == plot.ConsolidateEnvelope {
+				group.Series = plotEnvelopeSeries([]*backend.Series{{Name: group.Name,
+					Options: group.Series[0].Options}})
+			} else {
+				group.Series[0].Name = group.Name
+			}
 
 			// Replace group series with operation result
-			data[i] = []plot.Series{series}
+			data[i] = series
 
 		case plot.OperatorNormalize:
 			// noop
@@ -251,28 +586,325 @@ func (w *httpWorker) executeRequest(req *plot.Request) []plot.SeriesResponse {
 			}
 
 			// Summarize series
-			percentiles := []float64{}
-			if slice, ok := req.Graph.Options["percentiles"].([]interface{}); ok {
-				for _, entry := range slice {
-					if val, ok := entry.(float64); ok {
-						percentiles = append(percentiles, val)
-					}
-				}
-			}
-
-			series.Summarize(percentiles)
+			series.Summarize(percentiles, stats...)
 
 			result = append(result, plot.SeriesResponse{
 				Series:  series,
 				Name:    group.Series[j].Name,
 				Options: group.Series[j].Options,
 			})
+
+			// Append series trends and anomaly detection bands if any requested
+			result = append(result, w.executeTrends(req, series, group.Series[j], percentiles, stats)...)
+			result = append(result, w.executeAnomalies(series, group.Series[j], percentiles, stats)...)
+		}
+	}
+
+	return result, histograms
+}
+
+func (w *httpWorker) executeHistogram(req *plot.Request, group *backend.SeriesGroup,
+	series []plot.Series) (plot.HistogramResponse, error) {
+
+	var (
+		histogram plot.Histogram
+		err       error
+	)
+
+	if len(group.Histogram.Bounds) > 0 {
+		// Native histogram buckets: align buckets series before building histogram
+		if series, err = plot.Normalize(series, req.StartTime, req.EndTime, req.Sample, plot.ConsolidateAverage, 0,
+			false); err != nil {
+			return plot.HistogramResponse{}, err
+		}
+
+		bounds := make([]plot.Value, len(group.Histogram.Bounds))
+		for i, v := range group.Histogram.Bounds {
+			bounds[i] = plot.Value(v)
+		}
+
+		histogram, err = plot.NewHistogramFromBuckets(series, bounds, group.Histogram.Cumulative)
+	} else {
+		buckets := plot.DefaultHistogramBuckets
+		if group.Histogram.Buckets > 0 {
+			buckets = group.Histogram.Buckets
+		}
+
+		histogram, err = plot.NewHistogram(series, req.StartTime, req.EndTime, req.Sample, buckets)
+	}
+
+	if err != nil {
+		return plot.HistogramResponse{}, err
+	}
+
+	return plot.HistogramResponse{
+		Histogram: histogram,
+		Name:      group.Name,
+		Options:   group.Options,
+	}, nil
+}
+
+func (w *httpWorker) executeTrends(req *plot.Request, series plot.Series, s *backend.Series, percentiles []float64,
+	stats []string) []plot.SeriesResponse {
+
+	var kinds []string
+
+	switch value := s.Options["trend"].(type) {
+	case string:
+		kinds = []string{value}
+
+	case []interface{}:
+		for _, entry := range value {
+			if kind, ok := entry.(string); ok {
+				kinds = append(kinds, kind)
+			}
 		}
+
+	default:
+		return nil
+	}
+
+	// Get extrapolation horizon beyond request end time
+	until := req.EndTime
+	if v, ok := s.Options["trend_horizon"].(string); ok {
+		t, err := timerange.Apply(req.EndTime, v)
+		if err != nil || t.Before(req.EndTime) {
+			w.log.Warning("invalid %q trend horizon for series %s", v, s)
+			return nil
+		}
+
+		until = t
+	}
+
+	result := []plot.SeriesResponse{}
+
+	for _, kind := range kinds {
+		var (
+			trend plot.Series
+			name  string
+			err   error
+		)
+
+		switch kind {
+		case "linear":
+			trend, err = plot.LinearTrend(series, until)
+			name = fmt.Sprintf("%s (linear trend)", s.Name)
+
+		case "holtwinters":
+			var season time.Duration
+
+			alpha, _ := s.Options.GetFloat("trend_alpha", plot.DefaultHoltWintersAlpha)
+			beta, _ := s.Options.GetFloat("trend_beta", plot.DefaultHoltWintersBeta)
+			gamma, _ := s.Options.GetFloat("trend_gamma", plot.DefaultHoltWintersGamma)
+
+			if v, ok := s.Options["trend_season"].(string); ok {
+				if season, err = plotParseDuration(v); err != nil {
+					w.log.Warning("invalid %q trend season for series %s", v, s)
+					continue
+				}
+			}
+
+			trend, err = plot.HoltWinters(series, until, alpha, beta, gamma, season)
+			name = fmt.Sprintf("%s (forecast)", s.Name)
+
+		default:
+			w.log.Warning("unknown %q trend type for series %s", kind, s)
+			continue
+		}
+
+		if err != nil {
+			w.log.Warning("failed to compute %q trend for series %s: %s", kind, s, err)
+			continue
+		}
+
+		trend.Summarize(percentiles, stats...)
+
+		result = append(result, plot.SeriesResponse{
+			Series:  trend,
+			Name:    name,
+			Options: plotDerivedOptions(s.Options, "trend", kind),
+		})
 	}
 
 	return result
 }
 
+func (w *httpWorker) executeAnomalies(series plot.Series, s *backend.Series, percentiles []float64,
+	stats []string) []plot.SeriesResponse {
+
+	var (
+		method int
+		window = plot.DefaultAnomalyWindow
+		err    error
+	)
+
+	v, ok := s.Options["anomaly"].(string)
+	if !ok {
+		return nil
+	}
+
+	switch v {
+	case "stddev":
+		method = plot.AnomalyStddev
+
+	case "mad":
+		method = plot.AnomalyMAD
+
+	default:
+		w.log.Warning("unknown %q anomaly detection method for series %s", v, s)
+		return nil
+	}
+
+	if v, ok := s.Options["anomaly_window"].(string); ok {
+		if window, err = plotParseDuration(v); err != nil {
+			w.log.Warning("invalid %q anomaly window for series %s", v, s)
+			return nil
+		}
+	}
+
+	factor, _ := s.Options.GetFloat("anomaly_factor", plot.DefaultAnomalyFactor)
+
+	bands, err := plot.Anomalies(series, method, window, factor)
+	if err != nil {
+		w.log.Warning("failed to compute anomaly bands for series %s: %s", s, err)
+		return nil
+	}
+
+	result := []plot.SeriesResponse{}
+	for _, entry := range []struct {
+		series plot.Series
+		kind   string
+		label  string
+	}{
+		{bands.Lower, "lower", "lower band"},
+		{bands.Upper, "upper", "upper band"},
+		{bands.Anomalies, "markers", "anomalies"},
+	} {
+		entry.series.Summarize(percentiles, stats...)
+
+		result = append(result, plot.SeriesResponse{
+			Series:  entry.series,
+			Name:    fmt.Sprintf("%s (%s)", s.Name, entry.label),
+			Options: plotDerivedOptions(s.Options, "anomaly", entry.kind),
+		})
+	}
+
+	return result
+}
+
+func plotApplyOperator(series []plot.Series, operator int) (plot.Series, error) {
+	if operator == plot.OperatorAverage {
+		return plot.Average(series)
+	}
+
+	return plot.Sum(series)
+}
+
+func plotConsolidation(v interface{}) (int, bool) {
+	switch value := v.(type) {
+	case float64:
+		consolidate := int(value)
+		return consolidate, consolidate >= plot.ConsolidateAverage && consolidate <= plot.ConsolidateEnvelope
+
+	case string:
+		consolidate, ok := plotConsolidations[value]
+		return consolidate, ok
+	}
+
+	return 0, false
+}
+
+func plotDerivedOptions(options maputil.Map, key string, value interface{}) map[string]interface{} {
+	// Copy source series options, removing the ones related to derived series computation
+	result := map[string]interface{}{}
+	for k, v := range options {
+		if !strings.HasPrefix(k, key) {
+			result[k] = v
+		}
+	}
+	result[key] = value
+
+	return result
+}
+
+func plotDownsampling(v interface{}) (int, bool) {
+	switch value := v.(type) {
+	case float64:
+		downsample := int(value)
+		return downsample, downsample == plot.DownsampleBucket || downsample == plot.DownsampleLTTB
+
+	case string:
+		downsample, ok := plotDownsamplings[value]
+		return downsample, ok
+	}
+
+	return 0, false
+}
+
+func plotParseDuration(v string) (time.Duration, error) {
+	// Compute duration relative to current time to handle calendar-based units
+	ref := time.Now().UTC()
+
+	t, err := timerange.Apply(ref, v)
+	if err != nil {
+		return 0, err
+	} else if t.Before(ref) {
+		return 0, timerange.ErrInvalidRange
+	}
+
+	return t.Sub(ref), nil
+}
+
+func plotEnvelopeSeries(list []*backend.Series) []*backend.Series {
+	result := make([]*backend.Series, 0, len(list)*2)
+	for _, series := range list {
+		for _, bound := range []string{"min", "max"} {
+			s := &backend.Series{}
+			*s = *series
+			s.Name = fmt.Sprintf("%s (%s)", series.Name, bound)
+
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+func (w *httpWorker) executeShiftedRequest(req *plot.Request, graph *backend.Graph,
+	shift string) ([]plot.SeriesResponse, error) {
+
+	var err error
+
+	shiftedReq := *req
+	shiftedReq.Graph = graph
+
+	if shiftedReq.StartTime, err = timerange.Apply(req.StartTime, shift); err != nil {
+		return nil, err
+	} else if shiftedReq.EndTime, err = timerange.Apply(req.EndTime, shift); err != nil {
+		return nil, err
+	}
+
+	// Align shifted series back to the current time window
+	offset := req.StartTime.Sub(shiftedReq.StartTime)
+
+	// Histograms are not part of time-shifted comparisons
+	result, _ := w.executeRequest(&shiftedReq)
+	for i := range result {
+		result[i].Shift(offset)
+		result[i].Name = fmt.Sprintf("%s (%s)", result[i].Name, shift)
+
+		options := map[string]interface{}{}
+		for k, v := range result[i].Options {
+			options[k] = v
+		}
+		options["timeshift"] = shift
+
+		result[i].Options = options
+	}
+
+	return result, nil
+}
+
 func (w *httpWorker) dispatchQueries(req *plot.Request) []plotQuery {
 	providers := make(map[string]*plotQuery)
 
@@ -283,7 +915,8 @@ func (w *httpWorker) dispatchQueries(req *plot.Request) []plotQuery {
 				continue
 			}
 
-			search := w.service.searcher.Metrics(series.Origin, series.Source, series.Metric, 1)
+			search := w.service.Searcher(req.Graph.GetOrganization()).Metrics(series.Origin, series.Source,
+				series.Metric, 1)
 			if len(search) == 0 {
 				w.log.Warning("unable to find series metric: %s", series)
 				continue
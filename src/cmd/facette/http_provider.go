@@ -23,9 +23,13 @@ func (w *httpWorker) httpHandleProviderRefresh(rw http.ResponseWriter, r *http.R
 		w.log.Error("failed to fetch item: %s", err)
 		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
 		return
+	} else if !w.httpCheckOrganization(rw, &provider) {
+		return
 	}
 
 	w.service.poller.Refresh(provider)
 
+	w.httpAudit(rw, r, backend.AuditActionRefresh, "providers", &provider, nil)
+
 	httputil.WriteJSON(rw, nil, http.StatusNoContent)
 }
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"time"
+
+	"facette/plot"
+	"facette/render"
+
+	"github.com/facette/httproute"
+	"github.com/facette/httputil"
+)
+
+func (w *httpWorker) httpHandleRenderGraph(rw http.ResponseWriter, r *http.Request) {
+	req := &plot.Request{ID: httproute.ContextParam(r, "id").(string)}
+
+	if err := httpParsePlotQuery(r, req); err != nil {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	w.renderPlotRequest(rw, r, req)
+}
+
+func (w *httpWorker) httpHandleRender(rw http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	// Get plot request from received data
+	req := &plot.Request{}
+	if err := httputil.BindJSON(r, req); err == httputil.ErrInvalidContentType {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusUnsupportedMediaType)
+		return
+	} else if err != nil {
+		w.log.Error("unable to unmarshal JSON data: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return
+	}
+
+	w.renderPlotRequest(rw, r, req)
+}
+
+func (w *httpWorker) renderPlotRequest(rw http.ResponseWriter, r *http.Request, req *plot.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = render.FormatPNG
+	} else if format != render.FormatPNG && format != render.FormatSVG {
+		httputil.WriteJSON(rw, httpBuildMessage(render.ErrUnsupportedFormat), http.StatusBadRequest)
+		return
+	}
+
+	if !w.httpCheckGraphAccess(rw, req.ID) {
+		return
+	}
+
+	plots, err := w.executePlotRequest(req, httpOrganizationID(rw))
+	if err != nil {
+		httpWritePlotError(rw, err)
+		return
+	}
+
+	// Override graph options with rendering parameters if any
+	opts := render.NewOptions(plots.Options)
+
+	if v, err := httpGetIntParam(r, "width"); err != nil {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return
+	} else if v != 0 {
+		opts.Width = v
+	}
+
+	if v, err := httpGetIntParam(r, "height"); err != nil {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return
+	} else if v != 0 {
+		opts.Height = v
+	}
+
+	if v := r.URL.Query().Get("legend"); v != "" {
+		if opts.Legend, err = strconv.ParseBool(v); err != nil {
+			httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if v := r.URL.Query().Get("title"); v != "" {
+		opts.Title = v
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := render.Render(buf, format, plots, opts); err == render.ErrInvalidSize {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		w.log.Error("failed to render graph: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", render.ContentType(format))
+	rw.WriteHeader(http.StatusOK)
+	buf.WriteTo(rw)
+}
+
+// httpParsePlotQuery sets the plot request time boundaries and sample from the request query string parameters.
+func httpParsePlotQuery(r *http.Request, req *plot.Request) error {
+	var err error
+
+	query := r.URL.Query()
+
+	for key, t := range map[string]*time.Time{
+		"time":       &req.Time,
+		"start_time": &req.StartTime,
+		"end_time":   &req.EndTime,
+	} {
+		if v := query.Get(key); v != "" {
+			if *t, err = time.Parse(time.RFC3339, v); err != nil {
+				return ErrInvalidParameter
+			}
+		}
+	}
+
+	req.Range = query.Get("range")
+
+	if req.Sample, err = httpGetIntParam(r, "sample"); err != nil {
+		return ErrInvalidParameter
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"facette/backend"
+
+	"github.com/facette/httproute"
+	"github.com/facette/httputil"
+	"github.com/facette/sqlstorage"
+)
+
+func (w *httpWorker) httpHandleReportRun(rw http.ResponseWriter, r *http.Request) {
+	id := httproute.ContextParam(r, "id").(string)
+
+	// Request item from back-end
+	report := w.service.backend.NewReport()
+	if err := w.service.backend.Storage().Get("id", id, report); err == sqlstorage.ErrItemNotFound {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		w.log.Error("failed to fetch item: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	} else if !w.httpCheckOrganization(rw, report) {
+		return
+	}
+
+	// Generate report immediately, reporting generation and delivery failures to the client
+	if err := w.service.reporter.Generate(report, time.Now()); err != nil {
+		w.log.Warning("failed to generate %q report: %s", report.Name, err)
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadGateway)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// httpCheckReport returns whether or not a report can be saved, writing an error response otherwise.
+func (w *httpWorker) httpCheckReport(rw http.ResponseWriter, report *backend.Report) bool {
+	if report.Directory != "" {
+		if _, err := w.service.ReportDirectory(report.Directory); err != nil {
+			httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadRequest)
+			return false
+		}
+	}
+
+	return true
+}
+
+// httpApplyReportOwner sets the owner of a report being saved by the authenticated user given its original state (nil
+// upon creation), the report only including the graphs visible to its owner. Only administrators can change a report
+// owner.
+func (w *httpWorker) httpApplyReportOwner(rw http.ResponseWriter, report, orig *backend.Report) {
+	user := httpAuthUser(rw)
+
+	if user == nil {
+		return
+	} else if orig == nil {
+		if user.Role != backend.RoleAdmin || report.OwnerID == nil || *report.OwnerID == "" {
+			report.OwnerID = &user.ID
+		}
+	} else if user.Role != backend.RoleAdmin {
+		report.OwnerID = orig.OwnerID
+	}
+}
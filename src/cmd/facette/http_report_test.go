@@ -0,0 +1,48 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"facette/backend"
+)
+
+func Test_HTTP_ApplyReportOwner(t *testing.T) {
+	admin := &backend.User{ID: "admin", Role: backend.RoleAdmin}
+	editor := &backend.User{ID: "editor", Role: backend.RoleEditor}
+
+	owner := func(id string) *string { return &id }
+
+	for _, test := range []struct {
+		user     *backend.User
+		owner    *string
+		orig     *string
+		expected string
+	}{
+		// Reports are owned by their creator, unless set by administrators
+		{editor, nil, nil, "editor"},
+		{editor, owner("admin"), nil, "editor"},
+		{admin, nil, nil, "admin"},
+		{admin, owner("editor"), nil, "editor"},
+
+		// Only administrators can change a report owner
+		{editor, owner("editor"), owner("user1"), "user1"},
+		{editor, nil, owner("user1"), "user1"},
+		{admin, owner("editor"), owner("user1"), "editor"},
+	} {
+		report := &backend.Report{OwnerID: test.owner}
+
+		var orig *backend.Report
+		if test.orig != nil {
+			orig = &backend.Report{OwnerID: test.orig}
+		}
+
+		rw := authResponseWriter{ResponseWriter: httptest.NewRecorder(), user: test.user}
+
+		(&httpWorker{}).httpApplyReportOwner(rw, report, orig)
+		if report.OwnerID == nil || *report.OwnerID != test.expected {
+			t.Logf("\nExpected %q\nbut got  %v", test.expected, report.OwnerID)
+			t.Fail()
+		}
+	}
+}
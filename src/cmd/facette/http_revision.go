@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"facette/backend"
+
+	"github.com/facette/httproute"
+	"github.com/facette/httputil"
+	"github.com/facette/jsonutil"
+	"github.com/facette/sliceutil"
+	"github.com/facette/sqlstorage"
+)
+
+// revisionTypes represents the back-end item types whose revisions are kept upon saving.
+var revisionTypes = []string{
+	"providers",
+	"collections",
+	"graphs",
+	"sourcegroups",
+	"metricgroups",
+}
+
+func (w *httpWorker) httpHandleRevisionList(rw http.ResponseWriter, r *http.Request) {
+	item, ok := w.httpRevisionItem(rw, r, backend.AccessRead)
+	if !ok {
+		return
+	}
+
+	offset, err := httpGetIntParam(r, "offset")
+	if err != nil || offset < 0 {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return
+	}
+
+	limit, err := httpGetIntParam(r, "limit")
+	if err != nil || limit < 0 {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return
+	}
+
+	// Request item revisions list from back-end, most recent first
+	revs := []*backend.Revision{}
+
+	count, err := w.service.backend.Storage().List(&revs, map[string]interface{}{
+		"item": reflect.Indirect(reflect.ValueOf(item)).FieldByName("ID").String(),
+	}, []string{"-number"}, offset, limit)
+	if err != nil {
+		w.log.Error("failed to fetch items: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	fields := httpGetListParam(r, "fields", []string{"id", "number", "author", "created"})
+
+	result := []map[string]interface{}{}
+	for _, rev := range revs {
+		result = append(result, jsonutil.FilterStruct(rev, fields))
+	}
+
+	rw.Header().Set("X-Total-Records", fmt.Sprintf("%d", count))
+	httputil.WriteJSON(rw, result, http.StatusOK)
+}
+
+func (w *httpWorker) httpHandleRevisionGet(rw http.ResponseWriter, r *http.Request) {
+	item, ok := w.httpRevisionItem(rw, r, backend.AccessRead)
+	if !ok {
+		return
+	}
+
+	rev, ok := w.httpGetRevision(rw, item, httproute.ContextParam(r, "revision").(string))
+	if !ok {
+		return
+	}
+
+	httputil.WriteJSON(rw, rev, http.StatusOK)
+}
+
+func (w *httpWorker) httpHandleRevisionDiff(rw http.ResponseWriter, r *http.Request) {
+	item, ok := w.httpRevisionItem(rw, r, backend.AccessRead)
+	if !ok {
+		return
+	}
+
+	rev, ok := w.httpGetRevision(rw, item, httproute.ContextParam(r, "revision").(string))
+	if !ok {
+		return
+	}
+
+	// Compare with the given revision, or with the previous one if none (the first revision being compared with an
+	// empty one)
+	prev := &backend.Revision{}
+
+	if from := r.URL.Query().Get("from"); from != "" {
+		if prev, ok = w.httpGetRevision(rw, item, from); !ok {
+			return
+		}
+	} else if rev.Number > 1 {
+		if prev, ok = w.httpGetRevision(rw, item, strconv.Itoa(rev.Number-1)); !ok {
+			return
+		}
+	}
+
+	httputil.WriteJSON(rw, rev.Diff(prev), http.StatusOK)
+}
+
+func (w *httpWorker) httpHandleRevisionRestore(rw http.ResponseWriter, r *http.Request) {
+	if w.service.config.ReadOnly {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrReadOnly), http.StatusForbidden)
+		return
+	}
+
+	typ := httproute.ContextParam(r, "type").(string)
+	id := httproute.ContextParam(r, "id").(string)
+
+	orig, ok := w.httpRevisionItem(rw, r, backend.AccessWrite)
+	if !ok || !httpCheckPreconditions(rw, r, orig) {
+		return
+	}
+
+	rev, ok := w.httpGetRevision(rw, orig, httproute.ContextParam(r, "revision").(string))
+	if !ok {
+		return
+	}
+
+	// Fill item with revision data, keeping its current identifier and access control list
+	item, _ := w.httpBackendNewItem(typ)
+	if err := rev.Decode(item); err != nil {
+		w.log.Error("failed to decode revision: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	reflect.Indirect(reflect.ValueOf(item)).FieldByName("ID").SetString(id)
+
+	if v, ok := item.(backend.ACLItem); ok {
+		*v.GetACL() = *orig.(backend.ACLItem).GetACL().Clone()
+	}
+
+	w.httpApplyOrganization(rw, item)
+
+	if !w.httpCheckReferences(rw, item) {
+		return
+	}
+
+	httpExpectModified(item, orig)
+
+	// Update item in back-end
+	if err := w.service.backend.Storage().Save(item); err != nil {
+		switch err {
+		case sqlstorage.ErrItemConflict:
+			httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusConflict)
+
+		case backend.ErrItemModified:
+			httpWriteItemModified(rw, r)
+
+		case sqlstorage.ErrUnknownReference:
+			httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadRequest)
+
+		default:
+			w.log.Error("failed to update item: %s", err)
+			httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	w.httpSaveRevision(rw, typ, item)
+
+	w.log.Debug("restored %s item to revision %d", id, rev.Number)
+
+	w.httpSetETag(rw, typ, id)
+
+	w.httpAudit(rw, r, backend.AuditActionUpdate, typ, item, orig)
+
+	// Restart provider on update
+	if typ == "providers" {
+		provider := w.service.backend.NewProvider()
+		if err := w.service.backend.Storage().Get("id", id, provider); err == nil {
+			go w.service.poller.StopProvider(provider, true)
+		}
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// httpRevisionItem retrieves the back-end item whose revisions are requested, checking whether or not the
+// authenticated user is given an access to it.
+func (w *httpWorker) httpRevisionItem(rw http.ResponseWriter, r *http.Request, access string) (interface{}, bool) {
+	typ := httproute.ContextParam(r, "type").(string)
+	id := httproute.ContextParam(r, "id").(string)
+
+	if !sliceutil.Has(revisionTypes, typ) {
+		rw.WriteHeader(http.StatusNotFound)
+		return nil, false
+	}
+
+	item, _ := w.httpBackendNewItem(typ)
+
+	if err := w.service.backend.Storage().Get("id", id, item); err == sqlstorage.ErrItemNotFound {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusNotFound)
+		return nil, false
+	} else if err != nil {
+		w.log.Error("failed to fetch item: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return nil, false
+	} else if !w.httpCheckOrganization(rw, item) || !w.httpCheckAccess(rw, item, access) {
+		return nil, false
+	}
+
+	return item, true
+}
+
+// httpGetRevision retrieves a back-end item revision given its number.
+func (w *httpWorker) httpGetRevision(rw http.ResponseWriter, item interface{}, number string) (*backend.Revision,
+	bool) {
+
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 1 {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return nil, false
+	}
+
+	revs := []*backend.Revision{}
+
+	if _, err := w.service.backend.Storage().List(&revs, map[string]interface{}{
+		"item":   reflect.Indirect(reflect.ValueOf(item)).FieldByName("ID").String(),
+		"number": n,
+	}, nil, 0, 1); err != nil {
+		w.log.Error("failed to fetch item: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return nil, false
+	} else if len(revs) == 0 {
+		httputil.WriteJSON(rw, httpBuildMessage(sqlstorage.ErrItemNotFound), http.StatusNotFound)
+		return nil, false
+	}
+
+	return revs[0], true
+}
+
+// httpSaveRevision stores a new revision of a back-end item saved by the authenticated user, if its type supports
+// revisions. Failing to do so is logged, as the item itself has already been saved.
+func (w *httpWorker) httpSaveRevision(rw http.ResponseWriter, typ string, item interface{}) {
+	if !sliceutil.Has(revisionTypes, typ) {
+		return
+	}
+
+	author := ""
+	if user := httpAuthUser(rw); user != nil {
+		author = user.Name
+	}
+
+	if _, err := w.service.backend.SaveRevision(typ, item, author); err != nil {
+		w.log.Error("failed to save item revision: %s", err)
+	}
+}
+
+// httpDeleteRevisions removes the revisions of a deleted back-end item, if its type supports revisions. Revisions of
+// items moved to trash are kept until purged.
+func (w *httpWorker) httpDeleteRevisions(typ, id string) {
+	if !sliceutil.Has(revisionTypes, typ) || w.httpTrashable(typ) {
+		return
+	}
+
+	if err := w.service.backend.DeleteRevisions(id); err != nil {
+		w.log.Error("failed to delete item revisions: %s", err)
+	}
+}
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"facette/backend"
+	"facette/plot"
+
+	"github.com/facette/httproute"
+	"github.com/facette/httputil"
+	"github.com/facette/jsonutil"
+	"github.com/facette/maputil"
+	"github.com/facette/sliceutil"
+	"github.com/facette/sqlstorage"
+)
+
+// snapshotTypes represents the back-end item types snapshots can be taken from.
+var snapshotTypes = []string{
+	"collections",
+	"graphs",
+}
+
+type snapshotRequest struct {
+	Type       string      `json:"type"`
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	StartTime  time.Time   `json:"start_time"`
+	EndTime    time.Time   `json:"end_time"`
+	Time       time.Time   `json:"time"`
+	Range      string      `json:"range"`
+	Attributes maputil.Map `json:"attributes"`
+	Expires    *time.Time  `json:"expires"`
+}
+
+type snapshotResponse struct {
+	*backend.Snapshot
+	URL string `json:"url"`
+}
+
+// snapshotPublicResponse represents a snapshot as publicly exposed, its author and original item being omitted.
+type snapshotPublicResponse struct {
+	Name     string               `json:"name"`
+	ItemType string               `json:"item_type"`
+	Data     backend.SnapshotData `json:"data"`
+	Created  time.Time            `json:"created"`
+	Expires  *time.Time           `json:"expires,omitempty"`
+}
+
+// snapshotPlots represents the frozen plots of a snapshot graph, or the error encountered while requesting them.
+type snapshotPlots struct {
+	*plot.Response
+	Error string `json:"error,omitempty"`
+}
+
+func (w *httpWorker) httpHandleSnapshotList(rw http.ResponseWriter, r *http.Request) {
+	filters := map[string]interface{}{}
+	w.httpApplyOrganizationFilter(rw, &backend.Snapshot{}, filters)
+
+	// Only administrators can see the snapshots taken by other users
+	if user := httpAuthUser(rw); user != nil && user.Role != backend.RoleAdmin {
+		filters["author"] = user.Name
+	}
+
+	offset, err := httpGetIntParam(r, "offset")
+	if err != nil || offset < 0 {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return
+	}
+
+	limit, err := httpGetIntParam(r, "limit")
+	if err != nil || limit < 0 {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return
+	}
+
+	// Request snapshots list from back-end, most recent first
+	snapshots := []*backend.Snapshot{}
+
+	count, err := w.service.backend.Storage().List(&snapshots, filters, []string{"-created"}, offset, limit)
+	if err != nil {
+		w.log.Error("failed to fetch items: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	fields := httpGetListParam(r, "fields", []string{"id", "item", "item_type", "name", "author", "created",
+		"expires"})
+
+	result := []map[string]interface{}{}
+	for _, snapshot := range snapshots {
+		result = append(result, jsonutil.FilterStruct(snapshot, fields))
+	}
+
+	rw.Header().Set("X-Total-Records", fmt.Sprintf("%d", count))
+	httputil.WriteJSON(rw, result, http.StatusOK)
+}
+
+func (w *httpWorker) httpHandleSnapshotCreate(rw http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	if w.service.config.ReadOnly {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrReadOnly), http.StatusForbidden)
+		return
+	}
+
+	// Get snapshot request from received data
+	req := snapshotRequest{}
+	if err := httputil.BindJSON(r, &req); err == httputil.ErrInvalidContentType {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusUnsupportedMediaType)
+		return
+	} else if err != nil {
+		w.log.Error("unable to unmarshal JSON data: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidJSON), http.StatusBadRequest)
+		return
+	} else if !sliceutil.Has(snapshotTypes, req.Type) || req.Expires != nil && !req.Expires.After(time.Now()) {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return
+	}
+
+	// Request item from back-end
+	item, _ := w.httpBackendNewItem(req.Type)
+
+	if err := w.service.backend.Storage().Get("id", req.ID, item); err == sqlstorage.ErrItemNotFound {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		w.log.Error("failed to fetch item: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	} else if !w.httpCheckOrganization(rw, item) || !w.httpCheckAccess(rw, item, backend.AccessRead) {
+		return
+	}
+
+	snapshot, secret, err := backend.NewSnapshot(req.Type, item)
+	if err != nil {
+		w.log.Error("failed to generate snapshot: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	if req.Name != "" {
+		snapshot.Name = req.Name
+	}
+
+	snapshot.Expires = req.Expires
+
+	if user := httpAuthUser(rw); user != nil {
+		snapshot.Author = &user.Name
+	}
+
+	// Share the same reference time between all the snapshot graphs
+	if req.StartTime.IsZero() && req.EndTime.IsZero() && req.Time.IsZero() {
+		req.Time = time.Now().UTC()
+	}
+
+	// Freeze expanded item along with its graphs plots
+	plots := []interface{}{}
+
+	switch v := item.(type) {
+	case *backend.Graph:
+		preq := req.plotRequest(v.ID, req.Attributes)
+
+		resp, err := w.executePlotRequest(preq, httpOrganizationID(rw))
+		if err != nil {
+			httpWritePlotError(rw, err)
+			return
+		}
+
+		item = preq.Graph
+		plots = append(plots, snapshotPlots{Response: resp})
+
+	case *backend.Collection:
+		if err := v.Expand(req.Attributes); err != nil {
+			w.log.Error("failed to expand collection: %s", err)
+			httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+			return
+		}
+
+		hidden, err := w.httpHiddenItems(rw)
+		if err != nil {
+			w.log.Error("failed to fetch items: %s", err)
+			httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+			return
+		}
+
+		entries := []*backend.CollectionEntry{}
+
+		for _, entry := range v.Entries {
+			// Skip graphs the authenticated user isn't allowed to see
+			if hidden[entry.GraphID] {
+				continue
+			}
+
+			attrs := maputil.Map{}
+			attrs.Merge(v.Attributes, true)
+			attrs.Merge(entry.Attributes, true)
+
+			resp, err := w.executePlotRequest(req.plotRequest(entry.GraphID, attrs), httpOrganizationID(rw))
+			if err != nil {
+				w.log.Warning("failed to execute %q snapshot graph request: %s", snapshot.Name, err)
+				plots = append(plots, snapshotPlots{Error: err.Error()})
+			} else {
+				// Entry title takes precedence over the graph one
+				if title, ok := entry.Options["title"].(string); ok && title != "" {
+					resp.Options["title"] = title
+				}
+
+				plots = append(plots, snapshotPlots{Response: resp})
+			}
+
+			entries = append(entries, entry)
+		}
+
+		v.Entries = entries
+	}
+
+	if err := snapshot.SetData(item, plots); err != nil {
+		w.log.Error("failed to marshal snapshot data: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	// Insert snapshot into back-end
+	if err := w.service.backend.Storage().Save(snapshot); err != nil {
+		switch err {
+		case backend.ErrInvalidName, sqlstorage.ErrMissingField:
+			httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadRequest)
+
+		default:
+			w.log.Error("failed to insert item: %s", err)
+			httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	w.log.Debug("inserted %q snapshot into backend", snapshot.ID)
+
+	// Only record snapshot metadata into audit log, as its data might be large
+	meta := *snapshot
+	meta.Data = backend.SnapshotData{}
+
+	w.httpAudit(rw, r, backend.AuditActionCreate, "snapshots", &meta, nil)
+
+	// Snapshot secret is only returned once as part of its public URL, as only its hash is stored
+	httputil.WriteJSON(rw, snapshotResponse{Snapshot: snapshot, URL: w.prefix + "/snapshots/public/" + secret},
+		http.StatusCreated)
+}
+
+func (w *httpWorker) httpHandleSnapshotGet(rw http.ResponseWriter, r *http.Request) {
+	snapshot, ok := w.httpGetSnapshot(rw, r)
+	if !ok {
+		return
+	}
+
+	httputil.WriteJSON(rw, snapshot, http.StatusOK)
+}
+
+func (w *httpWorker) httpHandleSnapshotDelete(rw http.ResponseWriter, r *http.Request) {
+	if w.service.config.ReadOnly {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrReadOnly), http.StatusForbidden)
+		return
+	}
+
+	snapshot, ok := w.httpGetSnapshot(rw, r)
+	if !ok {
+		return
+	}
+
+	if err := w.service.backend.Storage().Delete(snapshot); err != nil {
+		w.log.Error("failed to delete item: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	w.log.Debug("deleted %s snapshot from back-end", snapshot.ID)
+
+	w.httpAudit(rw, r, backend.AuditActionDelete, "snapshots", snapshot, nil)
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (w *httpWorker) httpHandleSnapshotPublic(rw http.ResponseWriter, r *http.Request) {
+	secret := httproute.ContextParam(r, "secret").(string)
+
+	snapshot := &backend.Snapshot{}
+	if err := w.service.backend.Storage().Get("hash", backend.HashToken(secret),
+		snapshot); err == sqlstorage.ErrItemNotFound {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		w.log.Error("failed to fetch item: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	// Remove expired snapshot upon access
+	if snapshot.Expired(time.Now()) {
+		if err := w.service.backend.Storage().Delete(snapshot); err != nil {
+			w.log.Error("failed to delete expired snapshot: %s", err)
+		}
+
+		httputil.WriteJSON(rw, httpBuildMessage(sqlstorage.ErrItemNotFound), http.StatusNotFound)
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "json":
+		httputil.WriteJSON(rw, snapshotPublicResponse{
+			Name:     snapshot.Name,
+			ItemType: snapshot.ItemType,
+			Data:     snapshot.Data,
+			Created:  snapshot.Created,
+			Expires:  snapshot.Expires,
+		}, http.StatusOK)
+
+	case "", "html":
+		w.writeSnapshotHTML(rw, snapshot)
+
+	default:
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+	}
+}
+
+// writeSnapshotHTML renders a snapshot frozen graphs plots into an HTML page, using the reports layout.
+func (w *httpWorker) writeSnapshotHTML(rw http.ResponseWriter, snapshot *backend.Snapshot) {
+	item := struct {
+		Name string `json:"name"`
+	}{}
+
+	if err := json.Unmarshal(snapshot.Data.Item, &item); err != nil {
+		w.log.Error("failed to unmarshal snapshot data: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	data := reportData{
+		Name:       snapshot.Name,
+		Collection: item.Name,
+		Generated:  snapshot.Created.Format(time.RFC1123),
+		Stats:      reportStats,
+	}
+
+	for _, raw := range snapshot.Data.Plots {
+		plots := snapshotPlots{}
+		if err := json.Unmarshal(raw, &plots); err != nil {
+			w.log.Error("failed to unmarshal snapshot data: %s", err)
+			httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+			return
+		} else if plots.Response == nil {
+			data.Graphs = append(data.Graphs, reportGraph{Error: plots.Error})
+			continue
+		}
+
+		if data.Start == "" {
+			data.Start, data.End = plots.Start, plots.End
+		}
+
+		data.Graphs = append(data.Graphs, newReportGraph(plots.Response))
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := reportTemplate.Execute(buf, data); err != nil {
+		w.log.Error("failed to render snapshot: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	rw.WriteHeader(http.StatusOK)
+	buf.WriteTo(rw)
+}
+
+// httpGetSnapshot retrieves a back-end snapshot, checking whether or not the authenticated user is given an access to
+// it. Only administrators can access the snapshots taken by other users.
+func (w *httpWorker) httpGetSnapshot(rw http.ResponseWriter, r *http.Request) (*backend.Snapshot, bool) {
+	snapshot := &backend.Snapshot{}
+
+	if err := w.service.backend.Storage().Get("id", httproute.ContextParam(r, "id").(string),
+		snapshot); err == sqlstorage.ErrItemNotFound {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusNotFound)
+		return nil, false
+	} else if err != nil {
+		w.log.Error("failed to fetch item: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return nil, false
+	} else if !w.httpCheckOrganization(rw, snapshot) {
+		return nil, false
+	}
+
+	if user := httpAuthUser(rw); user != nil && user.Role != backend.RoleAdmin &&
+		(snapshot.Author == nil || *snapshot.Author != user.Name) {
+		httputil.WriteJSON(rw, httpBuildMessage(sqlstorage.ErrItemNotFound), http.StatusNotFound)
+		return nil, false
+	}
+
+	return snapshot, true
+}
+
+// plotRequest returns the plots request of a snapshot graph given its identifier and attributes.
+func (req snapshotRequest) plotRequest(id string, attrs maputil.Map) *plot.Request {
+	return &plot.Request{
+		ID:         id,
+		StartTime:  req.StartTime,
+		EndTime:    req.EndTime,
+		Time:       req.Time,
+		Range:      req.Range,
+		Attributes: attrs,
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/facette/logger"
+)
+
+// tlsCheckInterval represents the minimum interval between two checks of the certificate files modification.
+const tlsCheckInterval = 10 * time.Second
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// httpInitTLS initializes the TLS configuration of the HTTP listener, returning nil if no certificate is configured.
+func (w *httpWorker) httpInitTLS() (*tls.Config, error) {
+	cfg := w.service.config.TLS
+
+	if cfg.CertFile == "" && cfg.KeyFile == "" {
+		return nil, nil
+	} else if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, fmt.Errorf("both TLS certificate and key files must be provided")
+	}
+
+	version, ok := tlsVersions[cfg.MinVersion]
+	if !ok {
+		return nil, fmt.Errorf("unsupported TLS minimum version %q", cfg.MinVersion)
+	}
+
+	loader := newTLSCertificateLoader(cfg.CertFile, cfg.KeyFile, w.log)
+	if err := loader.load(); err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		MinVersion:     version,
+		GetCertificate: loader.GetCertificate,
+		NextProtos:     []string{"http/1.1"},
+	}
+
+	// Negotiate HTTP/2 using ALPN, the HTTP server handling it natively once advertised
+	if cfg.HTTP2 {
+		config.NextProtos = append([]string{"h2"}, config.NextProtos...)
+	}
+
+	// Require clients to present a certificate signed by the given authority if any (i.e. mutual TLS)
+	if cfg.ClientCAFile != "" {
+		data, err := ioutil.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		config.ClientCAs = x509.NewCertPool()
+		if !config.ClientCAs.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no valid certificate found in %q", cfg.ClientCAFile)
+		}
+
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return config, nil
+}
+
+// tlsCertificateLoader loads the TLS certificate served by the HTTP listener, reloading it from disk whenever its
+// files are modified. Failing to reload it is logged, the previously loaded certificate being kept.
+type tlsCertificateLoader struct {
+	sync.Mutex
+
+	certFile string
+	keyFile  string
+	log      *logger.Logger
+	cert     *tls.Certificate
+	modTime  time.Time
+	checked  time.Time
+}
+
+func newTLSCertificateLoader(certFile, keyFile string, log *logger.Logger) *tlsCertificateLoader {
+	return &tlsCertificateLoader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		log:      log,
+	}
+}
+
+// GetCertificate returns the current TLS certificate, as expected by the TLS configuration callback.
+func (l *tlsCertificateLoader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	if now := time.Now(); now.Sub(l.checked) >= tlsCheckInterval {
+		l.checked = now
+
+		if modTime, err := l.lastModified(); err != nil {
+			l.log.Error("failed to check TLS certificate: %s", err)
+		} else if modTime.After(l.modTime) {
+			if err := l.reload(modTime); err != nil {
+				l.log.Error("failed to reload TLS certificate: %s", err)
+			} else {
+				l.log.Info("reloaded TLS certificate from %q", l.certFile)
+			}
+		}
+	}
+
+	return l.cert, nil
+}
+
+func (l *tlsCertificateLoader) load() error {
+	l.Lock()
+	defer l.Unlock()
+
+	modTime, err := l.lastModified()
+	if err != nil {
+		return err
+	}
+
+	l.checked = time.Now()
+
+	return l.reload(modTime)
+}
+
+func (l *tlsCertificateLoader) reload(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+	if err != nil {
+		return err
+	}
+
+	l.cert = &cert
+	l.modTime = modTime
+
+	return nil
+}
+
+// lastModified returns the most recent modification time of the certificate and key files.
+func (l *tlsCertificateLoader) lastModified() (time.Time, error) {
+	var result time.Time
+
+	for _, path := range []string{l.certFile, l.keyFile} {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if fi.ModTime().After(result) {
+			result = fi.ModTime()
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/facette/logger"
+)
+
+func Test_HTTP_InitTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "facette")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca, caKey := testTLSKeyPair(t, dir, "ca", nil, nil)
+	testTLSKeyPair(t, dir, "server", ca, caKey)
+
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	caFile := filepath.Join(dir, "ca.crt")
+
+	for _, test := range []struct {
+		config   tlsConfig
+		expected bool
+	}{
+		{tlsConfig{MinVersion: "1.2"}, true},
+		{tlsConfig{CertFile: certFile, MinVersion: "1.2"}, false},
+		{tlsConfig{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.4"}, false},
+		{tlsConfig{CertFile: certFile, KeyFile: filepath.Join(dir, "unknown.key"), MinVersion: "1.2"}, false},
+		{tlsConfig{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.2", ClientCAFile: keyFile}, false},
+		{tlsConfig{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.2", ClientCAFile: caFile}, true},
+	} {
+		w := testTLSWorker(test.config)
+
+		if _, err := w.httpInitTLS(); (err == nil) != test.expected {
+			t.Logf("\nExpected success %v\nbut got  %v (%#v)", test.expected, err, test.config)
+			t.Fail()
+		}
+	}
+
+	// Ensure no TLS configuration is returned if no certificate is configured
+	if config, _ := testTLSWorker(tlsConfig{MinVersion: "1.2"}).httpInitTLS(); config != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", config)
+		t.Fail()
+	}
+
+	config, err := testTLSWorker(tlsConfig{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.2", HTTP2: true}).
+		httpInitTLS()
+	if err != nil {
+		t.Fatalf("failed to initialize TLS: %s", err)
+	} else if config.MinVersion != tls.VersionTLS12 {
+		t.Logf("\nExpected %d\nbut got  %d", tls.VersionTLS12, config.MinVersion)
+		t.Fail()
+	} else if len(config.NextProtos) == 0 || config.NextProtos[0] != "h2" {
+		t.Logf("\nExpected \"h2\" protocol first\nbut got  %v", config.NextProtos)
+		t.Fail()
+	} else if config.ClientAuth != tls.NoClientCert {
+		t.Logf("\nExpected %v\nbut got  %v", tls.NoClientCert, config.ClientAuth)
+		t.Fail()
+	}
+}
+
+func Test_HTTP_InitTLS_ClientAuth(t *testing.T) {
+	dir, err := ioutil.TempDir("", "facette")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca, caKey := testTLSKeyPair(t, dir, "ca", nil, nil)
+	testTLSKeyPair(t, dir, "server", ca, caKey)
+	testTLSKeyPair(t, dir, "client", ca, caKey)
+
+	otherCA, otherKey := testTLSKeyPair(t, dir, "other", nil, nil)
+	testTLSKeyPair(t, dir, "client2", otherCA, otherKey)
+
+	config, err := testTLSWorker(tlsConfig{
+		CertFile:     filepath.Join(dir, "server.crt"),
+		KeyFile:      filepath.Join(dir, "server.key"),
+		MinVersion:   "1.2",
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+	}).httpInitTLS()
+	if err != nil {
+		t.Fatalf("failed to initialize TLS: %s", err)
+	} else if config.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Logf("\nExpected %v\nbut got  %v", tls.RequireAndVerifyClientCert, config.ClientAuth)
+		t.Fail()
+	} else if config.ClientCAs == nil {
+		t.Logf("\nExpected (non-nil .ClientCAs)\nbut got  <nil>")
+		t.Fail()
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+	server.TLS = config
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	for _, test := range []struct {
+		client   string
+		expected bool
+	}{
+		{"", false},
+		{"client2", false},
+		{"client", true},
+	} {
+		clientConfig := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+
+		if test.client != "" {
+			cert, err := tls.LoadX509KeyPair(filepath.Join(dir, test.client+".crt"),
+				filepath.Join(dir, test.client+".key"))
+			if err != nil {
+				t.Fatalf("failed to load client certificate: %s", err)
+			}
+
+			// Always present the client certificate, even if not signed by an authority accepted by the server
+			clientConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				return &cert, nil
+			}
+		}
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientConfig}}
+
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		if (err == nil) != test.expected {
+			t.Logf("\nExpected success %v\nbut got  %v (client %q)", test.expected, err, test.client)
+			t.Fail()
+		}
+	}
+}
+
+func Test_HTTP_TLSCertificateLoader_Reload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "facette")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+
+	cert1, _ := testTLSKeyPair(t, dir, "server", nil, nil)
+
+	loader := newTLSCertificateLoader(certFile, keyFile, testTLSWorker(tlsConfig{}).log)
+	if err := loader.load(); err != nil {
+		t.Fatalf("failed to load certificate: %s", err)
+	}
+
+	testTLSCheckCertificate(t, loader, cert1)
+
+	// Replace certificate files, keeping the current certificate until the next check
+	cert2, _ := testTLSKeyPair(t, dir, "server", nil, nil)
+	testTLSTouch(t, time.Minute, certFile, keyFile)
+
+	testTLSCheckCertificate(t, loader, cert1)
+
+	loader.checked = time.Time{}
+	testTLSCheckCertificate(t, loader, cert2)
+
+	// Keep the previous certificate if the new files are invalid
+	if err := ioutil.WriteFile(certFile, []byte("invalid"), 0644); err != nil {
+		t.Fatalf("failed to write certificate: %s", err)
+	}
+	testTLSTouch(t, 2*time.Minute, certFile, keyFile)
+
+	loader.checked = time.Time{}
+	testTLSCheckCertificate(t, loader, cert2)
+
+	// Keep the previous certificate if the files are missing
+	os.Remove(keyFile)
+
+	loader.checked = time.Time{}
+	testTLSCheckCertificate(t, loader, cert2)
+
+	// Reload once the files are fixed
+	cert3, _ := testTLSKeyPair(t, dir, "server", nil, nil)
+	testTLSTouch(t, 3*time.Minute, certFile, keyFile)
+
+	loader.checked = time.Time{}
+	testTLSCheckCertificate(t, loader, cert3)
+}
+
+func testTLSWorker(cfg tlsConfig) *httpWorker {
+	l, err := logger.NewLogger(logger.FileConfig{})
+	if err != nil {
+		panic("failed to initialize logger")
+	}
+
+	return &httpWorker{service: NewService(&config{TLS: cfg}), log: l}
+}
+
+// testTLSKeyPair generates a certificate and its private key into the "<name>.crt" and "<name>.key" files of a
+// directory, signed by the given authority or self-signed as an authority if none.
+func testTLSKeyPair(t *testing.T, dir, name string, ca *x509.Certificate, caKey *ecdsa.PrivateKey) (*x509.Certificate,
+	*ecdsa.PrivateKey) {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		t.Fatalf("failed to generate serial number: %s", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	if ca == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+		ca, caKey = tmpl, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %s", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %s", err)
+	}
+
+	for path, block := range map[string]*pem.Block{
+		filepath.Join(dir, name+".crt"): {Type: "CERTIFICATE", Bytes: der},
+		filepath.Join(dir, name+".key"): {Type: "EC PRIVATE KEY", Bytes: keyDER},
+	} {
+		if err := ioutil.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+			t.Fatalf("failed to write %q: %s", path, err)
+		}
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %s", err)
+	}
+
+	return cert, key
+}
+
+// testTLSTouch sets the modification time of files in the future, as rewriting them might not change it given the
+// file system timestamps granularity.
+func testTLSTouch(t *testing.T, offset time.Duration, paths ...string) {
+	modTime := time.Now().Add(offset)
+
+	for _, path := range paths {
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("failed to change %q times: %s", path, err)
+		}
+	}
+}
+
+func testTLSCheckCertificate(t *testing.T, loader *tlsCertificateLoader, expected *x509.Certificate) {
+	cert, err := loader.GetCertificate(nil)
+	if err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	} else if cert == nil || len(cert.Certificate) == 0 || !bytes.Equal(cert.Certificate[0], expected.Raw) {
+		t.Logf("\nExpected certificate %s\nbut got  another one", expected.SerialNumber)
+		t.Fail()
+	}
+}
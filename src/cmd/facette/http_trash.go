@@ -0,0 +1,335 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"facette/backend"
+
+	"github.com/facette/httproute"
+	"github.com/facette/httputil"
+	"github.com/facette/jsonutil"
+	"github.com/facette/sliceutil"
+	"github.com/facette/sqlstorage"
+)
+
+// trashTypes represents the back-end item types moved to trash upon deletion.
+var trashTypes = []string{
+	"collections",
+	"graphs",
+	"sourcegroups",
+	"metricgroups",
+}
+
+func (w *httpWorker) httpHandleTrashList(rw http.ResponseWriter, r *http.Request) {
+	filters := map[string]interface{}{}
+	w.httpApplyOrganizationFilter(rw, &backend.TrashEntry{}, filters)
+
+	if v := r.URL.Query().Get("type"); v != "" {
+		filters["item_type"] = v
+	}
+
+	if v := r.URL.Query().Get("filter"); v != "" {
+		filters["name"] = filterApplyModifier(v)
+	}
+
+	offset, err := httpGetIntParam(r, "offset")
+	if err != nil || offset < 0 {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return
+	}
+
+	limit, err := httpGetIntParam(r, "limit")
+	if err != nil || limit < 0 {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrInvalidParameter), http.StatusBadRequest)
+		return
+	}
+
+	// Request trash entries from back-end, most recently deleted first. Entries are paginated once filtered, as the
+	// authenticated user might not be allowed to see some of the trashed items.
+	entries := []*backend.TrashEntry{}
+
+	if _, err := w.service.backend.Storage().List(&entries, filters, []string{"-deleted"}, 0, 0); err != nil {
+		w.log.Error("failed to fetch items: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	user := httpAuthUser(rw)
+
+	hidden, err := w.httpHiddenItems(rw)
+	if err != nil {
+		w.log.Error("failed to fetch items: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	visible := []*backend.TrashEntry{}
+	for _, entry := range entries {
+		if item, err := w.httpTrashItem(entry); err != nil {
+			w.log.Error("failed to decode trash entry: %s", err)
+		} else if v, ok := item.(backend.ACLItem); !ok || v.GetACL().Allowed(user, backend.AccessRead) {
+			visible = append(visible, httpFilterTrashEntry(entry, user, hidden))
+		}
+	}
+
+	start, end := httpGetPageBounds(len(visible), offset, limit)
+
+	fields := httpGetListParam(r, "fields", []string{"id", "item", "item_type", "name", "author", "deleted"})
+
+	result := []map[string]interface{}{}
+	for _, entry := range visible[start:end] {
+		result = append(result, jsonutil.FilterStruct(entry, fields))
+	}
+
+	rw.Header().Set("X-Total-Records", fmt.Sprintf("%d", len(visible)))
+	httputil.WriteJSON(rw, result, http.StatusOK)
+}
+
+func (w *httpWorker) httpHandleTrashGet(rw http.ResponseWriter, r *http.Request) {
+	entry, ok := w.httpGetTrashEntry(rw, r, backend.AccessRead)
+	if !ok {
+		return
+	}
+
+	hidden, err := w.httpHiddenItems(rw)
+	if err != nil {
+		w.log.Error("failed to fetch items: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	httputil.WriteJSON(rw, httpFilterTrashEntry(entry, httpAuthUser(rw), hidden), http.StatusOK)
+}
+
+func (w *httpWorker) httpHandleTrashRestore(rw http.ResponseWriter, r *http.Request) {
+	if w.service.config.ReadOnly {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrReadOnly), http.StatusForbidden)
+		return
+	}
+
+	entry, ok := w.httpGetTrashEntry(rw, r, backend.AccessWrite)
+	if !ok {
+		return
+	}
+
+	// Restore item along with the items depending on it, then remove entry from trash
+	skipped, err := w.service.backend.RestoreTrashEntry(entry)
+	if err != nil {
+		switch err {
+		case sqlstorage.ErrItemConflict:
+			httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusConflict)
+
+		case sqlstorage.ErrUnknownReference:
+			httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusBadRequest)
+
+		default:
+			w.log.Error("failed to restore item: %s", err)
+			httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	for _, item := range skipped {
+		w.log.Warning("skipped %s item while restoring %s item from trash: %s", item.Type, entry.ItemID, item.Data)
+	}
+
+	if err := w.service.backend.Storage().Delete(entry); err != nil {
+		w.log.Error("failed to delete trash entry: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	w.log.Debug("restored %s item from trash", entry.ItemID)
+
+	w.httpAuditTrash(rw, r, backend.AuditActionRestore, entry)
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (w *httpWorker) httpHandleTrashPurge(rw http.ResponseWriter, r *http.Request) {
+	if w.service.config.ReadOnly {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrReadOnly), http.StatusForbidden)
+		return
+	}
+
+	entry, ok := w.httpGetTrashEntry(rw, r, backend.AccessWrite)
+	if !ok {
+		return
+	}
+
+	if err := w.service.backend.PurgeTrashEntry(entry); err != nil {
+		w.log.Error("failed to purge trash entry: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	w.log.Debug("purged %s item from trash", entry.ItemID)
+
+	w.httpAuditTrash(rw, r, backend.AuditActionPurge, entry)
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (w *httpWorker) httpHandleTrashPurgeAll(rw http.ResponseWriter, r *http.Request) {
+	if w.service.config.ReadOnly {
+		httputil.WriteJSON(rw, httpBuildMessage(ErrReadOnly), http.StatusForbidden)
+		return
+	}
+
+	// Check for confirmation header
+	if r.Header.Get("X-Confirm-Action") != "1" {
+		rw.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	filters := map[string]interface{}{}
+	w.httpApplyOrganizationFilter(rw, &backend.TrashEntry{}, filters)
+
+	entries := []*backend.TrashEntry{}
+
+	if _, err := w.service.backend.Storage().List(&entries, filters, nil, 0, 0); err != nil {
+		w.log.Error("failed to fetch items for deletion: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return
+	}
+
+	for _, entry := range entries {
+		if err := w.service.backend.PurgeTrashEntry(entry); err == sqlstorage.ErrItemNotFound {
+			continue
+		} else if err != nil {
+			w.log.Error("failed to purge trash entry: %s", err)
+			httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+			return
+		}
+
+		w.httpAuditTrash(rw, r, backend.AuditActionPurge, entry)
+	}
+
+	w.log.Debug("purged trash")
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// httpGetTrashEntry retrieves a back-end trash entry, checking whether or not the authenticated user is given an
+// access to the trashed item.
+func (w *httpWorker) httpGetTrashEntry(rw http.ResponseWriter, r *http.Request, access string) (*backend.TrashEntry,
+	bool) {
+
+	entry := &backend.TrashEntry{}
+
+	if err := w.service.backend.Storage().Get("id", httproute.ContextParam(r, "id").(string),
+		entry); err == sqlstorage.ErrItemNotFound {
+		httputil.WriteJSON(rw, httpBuildMessage(err), http.StatusNotFound)
+		return nil, false
+	} else if err != nil {
+		w.log.Error("failed to fetch item: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return nil, false
+	} else if !w.httpCheckOrganization(rw, entry) {
+		return nil, false
+	}
+
+	item, err := w.httpTrashItem(entry)
+	if err != nil {
+		w.log.Error("failed to decode trash entry: %s", err)
+		httputil.WriteJSON(rw, httpBuildMessage(ErrUnhandledError), http.StatusInternalServerError)
+		return nil, false
+	} else if !w.httpCheckAccess(rw, item, access) {
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// httpFilterTrashEntry returns a trash entry only holding the depending items a user is allowed to see, as being
+// allowed to see the trashed item doesn't imply being allowed to see the items depending on it (e.g. private graphs
+// linked to a template graph). Depending items referring to hidden collections are left out as well.
+func httpFilterTrashEntry(entry *backend.TrashEntry, user *backend.User,
+	hidden map[string]bool) *backend.TrashEntry {
+
+	if user == nil || user.Role == backend.RoleAdmin || len(entry.Items) == 0 {
+		return entry
+	}
+
+	result := *entry
+	result.Items = backend.TrashItemList{entry.Items[0]}
+
+	for _, item := range entry.Items[1:] {
+		// Depending items are no longer stored, thus check their access control list from the trashed data
+		var ref struct {
+			backend.ACL
+			ID         string `json:"id"`
+			Collection string `json:"collection"`
+		}
+
+		if err := json.Unmarshal(item.Data, &ref); err != nil || !ref.Allowed(user, backend.AccessRead) ||
+			hidden[ref.ID] || hidden[ref.Collection] {
+			continue
+		}
+
+		result.Items = append(result.Items, item)
+	}
+
+	return &result
+}
+
+// httpTrashItem returns the back-end item held by a trash entry.
+func (w *httpWorker) httpTrashItem(entry *backend.TrashEntry) (interface{}, error) {
+	item, ok := w.httpBackendNewItem(entry.ItemType)
+	if !ok {
+		return nil, backend.ErrUnresolvableItem
+	} else if err := entry.Decode(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// httpMoveToTrash moves a back-end item about to be deleted to trash along with the items depending on it, if its type
+// supports it. It returns the created trash entry, or nil if none.
+func (w *httpWorker) httpMoveToTrash(rw http.ResponseWriter, typ string, item interface{}) (*backend.TrashEntry,
+	error) {
+
+	if !w.httpTrashable(typ) {
+		return nil, nil
+	}
+
+	entry, err := w.service.backend.NewTrashEntry(typ, item)
+	if err != nil {
+		return nil, err
+	}
+
+	if user := httpAuthUser(rw); user != nil {
+		entry.Author = &user.Name
+	}
+
+	if err := w.service.backend.Storage().Save(entry); err != nil {
+		return nil, err
+	}
+
+	w.log.Debug("moved %s item to trash", reflect.Indirect(reflect.ValueOf(item)).FieldByName("ID").String())
+
+	return entry, nil
+}
+
+// httpAuditTrash records an action performed on a trashed back-end item into the audit log.
+func (w *httpWorker) httpAuditTrash(rw http.ResponseWriter, r *http.Request, action string,
+	entry *backend.TrashEntry) {
+
+	item, err := w.httpTrashItem(entry)
+	if err != nil {
+		w.log.Error("failed to decode trash entry: %s", err)
+		return
+	}
+
+	w.httpAudit(rw, r, action, entry.ItemType, item, nil)
+}
+
+// httpTrashable returns whether or not the back-end items of a given type are moved to trash upon deletion.
+func (w *httpWorker) httpTrashable(typ string) bool {
+	return w.service.config.Trash.Enabled && sliceutil.Has(trashTypes, typ)
+}
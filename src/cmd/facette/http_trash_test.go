@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"facette/backend"
+)
+
+func Test_HTTP_FilterTrashEntry(t *testing.T) {
+	var (
+		admin  = &backend.User{Item: backend.Item{ID: "user1"}, Role: backend.RoleAdmin}
+		editor = &backend.User{Item: backend.Item{ID: "user2"}, Role: backend.RoleEditor}
+		owner  = &backend.User{Item: backend.Item{ID: "user3"}, Role: backend.RoleEditor}
+	)
+
+	items := backend.TrashItemList{
+		{Type: "graphs", Data: json.RawMessage(`{"id":"graph1","name":"graph1","template":true}`)},
+		{Type: "graphs", Data: json.RawMessage(`{"id":"graph2","name":"graph2","link":"graph1"}`)},
+		{Type: "graphs", Data: json.RawMessage(`{"id":"graph3","name":"graph3","link":"graph1","owner":"user3",` +
+			`"private":true}`)},
+		{Type: backend.TrashItemEntry, Data: json.RawMessage(`{"collection":"collection1","graph":"graph1"}`)},
+		{Type: backend.TrashItemEntry, Data: json.RawMessage(`{"collection":"collection2","graph":"graph1"}`)},
+		{Type: "alerts", Data: json.RawMessage(`{"id":"alert1","name":"alert1","graph":"graph1"}`)},
+	}
+
+	entry := &backend.TrashEntry{ID: "entry1", ItemID: "graph1", ItemType: "graphs", Name: "graph1", Items: items}
+
+	// Entries are left untouched for unauthenticated contexts and administrators
+	for _, user := range []*backend.User{nil, admin} {
+		if result := httpFilterTrashEntry(entry, user, nil); result != entry {
+			t.Logf("\nExpected %#v\nbut got  %#v", entry, result)
+			t.Fail()
+		}
+	}
+
+	for _, test := range []struct {
+		user     *backend.User
+		hidden   map[string]bool
+		expected backend.TrashItemList
+	}{
+		{editor, nil, backend.TrashItemList{items[0], items[1], items[3], items[4], items[5]}},
+		{editor, map[string]bool{"collection2": true}, backend.TrashItemList{items[0], items[1], items[3], items[5]}},
+		{owner, nil, items},
+		// Trashed item is always kept, access to it being checked beforehand
+		{owner, map[string]bool{"graph1": true}, items},
+	} {
+		result := httpFilterTrashEntry(entry, test.user, test.hidden)
+		if !reflect.DeepEqual(result.Items, test.expected) {
+			t.Logf("\nExpected %#v\nbut got  %#v", test.expected, result.Items)
+			t.Fail()
+		}
+	}
+
+	if len(entry.Items) != len(items) {
+		t.Logf("\nExpected original entry to hold %d items\nbut got  %d", len(items), len(entry.Items))
+		t.Fail()
+	}
+}
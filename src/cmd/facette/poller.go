@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	"facette/backend"
+	"facette/connector"
 	"facette/worker"
 
 	"github.com/facette/logger"
@@ -49,7 +50,9 @@ func (w *pollerWorker) Run(wg *sync.WaitGroup) {
 		w.StartProvider(p)
 	}
 
-	w.service.searcher.ApplyPriorities()
+	for _, searcher := range w.service.Searchers() {
+		searcher.ApplyPriorities()
+	}
 }
 
 func (w *pollerWorker) Shutdown() {
@@ -115,6 +118,23 @@ func (w *pollerWorker) StopProvider(prov *backend.Provider, update bool) {
 	}
 }
 
+// StopOrganization stops the running providers belonging to an organization.
+func (w *pollerWorker) StopOrganization(id string) {
+	providers := []*backend.Provider{}
+
+	w.Lock()
+	for _, pw := range w.providers {
+		if pw != nil && pw.provider.GetOrganization() == id {
+			providers = append(providers, pw.provider)
+		}
+	}
+	w.Unlock()
+
+	for _, prov := range providers {
+		w.StopProvider(prov, false)
+	}
+}
+
 func (w *pollerWorker) RefreshAll() {
 	for _, prov := range w.providers {
 		go (*prov).Refresh()
@@ -129,3 +149,21 @@ func (w *pollerWorker) Refresh(prov backend.Provider) {
 		go pw.Refresh()
 	}
 }
+
+// AnnotationConnector returns the connector of a running provider given its organization and name, if it provides
+// annotations.
+func (w *pollerWorker) AnnotationConnector(organization, name string) (connector.AnnotationConnector, bool) {
+	w.Lock()
+	defer w.Unlock()
+
+	for _, pw := range w.providers {
+		if pw == nil || pw.provider.Name != name || pw.provider.GetOrganization() != organization {
+			continue
+		}
+
+		c, ok := pw.connector.(connector.AnnotationConnector)
+		return c, ok
+	}
+
+	return nil, false
+}
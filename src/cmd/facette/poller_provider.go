@@ -24,6 +24,7 @@ type providerWorker struct {
 	provider   *backend.Provider
 	connector  connector.Connector
 	catalog    *catalog.Catalog
+	searcher   *catalog.Searcher
 	filters    *catalog.FilterChain
 	refreshing bool
 	cmdChan    chan int
@@ -43,6 +44,7 @@ func newProviderWorker(poller *pollerWorker, prov *backend.Provider) (*providerW
 		provider:  prov,
 		connector: c,
 		catalog:   catalog.NewCatalog(prov.Name),
+		searcher:  poller.service.Searcher(prov.GetOrganization()),
 		filters:   catalog.NewFilterChain(&prov.Filters),
 		cmdChan:   make(chan int),
 		wg:        &sync.WaitGroup{},
@@ -60,8 +62,8 @@ func (w *providerWorker) Run(wg *sync.WaitGroup) {
 	w.wg.Add(1)
 	w.poller.log.Debug("provider %q started", w.provider.Name)
 
-	// Register catalog into main searcher instance
-	w.poller.service.searcher.Register(w.catalog)
+	// Register catalog into its organization searcher instance
+	w.searcher.Register(w.catalog)
 
 	// Set catalog priority if defined
 	if w.provider.Priority > 0 {
@@ -122,8 +124,8 @@ stop:
 }
 
 func (w *providerWorker) Shutdown() {
-	// Unregister catalog from main searcher instance
-	w.poller.service.searcher.Unregister(w.catalog)
+	// Unregister catalog from its organization searcher instance
+	w.searcher.Unregister(w.catalog)
 
 	// Trigger provider shutdown
 	w.cmdChan <- providerCmdShutdown
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"facette/backend"
+	"facette/worker"
+
+	"github.com/facette/logger"
+	"github.com/facette/sqlstorage"
+)
+
+const purgerTickInterval = time.Hour
+
+type purgerWorker struct {
+	worker.CommonWorker
+
+	service  *Service
+	log      *logger.Logger
+	stopChan chan struct{}
+	wg       *sync.WaitGroup
+}
+
+func newPurgerWorker(s *Service) *purgerWorker {
+	return &purgerWorker{
+		service:  s,
+		log:      s.log.Context("purger"),
+		stopChan: make(chan struct{}),
+		wg:       &sync.WaitGroup{},
+	}
+}
+
+func (w *purgerWorker) Run(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	w.wg.Add(1)
+	defer w.wg.Done()
+
+	w.log.Debug("worker started")
+
+	ticker := time.NewTicker(purgerTickInterval)
+	defer ticker.Stop()
+
+	w.purgeExpired(time.Now().UTC())
+
+	for {
+		select {
+		case now := <-ticker.C:
+			w.purgeExpired(now.UTC())
+
+		case <-w.stopChan:
+			w.log.Debug("worker stopped")
+			return
+		}
+	}
+}
+
+func (w *purgerWorker) Shutdown() {
+	if w.Stopping() {
+		return
+	}
+
+	// Trigger purger shutdown
+	close(w.stopChan)
+	w.wg.Wait()
+
+	w.CommonWorker.Shutdown()
+}
+
+// purgeExpired permanently removes the trash entries older than the configured retention period, along with the
+// expired snapshots.
+func (w *purgerWorker) purgeExpired(now time.Time) {
+	w.purgeTrash(now)
+	w.purgeSnapshots(now)
+}
+
+func (w *purgerWorker) purgeTrash(now time.Time) {
+	entries := []*backend.TrashEntry{}
+	if _, err := w.service.backend.Storage().List(&entries, nil, nil, 0, 0); err != nil {
+		w.log.Error("failed to list trash entries: %s", err)
+		return
+	}
+
+	limit := now.Add(-time.Duration(w.service.config.Trash.Retention) * time.Second)
+
+	for _, entry := range entries {
+		if !entry.Deleted.Before(limit) {
+			continue
+		}
+
+		if err := w.service.backend.PurgeTrashEntry(entry); err != nil {
+			w.log.Error("failed to purge %s item from trash: %s", entry.ItemID, err)
+			continue
+		}
+
+		w.log.Debug("purged %s item from trash", entry.ItemID)
+	}
+}
+
+func (w *purgerWorker) purgeSnapshots(now time.Time) {
+	snapshots := []*backend.Snapshot{}
+	if _, err := w.service.backend.Storage().List(&snapshots, map[string]interface{}{
+		"expires": sqlstorage.LessOrEqualModifier{Value: now.UTC()},
+	}, nil, 0, 0); err != nil {
+		w.log.Error("failed to list snapshots: %s", err)
+		return
+	}
+
+	for _, snapshot := range snapshots {
+		if err := w.service.backend.Storage().Delete(snapshot); err != nil {
+			w.log.Error("failed to delete expired %s snapshot: %s", snapshot.ID, err)
+			continue
+		}
+
+		w.log.Debug("deleted expired %s snapshot", snapshot.ID)
+	}
+}
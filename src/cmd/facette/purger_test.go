@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"facette/backend"
+)
+
+func Test_Purger_PurgeSnapshots(t *testing.T) {
+	w, cleanup := testHTTPWorker(t)
+	defer cleanup()
+
+	now := time.Now().UTC().Round(time.Second)
+
+	ids := []string{}
+	for _, expires := range []*time.Time{nil, testTimePtr(now.Add(-time.Hour)), testTimePtr(now.Add(time.Hour))} {
+		snapshot, _, err := backend.NewSnapshot("graphs", &backend.Graph{Item: backend.Item{Name: "graph1"}})
+		if err != nil {
+			t.Fatalf("failed to create snapshot: %s", err)
+		}
+
+		snapshot.Expires = expires
+
+		if err := w.service.backend.Storage().Save(snapshot); err != nil {
+			t.Fatalf("failed to save snapshot: %s", err)
+		}
+
+		ids = append(ids, snapshot.ID)
+	}
+
+	newPurgerWorker(w.service).purgeSnapshots(now)
+
+	for i, expected := range []bool{true, false, true} {
+		err := w.service.backend.Storage().Get("id", ids[i], &backend.Snapshot{})
+		if (err == nil) != expected {
+			t.Logf("\nExpected snapshot %d kept %v\nbut got  %v", i, expected, err)
+			t.Fail()
+		}
+	}
+}
+
+func testTimePtr(t time.Time) *time.Time {
+	return &t
+}
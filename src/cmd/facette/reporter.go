@@ -0,0 +1,396 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"facette/backend"
+	"facette/cron"
+	"facette/notifier"
+	"facette/plot"
+	"facette/render"
+	"facette/worker"
+
+	"github.com/facette/logger"
+	"github.com/facette/maputil"
+	"github.com/facette/sqlstorage"
+)
+
+const reporterTickInterval = 30 * time.Second
+
+var (
+	reportFileRegexp = regexp.MustCompile(`[^A-Za-z0-9_\-]+`)
+
+	reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Name }}</title>
+<style>
+body { font-family: sans-serif; color: #333; margin: 2em; }
+table { border-collapse: collapse; margin: 1em 0 2em; }
+th, td { border-bottom: 1px solid #ddd; padding: 0.25em 1em; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+.error { color: #c0392b; }
+</style>
+</head>
+<body>
+<h1>{{ .Name }}</h1>
+<p>{{ .Collection }} &mdash; {{ .Start }} to {{ .End }}</p>
+{{ range .Graphs }}
+<h2>{{ .Title }}</h2>
+{{ if .Error }}<p class="error">{{ .Error }}</p>{{ else }}
+<img src="{{ .Image }}" alt="{{ .Title }}">
+<table>
+<tr><th>Series</th>{{ range $.Stats }}<th>{{ . }}</th>{{ end }}</tr>
+{{ range .Series }}<tr><td>{{ .Name }}</td>{{ range .Values }}<td>{{ . }}</td>{{ end }}</tr>
+{{ end }}</table>
+{{ end }}{{ end }}
+<p><small>Generated by Facette on {{ .Generated }}</small></p>
+</body>
+</html>
+`))
+
+	reportStats = []string{"min", "avg", "max", "last"}
+)
+
+type reportData struct {
+	Name       string
+	Collection string
+	Start      string
+	End        string
+	Generated  string
+	Stats      []string
+	Graphs     []reportGraph
+}
+
+type reportGraph struct {
+	Title  string
+	Image  template.URL
+	Series []reportSeries
+	Error  string
+}
+
+type reportSeries struct {
+	Name   string
+	Values []string
+}
+
+type reportSchedule struct {
+	spec string
+	next time.Time
+}
+
+type reporterWorker struct {
+	sync.Mutex
+	worker.CommonWorker
+
+	service   *Service
+	log       *logger.Logger
+	schedules map[string]*reportSchedule
+	stopChan  chan struct{}
+	wg        *sync.WaitGroup
+}
+
+func newReporterWorker(s *Service) *reporterWorker {
+	return &reporterWorker{
+		service:   s,
+		log:       s.log.Context("reporter"),
+		schedules: make(map[string]*reportSchedule),
+		stopChan:  make(chan struct{}),
+		wg:        &sync.WaitGroup{},
+	}
+}
+
+func (w *reporterWorker) Run(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	w.wg.Add(1)
+	defer w.wg.Done()
+
+	w.log.Debug("worker started")
+
+	ticker := time.NewTicker(reporterTickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			w.runDue(now)
+
+		case <-w.stopChan:
+			w.log.Debug("worker stopped")
+			return
+		}
+	}
+}
+
+func (w *reporterWorker) Shutdown() {
+	if w.Stopping() {
+		return
+	}
+
+	// Trigger reporter shutdown
+	close(w.stopChan)
+	w.wg.Wait()
+
+	w.CommonWorker.Shutdown()
+}
+
+func (w *reporterWorker) runDue(now time.Time) {
+	reports := []*backend.Report{}
+	if _, err := w.service.backend.Storage().List(&reports, map[string]interface{}{"enabled": true}, nil, 0,
+		0); err != nil {
+		w.log.Error("failed to list reports: %s", err)
+		return
+	}
+
+	due := []*backend.Report{}
+	schedules := make(map[string]*reportSchedule)
+
+	w.Lock()
+
+	for _, report := range reports {
+		// Compute next activation time for new reports or if their schedule changed
+		s, ok := w.schedules[report.ID]
+		if !ok || s.spec != report.Schedule {
+			schedule, err := cron.Parse(report.Schedule)
+			if err != nil {
+				w.log.Error("failed to parse %q report schedule: %s", report.Name, err)
+				continue
+			}
+
+			s = &reportSchedule{spec: report.Schedule, next: schedule.Next(now)}
+		}
+
+		if !s.next.IsZero() && !now.Before(s.next) {
+			due = append(due, report)
+
+			schedule, _ := cron.Parse(s.spec)
+			s.next = schedule.Next(now)
+		}
+
+		schedules[report.ID] = s
+	}
+
+	// Forget about schedules of deleted or disabled reports
+	w.schedules = schedules
+
+	w.Unlock()
+
+	for _, report := range due {
+		if err := w.Generate(report, now); err != nil {
+			w.log.Error("failed to generate %q report: %s", report.Name, err)
+		}
+	}
+}
+
+// newReportGraph renders a report graph image given its plots, along with its series statistics.
+func newReportGraph(plots *plot.Response) reportGraph {
+	graph := reportGraph{}
+	graph.Title, _ = plots.Options["title"].(string)
+
+	opts := render.NewOptions(plots.Options)
+
+	buf := bytes.NewBuffer(nil)
+	if err := render.Render(buf, render.FormatPNG, plots, opts); err != nil {
+		graph.Error = err.Error()
+		return graph
+	}
+
+	graph.Image = template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()))
+
+	for _, s := range plots.Series {
+		series := reportSeries{Name: s.Name}
+		for _, stat := range reportStats {
+			value := "-"
+			if v, ok := s.Summary[stat]; ok && !v.IsNaN() {
+				value = strconv.FormatFloat(float64(v), 'g', 6, 64)
+			}
+
+			series.Values = append(series.Values, value)
+		}
+
+		graph.Series = append(graph.Series, series)
+	}
+
+	return graph
+}
+
+// Generate renders the report collection graphs at a given time, and delivers the resulting bundle either through
+// the report notification channel or by writing it into the report directory.
+func (w *reporterWorker) Generate(report *backend.Report, now time.Time) error {
+	// Only include the graphs visible to the report owner, thus only the public ones if it has none
+	owner := &backend.User{Role: backend.RoleViewer}
+	if report.OwnerID != nil {
+		user := w.service.backend.NewUser()
+		if err := w.service.backend.Storage().Get("id", *report.OwnerID, user); err == nil {
+			owner = user
+		} else if err != sqlstorage.ErrItemNotFound {
+			return err
+		}
+	}
+
+	hidden, err := w.service.http.hiddenItems(owner)
+	if err != nil {
+		return err
+	}
+
+	collection := w.service.backend.NewCollection()
+	if err := w.service.backend.Storage().Get("id", report.CollectionID, collection); err != nil {
+		return err
+	} else if hidden[collection.ID] {
+		return sqlstorage.ErrItemNotFound
+	} else if err := collection.Expand(report.Attributes); err != nil {
+		return err
+	}
+
+	data := reportData{
+		Name:       report.Name,
+		Collection: collection.Name,
+		Generated:  now.Format(time.RFC1123),
+		Stats:      reportStats,
+	}
+
+	files := []notifier.Attachment{}
+
+	entries := []*backend.CollectionEntry{}
+	for _, entry := range collection.Entries {
+		if !hidden[entry.GraphID] {
+			entries = append(entries, entry)
+		}
+	}
+
+	for idx, entry := range entries {
+		attrs := maputil.Map{}
+		attrs.Merge(collection.Attributes, true)
+		attrs.Merge(entry.Attributes, true)
+
+		req := &plot.Request{
+			ID:         entry.GraphID,
+			Time:       now,
+			Range:      report.Range,
+			Attributes: attrs,
+		}
+
+		graph := reportGraph{Title: entry.GraphID}
+
+		plots, err := w.service.http.executePlotRequest(req, report.GetOrganization())
+		if err != nil {
+			w.log.Warning("failed to execute %q report graph request: %s", report.Name, err)
+			graph.Error = err.Error()
+			data.Graphs = append(data.Graphs, graph)
+			continue
+		}
+
+		if data.Start == "" {
+			data.Start, data.End = plots.Start, plots.End
+		}
+
+		// Entry title takes precedence over the graph one
+		if title, ok := entry.Options["title"].(string); ok && title != "" {
+			plots.Options["title"] = title
+		}
+
+		graph = newReportGraph(plots)
+		data.Graphs = append(data.Graphs, graph)
+		if graph.Error != "" {
+			continue
+		}
+
+		// Export graph plots as CSV file
+		buf := bytes.NewBuffer(nil)
+		if err := plots.WriteCSV(buf, ','); err != nil {
+			return err
+		}
+
+		files = append(files, notifier.Attachment{
+			Name:        fmt.Sprintf("%02d-%s.csv", idx+1, reportFileRegexp.ReplaceAllString(graph.Title, "_")),
+			ContentType: "text/csv",
+			Data:        buf.Bytes(),
+		})
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := reportTemplate.Execute(buf, data); err != nil {
+		return err
+	}
+
+	files = append([]notifier.Attachment{{Name: "report.html", ContentType: "text/html", Data: buf.Bytes()}},
+		files...)
+
+	if report.Directory != "" {
+		if err := w.write(report, now, files); err != nil {
+			return err
+		}
+	}
+
+	if report.ChannelID != nil && *report.ChannelID != "" {
+		if err := w.send(report, now, collection, files); err != nil {
+			return err
+		}
+	}
+
+	w.log.Info("generated %q report", report.Name)
+
+	return nil
+}
+
+func (w *reporterWorker) write(report *backend.Report, now time.Time, files []notifier.Attachment) error {
+	dir, err := w.service.ReportDirectory(report.Directory)
+	if err != nil {
+		return err
+	}
+
+	dir = filepath.Join(dir, fmt.Sprintf("%s-%s", report.Name, now.Format("20060102-150405")))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, f.Name), f.Data, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *reporterWorker) send(report *backend.Report, now time.Time, collection *backend.Collection,
+	files []notifier.Attachment) error {
+
+	channel := w.service.backend.NewChannel()
+	if err := w.service.backend.Storage().Get("id", *report.ChannelID, channel); err != nil {
+		return err
+	} else if !channel.Enabled {
+		return nil
+	}
+
+	n, err := w.service.NewNotifier(channel, w.log)
+	if err != nil {
+		return err
+	}
+
+	return n.Notify(&notifier.Notification{
+		Subject: fmt.Sprintf("%s report", report.Name),
+		Message: fmt.Sprintf("report %q of %q collection over %s (see attached files)", report.Name,
+			collection.Name, report.Range),
+		Time: now,
+		Attributes: map[string]interface{}{
+			"id":         report.ID,
+			"name":       report.Name,
+			"collection": collection.Name,
+			"range":      report.Range,
+		},
+		Attachments: files,
+	})
+}
@@ -3,32 +3,69 @@ package main
 import (
 	"facette/backend"
 	"facette/catalog"
+	"facette/notifier"
 	"facette/worker"
 	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/facette/logger"
+	"github.com/facette/sliceutil"
 )
 
 // Service represents a service struct.
 type Service struct {
-	config   *config
-	log      *logger.Logger
-	backend  *backend.Backend
-	poller   *pollerWorker
-	searcher *catalog.Searcher
-	workers  *worker.Pool
-	stopping bool
+	config        *config
+	log           *logger.Logger
+	backend       *backend.Backend
+	http          *httpWorker
+	poller        *pollerWorker
+	alerter       *alerterWorker
+	reporter      *reporterWorker
+	purger        *purgerWorker
+	auditor       *auditor
+	searchers     map[string]*catalog.Searcher
+	searchersLock sync.Mutex
+	workers       *worker.Pool
+	stopping      bool
 }
 
 // NewService returns a new service instance.
 func NewService(config *config) *Service {
 	return &Service{
-		config:   config,
-		searcher: catalog.NewSearcher(),
-		workers:  worker.NewPool(),
+		config:    config,
+		searchers: make(map[string]*catalog.Searcher),
+		workers:   worker.NewPool(),
 	}
 }
 
+// Searcher returns the catalog searcher of an organization given its identifier, assembled from the catalogs of its
+// providers only. An empty identifier stands for the providers not belonging to any organization.
+func (s *Service) Searcher(organization string) *catalog.Searcher {
+	s.searchersLock.Lock()
+	defer s.searchersLock.Unlock()
+
+	if _, ok := s.searchers[organization]; !ok {
+		s.searchers[organization] = catalog.NewSearcher()
+	}
+
+	return s.searchers[organization]
+}
+
+// Searchers returns the catalog searchers of all the organizations.
+func (s *Service) Searchers() []*catalog.Searcher {
+	s.searchersLock.Lock()
+	defer s.searchersLock.Unlock()
+
+	result := []*catalog.Searcher{}
+	for _, searcher := range s.searchers {
+		result = append(result, searcher)
+	}
+
+	return result
+}
+
 // Run starts the service processing.
 func (s *Service) Run() error {
 	var err error
@@ -47,14 +84,40 @@ func (s *Service) Run() error {
 		return nil
 	}
 
+	// Initialize audit log
+	if s.config.Audit.Enabled {
+		if s.auditor, err = newAuditor(s); err != nil {
+			s.log.Error("failed to initialize audit log: %s", err)
+			return nil
+		}
+	}
+
 	// Register and initialize workers
+	s.http = newHTTPWorker(s)
 	s.poller = newPollerWorker(s)
 
 	s.workers.Add(
-		worker.NewWorker(newHTTPWorker(s)),
+		worker.NewWorker(s.http),
 		worker.NewWorker(s.poller),
 	)
 
+	if s.config.Alerting.Enabled {
+		s.alerter = newAlerterWorker(s)
+		s.workers.Add(worker.NewWorker(s.alerter))
+	}
+
+	// Reports can still be generated on demand if scheduling is disabled
+	s.reporter = newReporterWorker(s)
+	if s.config.Reporting.Enabled {
+		s.workers.Add(worker.NewWorker(s.reporter))
+	}
+
+	// Trash entries are kept forever if no retention period is set
+	if s.config.Trash.Enabled && s.config.Trash.Retention > 0 {
+		s.purger = newPurgerWorker(s)
+		s.workers.Add(worker.NewWorker(s.purger))
+	}
+
 	if err = s.workers.Init(); err != nil {
 		return fmt.Errorf("failed to initialize workers: %s", err)
 	}
@@ -82,6 +145,11 @@ func (s *Service) Shutdown() {
 		s.backend.Close()
 	}
 
+	// Close audit log
+	if s.auditor != nil {
+		s.auditor.Close()
+	}
+
 	// Broadcast shutdown job to workers
 	s.workers.Shutdown()
 }
@@ -93,3 +161,38 @@ func (s *Service) Refresh() {
 	// Trigger poller providers refresh
 	s.poller.RefreshAll()
 }
+
+// NewNotifier creates a new notification handler for a channel. Command channels executing local commands as the
+// service user, they are rejected unless enabled in the configuration, their command having to be allowed if a list
+// of allowed commands is set.
+func (s *Service) NewNotifier(channel *backend.Channel, log *logger.Logger) (notifier.Notifier, error) {
+	if channel.Kind == "command" {
+		cfg := s.config.Channels.Command
+
+		if !cfg.Enabled {
+			return nil, ErrCommandChannelsDisabled
+		}
+
+		if len(cfg.AllowedCommands) > 0 {
+			command, _ := channel.Settings.GetString("command", "")
+			if !sliceutil.Has(cfg.AllowedCommands, command) {
+				return nil, ErrCommandNotAllowed
+			}
+		}
+	}
+
+	return notifier.NewNotifier(channel.Kind, channel.Name, &channel.Settings, log)
+}
+
+// ReportDirectory returns the path of a report output directory. Reports being written as the service user, their
+// directory has to be relative to the base directory set in the configuration, reports not being written to disk
+// otherwise.
+func (s *Service) ReportDirectory(dir string) (string, error) {
+	if s.config.Reporting.Directory == "" {
+		return "", ErrReportDirectoryDisabled
+	} else if filepath.IsAbs(dir) || sliceutil.Has(strings.Split(filepath.ToSlash(dir), "/"), "..") {
+		return "", ErrInvalidReportDirectory
+	}
+
+	return filepath.Join(s.config.Reporting.Directory, dir), nil
+}
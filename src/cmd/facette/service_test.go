@@ -0,0 +1,91 @@
+package main
+
+import (
+	"testing"
+
+	"facette/backend"
+
+	"github.com/facette/maputil"
+)
+
+func Test_Service_NewNotifier(t *testing.T) {
+	service := NewService(&config{})
+
+	channel := &backend.Channel{
+		Name:     "channel1",
+		Kind:     "command",
+		Settings: maputil.Map{"command": "/bin/true"},
+	}
+
+	// Command channels are disabled by default
+	if _, err := service.NewNotifier(channel, nil); err != ErrCommandChannelsDisabled {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrCommandChannelsDisabled, err)
+		t.Fail()
+	}
+
+	service.config.Channels.Command.Enabled = true
+
+	if _, err := service.NewNotifier(channel, nil); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	}
+
+	// Only listed commands are allowed if any
+	service.config.Channels.Command.AllowedCommands = []string{"/usr/local/bin/notify"}
+
+	if _, err := service.NewNotifier(channel, nil); err != ErrCommandNotAllowed {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrCommandNotAllowed, err)
+		t.Fail()
+	}
+
+	channel.Settings["command"] = "/usr/local/bin/notify"
+
+	if _, err := service.NewNotifier(channel, nil); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	}
+
+	// Other channels kinds are unaffected
+	channel = &backend.Channel{
+		Name:     "channel2",
+		Kind:     "webhook",
+		Settings: maputil.Map{"url": "http://localhost/"},
+	}
+
+	service.config.Channels.Command.Enabled = false
+
+	if _, err := service.NewNotifier(channel, nil); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	}
+}
+
+func Test_Service_ReportDirectory(t *testing.T) {
+	service := NewService(&config{})
+
+	// Report directories are disabled unless a base directory is set
+	if _, err := service.ReportDirectory("reports"); err != ErrReportDirectoryDisabled {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrReportDirectoryDisabled, err)
+		t.Fail()
+	}
+
+	service.config.Reporting.Directory = "/var/lib/facette/reports"
+
+	for _, test := range []struct {
+		dir      string
+		expected string
+		err      error
+	}{
+		{"", "/var/lib/facette/reports", nil},
+		{"weekly", "/var/lib/facette/reports/weekly", nil},
+		{"team1/weekly", "/var/lib/facette/reports/team1/weekly", nil},
+		{"/etc", "", ErrInvalidReportDirectory},
+		{"..", "", ErrInvalidReportDirectory},
+		{"team1/../../etc", "", ErrInvalidReportDirectory},
+	} {
+		if dir, err := service.ReportDirectory(test.dir); err != test.err || dir != test.expected {
+			t.Logf("\nExpected %q, %#v\nbut got  %q, %#v", test.expected, test.err, dir, err)
+			t.Fail()
+		}
+	}
+}
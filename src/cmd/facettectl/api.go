@@ -56,6 +56,12 @@ func apiRequest(method, enpoint string, headers map[string]string, v, result int
 	}
 
 	req.Header.Add("User-Agent", "facettectl/"+version)
+	if cmd.Token != "" {
+		req.Header.Add("Authorization", "Bearer "+cmd.Token)
+	}
+	if cmd.Organization != "" {
+		req.Header.Add("X-Facette-Organization", cmd.Organization)
+	}
 	if v != nil {
 		req.Header.Add("Content-Type", "application/json")
 	}
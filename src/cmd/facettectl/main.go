@@ -10,11 +10,13 @@ import (
 )
 
 type command struct {
-	Address string `names:"-a, --address" usage:"Upstream socket address" default:"http://localhost:12003"`
-	Help    bool   `names:"-h, --help" usage:"Display this help and exit"`
-	Timeout int    `names:"-t, --timeout" usage:"Upstream connection timeout" default:"30"`
-	Version bool   `names:"-V, --version" usage:"Display version information and exit"`
-	Quiet   bool   `names:"-q, --quiet" usage:"Run in quiet mode"`
+	Address      string `names:"-a, --address" usage:"Upstream socket address" default:"http://localhost:12003"`
+	Help         bool   `names:"-h, --help" usage:"Display this help and exit"`
+	Timeout      int    `names:"-t, --timeout" usage:"Upstream connection timeout" default:"30"`
+	Token        string `names:"-k, --token" usage:"Upstream API authentication token" env:"FACETTE_TOKEN"`
+	Organization string `names:"-O, --organization" usage:"Upstream organization" env:"FACETTE_ORGANIZATION"`
+	Version      bool   `names:"-V, --version" usage:"Display version information and exit"`
+	Quiet        bool   `names:"-q, --quiet" usage:"Run in quiet mode"`
 
 	Catalog catalogCommand `usage:"Manage catalog operations"`
 	Library libraryCommand `usage:"Manage library operations"`
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"facette/backend"
+
+	"github.com/facette/logger"
+	"github.com/facette/maputil"
+)
+
+const authDefaultTimeout = 10
+
+var authenticators = make(map[string]func(*maputil.Map, *logger.Logger) (Authenticator, error))
+
+// Authenticator represents an authentication backend handler interface.
+type Authenticator interface {
+	Type() string
+}
+
+// PasswordAuthenticator represents an authentication backend handler checking user credentials upon login.
+type PasswordAuthenticator interface {
+	Authenticator
+	Login(name, password string) (*Identity, error)
+}
+
+// RequestAuthenticator represents an authentication backend handler identifying users from incoming requests. A nil
+// identity is returned if the request isn't handled by the authenticator.
+type RequestAuthenticator interface {
+	Authenticator
+	Identify(r *http.Request) (*Identity, error)
+}
+
+// Identity represents an external user identity, as provided by an authentication backend. Organization holds the
+// name or identifier of the organization the user belongs to, an empty value meaning none.
+type Identity struct {
+	Name         string
+	Email        string
+	Role         string
+	Organization string
+}
+
+// NewAuthenticator creates a new instance of an authentication backend handler.
+func NewAuthenticator(settings *maputil.Map, log *logger.Logger) (Authenticator, error) {
+	typ, err := settings.GetString("type", "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Check for existing authenticator handler
+	if _, ok := authenticators[typ]; !ok {
+		return nil, ErrUnsupportedAuthenticator
+	}
+
+	// Return new authenticator handler instance
+	return authenticators[typ](settings, log)
+}
+
+// Authenticators returns the list of supported authentication backend handlers.
+func Authenticators() []string {
+	list := []string{}
+	for name := range authenticators {
+		list = append(list, name)
+	}
+
+	sort.Strings(list)
+
+	return list
+}
+
+// roleMapping represents a mapping of external groups to user roles.
+type roleMapping struct {
+	groups      map[string][]string
+	defaultRole string
+}
+
+// newRoleMapping creates a new role mapping instance from the "admin_groups", "editor_groups", "viewer_groups" and
+// "default_role" settings. Default role is "viewer" unless explicitly set, an empty value denying access to users not
+// belonging to any mapped group.
+func newRoleMapping(settings *maputil.Map) (*roleMapping, error) {
+	var err error
+
+	m := &roleMapping{groups: make(map[string][]string)}
+
+	for _, role := range []string{backend.RoleAdmin, backend.RoleEditor, backend.RoleViewer} {
+		if m.groups[role], err = getStringSlice(settings, role+"_groups"); err != nil {
+			return nil, err
+		}
+	}
+
+	if m.defaultRole, err = settings.GetString("default_role", backend.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	switch m.defaultRole {
+	case "", backend.RoleAdmin, backend.RoleEditor, backend.RoleViewer:
+		return m, nil
+	}
+
+	return nil, backend.ErrInvalidRole
+}
+
+// Role returns the highest role matching the given groups, or the default role if none matches. An empty role is
+// returned if the user isn't allowed to log in.
+func (m *roleMapping) Role(groups []string) string {
+	for _, role := range []string{backend.RoleAdmin, backend.RoleEditor, backend.RoleViewer} {
+		for _, group := range m.groups[role] {
+			for _, g := range groups {
+				if strings.EqualFold(g, group) {
+					return role
+				}
+			}
+		}
+	}
+
+	return m.defaultRole
+}
+
+// getStringSlice returns the list of strings associated with a settings key, also accepting a single string value.
+func getStringSlice(settings *maputil.Map, key string) ([]string, error) {
+	switch v := (*settings)[key].(type) {
+	case nil:
+		return nil, nil
+
+	case string:
+		return []string{v}, nil
+
+	case []interface{}:
+		return settings.GetStringSlice(key, nil)
+	}
+
+	return nil, maputil.ErrInvalidType
+}
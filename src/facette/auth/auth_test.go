@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+
+	"facette/backend"
+
+	"github.com/facette/logger"
+	"github.com/facette/maputil"
+)
+
+func Test_RoleMapping(t *testing.T) {
+	m, err := newRoleMapping(&maputil.Map{
+		"admin_groups":  []interface{}{"admins"},
+		"editor_groups": "editors",
+	})
+	if err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	}
+
+	for _, entry := range []struct {
+		groups   []string
+		expected string
+	}{
+		{nil, backend.RoleViewer},
+		{[]string{"others"}, backend.RoleViewer},
+		{[]string{"Editors"}, backend.RoleEditor},
+		{[]string{"editors", "admins"}, backend.RoleAdmin},
+	} {
+		if result := m.Role(entry.groups); result != entry.expected {
+			t.Logf("\nExpected %q\nbut got  %q", entry.expected, result)
+			t.Fail()
+		}
+	}
+
+	// Ensure users not belonging to any mapped group are denied if no default role is set
+	if m, err = newRoleMapping(&maputil.Map{"admin_groups": "admins", "default_role": ""}); err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	} else if result := m.Role([]string{"others"}); result != "" {
+		t.Logf("\nExpected %q\nbut got  %q", "", result)
+		t.Fail()
+	}
+
+	if _, err = newRoleMapping(&maputil.Map{"default_role": "invalid"}); err != backend.ErrInvalidRole {
+		t.Logf("\nExpected %#v\nbut got  %#v", backend.ErrInvalidRole, err)
+		t.Fail()
+	}
+}
+
+func Test_Header_Identify(t *testing.T) {
+	log, _ := logger.NewLogger(logger.FileConfig{Level: "error"})
+
+	a, err := NewAuthenticator(&maputil.Map{
+		"type":                "header",
+		"email_header":        "X-Remote-Email",
+		"groups_header":       "X-Remote-Groups",
+		"organization_header": "X-Remote-Organization",
+		"organization":        "org1",
+		"trusted_proxies":     []interface{}{"127.0.0.1", "10.0.0.0/8"},
+		"admin_groups":        "admins",
+		"default_role":        "",
+	}, log)
+	if err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	}
+
+	for _, entry := range []struct {
+		addr     string
+		headers  map[string]string
+		expected *Identity
+		err      error
+	}{
+		{
+			addr: "127.0.0.1:12345",
+			headers: map[string]string{
+				"X-Remote-User":   "user1",
+				"X-Remote-Email":  "user1@example.net",
+				"X-Remote-Groups": "users, admins",
+			},
+			expected: &Identity{
+				Name:         "user1",
+				Email:        "user1@example.net",
+				Role:         backend.RoleAdmin,
+				Organization: "org1",
+			},
+		},
+		{
+			addr: "10.1.2.3:12345",
+			headers: map[string]string{
+				"X-Remote-User":         "user3",
+				"X-Remote-Groups":       "admins",
+				"X-Remote-Organization": "org2",
+			},
+			expected: &Identity{
+				Name:         "user3",
+				Role:         backend.RoleAdmin,
+				Organization: "org2",
+			},
+		},
+		{
+			addr:    "10.1.2.3:12345",
+			headers: map[string]string{"X-Remote-User": "user2", "X-Remote-Groups": "users"},
+			err:     ErrInvalidCredentials,
+		},
+		{
+			addr:    "192.168.0.1:12345",
+			headers: map[string]string{"X-Remote-User": "user1", "X-Remote-Groups": "admins"},
+		},
+		{
+			addr: "127.0.0.1:12345",
+		},
+	} {
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.RemoteAddr = entry.addr
+		for k, v := range entry.headers {
+			r.Header.Set(k, v)
+		}
+
+		identity, err := a.(RequestAuthenticator).Identify(r)
+		if err != entry.err {
+			t.Logf("\nExpected %#v\nbut got  %#v", entry.err, err)
+			t.Fail()
+		} else if entry.expected == nil && identity != nil || entry.expected != nil && (identity == nil ||
+			*identity != *entry.expected) {
+			t.Logf("\nExpected %#v\nbut got  %#v", entry.expected, identity)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Header_MissingProxies(t *testing.T) {
+	log, _ := logger.NewLogger(logger.FileConfig{Level: "error"})
+
+	if _, err := NewAuthenticator(&maputil.Map{"type": "header"}, log); err == nil {
+		t.Logf("\nExpected %#v\nbut got  <nil>", ErrMissingAuthSetting("trusted_proxies"))
+		t.Fail()
+	}
+}
@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidCredentials represents an invalid credentials error.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrUnsupportedAuthenticator represents an unsupported authenticator handler error.
+	ErrUnsupportedAuthenticator = errors.New("unsupported authenticator handler")
+)
+
+// ErrMissingAuthSetting creates a new missing authenticator setting error.
+func ErrMissingAuthSetting(key string) error {
+	return fmt.Errorf("missing mandatory %q authenticator setting", key)
+}
@@ -0,0 +1,158 @@
+// +build !disable_auth_header
+
+package auth
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/facette/logger"
+	"github.com/facette/maputil"
+)
+
+const headerDefaultUser = "X-Remote-User"
+
+// headerAuthenticator implements the authenticator handler trusting the user identity set by a reverse proxy in
+// request headers. Headers are only honored for requests originating from the configured proxies addresses.
+type headerAuthenticator struct {
+	header       string
+	emailHeader  string
+	groupsHeader string
+	orgHeader    string
+	organization string
+	proxies      []*net.IPNet
+	roles        *roleMapping
+	log          *logger.Logger
+}
+
+func init() {
+	authenticators["header"] = func(settings *maputil.Map, log *logger.Logger) (Authenticator, error) {
+		var err error
+
+		a := &headerAuthenticator{
+			log: log,
+		}
+
+		// Load authenticator configuration
+		if a.header, err = settings.GetString("header", headerDefaultUser); err != nil {
+			return nil, err
+		}
+
+		if a.emailHeader, err = settings.GetString("email_header", ""); err != nil {
+			return nil, err
+		}
+
+		if a.groupsHeader, err = settings.GetString("groups_header", ""); err != nil {
+			return nil, err
+		}
+
+		// Organization header, if any, takes precedence over the configured one
+		if a.orgHeader, err = settings.GetString("organization_header", ""); err != nil {
+			return nil, err
+		}
+
+		if a.organization, err = settings.GetString("organization", ""); err != nil {
+			return nil, err
+		}
+
+		proxies, err := getStringSlice(settings, "trusted_proxies")
+		if err != nil {
+			return nil, err
+		} else if len(proxies) == 0 {
+			return nil, ErrMissingAuthSetting("trusted_proxies")
+		}
+
+		for _, proxy := range proxies {
+			// Handle single addresses as host networks
+			if !strings.Contains(proxy, "/") {
+				if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+					proxy += "/32"
+				} else {
+					proxy += "/128"
+				}
+			}
+
+			_, ipNet, err := net.ParseCIDR(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy: %s", err)
+			}
+
+			a.proxies = append(a.proxies, ipNet)
+		}
+
+		if a.roles, err = newRoleMapping(settings); err != nil {
+			return nil, err
+		}
+
+		return a, nil
+	}
+}
+
+func (a *headerAuthenticator) Type() string {
+	return "header"
+}
+
+func (a *headerAuthenticator) Identify(r *http.Request) (*Identity, error) {
+	name := strings.TrimSpace(r.Header.Get(a.header))
+	if name == "" {
+		return nil, nil
+	}
+
+	if !a.trusted(r.RemoteAddr) {
+		a.log.Warning("ignoring %q header from untrusted %s address", a.header, r.RemoteAddr)
+		return nil, nil
+	}
+
+	groups := []string{}
+	if a.groupsHeader != "" {
+		for _, group := range strings.Split(r.Header.Get(a.groupsHeader), ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				groups = append(groups, group)
+			}
+		}
+	}
+
+	identity := &Identity{
+		Name:         name,
+		Role:         a.roles.Role(groups),
+		Organization: a.organization,
+	}
+
+	if a.emailHeader != "" {
+		identity.Email = strings.TrimSpace(r.Header.Get(a.emailHeader))
+	}
+
+	if a.orgHeader != "" {
+		if v := strings.TrimSpace(r.Header.Get(a.orgHeader)); v != "" {
+			identity.Organization = v
+		}
+	}
+
+	if identity.Role == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	return identity, nil
+}
+
+func (a *headerAuthenticator) trusted(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, ipNet := range a.proxies {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
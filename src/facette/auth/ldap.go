@@ -0,0 +1,319 @@
+// +build !disable_auth_ldap
+
+package auth
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/facette/logger"
+	"github.com/facette/maputil"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// ldapAuthenticator implements the authenticator handler for LDAP directories. Users are authenticated by binding
+// with their credentials, either using a DN template or the DN of the entry found by searching the directory.
+type ldapAuthenticator struct {
+	url            string
+	startTLS       bool
+	tlsConfig      *tls.Config
+	timeout        time.Duration
+	bindDN         string
+	bindPassword   string
+	userDN         string
+	baseDN         string
+	userFilter     string
+	emailAttribute string
+	memberOf       string
+	groupBaseDN    string
+	groupFilter    string
+	groupAttribute string
+	orgAttribute   string
+	organization   string
+	roles          *roleMapping
+	log            *logger.Logger
+}
+
+func init() {
+	authenticators["ldap"] = func(settings *maputil.Map, log *logger.Logger) (Authenticator, error) {
+		var err error
+
+		a := &ldapAuthenticator{
+			log: log,
+		}
+
+		// Load authenticator configuration
+		if a.url, err = settings.GetString("url", ""); err != nil {
+			return nil, err
+		} else if a.url == "" {
+			return nil, ErrMissingAuthSetting("url")
+		}
+
+		u, err := url.Parse(a.url)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse URL: %s", err)
+		}
+
+		if a.startTLS, err = settings.GetBool("start_tls", false); err != nil {
+			return nil, err
+		}
+
+		insecure, err := settings.GetBool("insecure_skip_verify", false)
+		if err != nil {
+			return nil, err
+		}
+
+		host, _, err := net.SplitHostPort(u.Host)
+		if err != nil {
+			host = u.Host
+		}
+
+		a.tlsConfig = &tls.Config{ServerName: host, InsecureSkipVerify: insecure}
+
+		timeout, err := settings.GetInt("timeout", authDefaultTimeout)
+		if err != nil {
+			return nil, err
+		}
+		a.timeout = time.Duration(timeout) * time.Second
+
+		if a.bindDN, err = settings.GetString("bind_dn", ""); err != nil {
+			return nil, err
+		}
+
+		if a.bindPassword, err = settings.GetString("bind_password", ""); err != nil {
+			return nil, err
+		}
+
+		// User DN template takes precedence over directory search
+		if a.userDN, err = settings.GetString("user_dn", ""); err != nil {
+			return nil, err
+		}
+
+		if a.baseDN, err = settings.GetString("base_dn", ""); err != nil {
+			return nil, err
+		} else if a.userDN == "" && a.baseDN == "" {
+			return nil, ErrMissingAuthSetting("base_dn")
+		}
+
+		if a.userFilter, err = settings.GetString("user_filter", "(uid=%s)"); err != nil {
+			return nil, err
+		}
+
+		if a.emailAttribute, err = settings.GetString("email_attribute", "mail"); err != nil {
+			return nil, err
+		}
+
+		if a.memberOf, err = settings.GetString("member_of_attribute", "memberOf"); err != nil {
+			return nil, err
+		}
+
+		if a.groupBaseDN, err = settings.GetString("group_base_dn", ""); err != nil {
+			return nil, err
+		}
+
+		if a.groupFilter, err = settings.GetString("group_filter", "(member=%s)"); err != nil {
+			return nil, err
+		}
+
+		if a.groupAttribute, err = settings.GetString("group_attribute", "cn"); err != nil {
+			return nil, err
+		}
+
+		// Organization attribute, if any, takes precedence over the configured one
+		if a.orgAttribute, err = settings.GetString("organization_attribute", ""); err != nil {
+			return nil, err
+		}
+
+		if a.organization, err = settings.GetString("organization", ""); err != nil {
+			return nil, err
+		}
+
+		if a.roles, err = newRoleMapping(settings); err != nil {
+			return nil, err
+		}
+
+		return a, nil
+	}
+}
+
+func (a *ldapAuthenticator) Type() string {
+	return "ldap"
+}
+
+func (a *ldapAuthenticator) Login(name, password string) (*Identity, error) {
+	var entry *ldap.Entry
+
+	// Directory servers accept unauthenticated binds with empty passwords, thus reject them upfront
+	if name == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := a.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	attrs := []string{a.emailAttribute, a.memberOf}
+	if a.orgAttribute != "" {
+		attrs = append(attrs, a.orgAttribute)
+	}
+
+	if a.userDN != "" {
+		dn := fmt.Sprintf(a.userDN, ldapEscapeDN(name))
+
+		if err := conn.Bind(dn, password); err != nil {
+			return nil, ldapError(err)
+		}
+
+		if entry, err = a.search(conn, dn, ldap.ScopeBaseObject, "(objectClass=*)", attrs); err != nil {
+			return nil, err
+		}
+	} else {
+		// Bind with service account if any, or search anonymously
+		if a.bindDN != "" {
+			if err := conn.Bind(a.bindDN, a.bindPassword); err != nil {
+				return nil, err
+			}
+		}
+
+		if entry, err = a.search(conn, a.baseDN, ldap.ScopeWholeSubtree, fmt.Sprintf(a.userFilter,
+			ldap.EscapeFilter(name)), attrs); err != nil {
+			return nil, err
+		}
+
+		if err := conn.Bind(entry.DN, password); err != nil {
+			return nil, ldapError(err)
+		}
+	}
+
+	groups, err := a.groups(conn, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{
+		Name:         name,
+		Email:        entry.GetAttributeValue(a.emailAttribute),
+		Role:         a.roles.Role(groups),
+		Organization: a.organization,
+	}
+
+	if a.orgAttribute != "" {
+		if v := entry.GetAttributeValue(a.orgAttribute); v != "" {
+			identity.Organization = v
+		}
+	}
+
+	if identity.Role == "" {
+		a.log.Warning("%q user doesn't belong to any mapped group", name)
+		return nil, ErrInvalidCredentials
+	}
+
+	return identity, nil
+}
+
+func (a *ldapAuthenticator) dial() (*ldap.Conn, error) {
+	conn, err := ldap.DialURL(a.url, ldap.DialWithDialer(&net.Dialer{Timeout: a.timeout}),
+		ldap.DialWithTLSConfig(a.tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetTimeout(a.timeout)
+
+	if a.startTLS {
+		if err := conn.StartTLS(a.tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+func (a *ldapAuthenticator) search(conn *ldap.Conn, base string, scope int, filter string,
+	attrs []string) (*ldap.Entry, error) {
+
+	result, err := conn.Search(ldap.NewSearchRequest(base, scope, ldap.NeverDerefAliases, 2, int(a.timeout.Seconds()),
+		false, filter, attrs, nil))
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) ||
+		ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, ErrInvalidCredentials
+	} else if err != nil {
+		return nil, err
+	} else if len(result.Entries) != 1 {
+		// Unknown or ambiguous users can't be authenticated
+		return nil, ErrInvalidCredentials
+	}
+
+	return result.Entries[0], nil
+}
+
+func (a *ldapAuthenticator) groups(conn *ldap.Conn, entry *ldap.Entry) ([]string, error) {
+	groups := []string{}
+
+	// Retrieve groups from user entry membership attribute, matching both groups DN and names
+	for _, dn := range entry.GetAttributeValues(a.memberOf) {
+		groups = append(groups, dn)
+
+		if v, err := ldap.ParseDN(dn); err == nil && len(v.RDNs) > 0 && len(v.RDNs[0].Attributes) > 0 {
+			groups = append(groups, v.RDNs[0].Attributes[0].Value)
+		}
+	}
+
+	if a.groupBaseDN == "" {
+		return groups, nil
+	}
+
+	// Search for groups the user is member of, binding back with the service account if any
+	if a.bindDN != "" {
+		if err := conn.Bind(a.bindDN, a.bindPassword); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(a.groupBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, int(a.timeout.Seconds()), false, fmt.Sprintf(a.groupFilter, ldap.EscapeFilter(entry.DN)),
+		[]string{a.groupAttribute}, nil))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range result.Entries {
+		groups = append(groups, e.DN)
+		groups = append(groups, e.GetAttributeValues(a.groupAttribute)...)
+	}
+
+	return groups, nil
+}
+
+func ldapError(err error) error {
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		return ErrInvalidCredentials
+	}
+
+	return err
+}
+
+// ldapEscapeDN escapes special characters from a DN attribute value, as described in RFC 4514.
+func ldapEscapeDN(s string) string {
+	var b strings.Builder
+
+	for i, c := range s {
+		switch {
+		case strings.ContainsRune(`"+,;<>\=`, c),
+			i == 0 && (c == ' ' || c == '#'),
+			i == len(s)-1 && c == ' ':
+			b.WriteRune('\\')
+		}
+
+		b.WriteRune(c)
+	}
+
+	return b.String()
+}
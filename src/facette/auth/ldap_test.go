@@ -0,0 +1,79 @@
+// +build !disable_auth_ldap
+
+package auth
+
+import (
+	"os"
+	"testing"
+
+	"facette/backend"
+
+	"github.com/facette/logger"
+	"github.com/facette/maputil"
+)
+
+// Test_LDAP_Login runs against a directory server (e.g. OpenLDAP or glauth) set using the TEST_LDAP_* environment
+// variables, the test user being expected to belong to the TEST_LDAP_GROUP group.
+func Test_LDAP_Login(t *testing.T) {
+	settings := maputil.Map{"type": "ldap", "default_role": ""}
+
+	for key, env := range map[string]string{
+		"url":           "TEST_LDAP_URL",
+		"base_dn":       "TEST_LDAP_BASE_DN",
+		"bind_dn":       "TEST_LDAP_BIND_DN",
+		"bind_password": "TEST_LDAP_BIND_PASSWORD",
+		"user_filter":   "TEST_LDAP_USER_FILTER",
+		"group_base_dn": "TEST_LDAP_GROUP_BASE_DN",
+		"editor_groups": "TEST_LDAP_GROUP",
+	} {
+		if v := os.Getenv(env); v != "" {
+			settings.Set(key, v)
+		}
+	}
+
+	name, password := os.Getenv("TEST_LDAP_USER"), os.Getenv("TEST_LDAP_PASSWORD")
+
+	if !settings.Has("url") || name == "" || password == "" {
+		t.Skip("missing TEST_LDAP_URL, TEST_LDAP_USER or TEST_LDAP_PASSWORD environment variable")
+	}
+
+	log, _ := logger.NewLogger(logger.FileConfig{Level: "error"})
+
+	a, err := NewAuthenticator(&settings, log)
+	if err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	}
+
+	identity, err := a.(PasswordAuthenticator).Login(name, password)
+	if err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	} else if identity.Name != name || identity.Role != backend.RoleEditor {
+		t.Logf("\nExpected %q user with %q role\nbut got  %#v", name, backend.RoleEditor, identity)
+		t.Fail()
+	}
+
+	for _, entry := range [][2]string{
+		{name, password + "invalid"},
+		{name, ""},
+		{name + "invalid", password},
+	} {
+		if _, err := a.(PasswordAuthenticator).Login(entry[0], entry[1]); err != ErrInvalidCredentials {
+			t.Logf("\nExpected %#v\nbut got  %#v", ErrInvalidCredentials, err)
+			t.Fail()
+		}
+	}
+}
+
+func Test_LDAP_EscapeDN(t *testing.T) {
+	for _, entry := range [][2]string{
+		{"user1", "user1"},
+		{"last, first", `last\, first`},
+		{"#user=1 ", `\#user\=1\ `},
+	} {
+		if result := ldapEscapeDN(entry[0]); result != entry[1] {
+			t.Logf("\nExpected %q\nbut got  %q", entry[1], result)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package backend
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// AccessRead represents a read access permission.
+	AccessRead = "read"
+	// AccessWrite represents a read and write access permission.
+	AccessWrite = "write"
+)
+
+// ACLItem represents a back-end item supporting access control.
+type ACLItem interface {
+	GetACL() *ACL
+}
+
+// ACL represents a back-end item access control list, holding its owner and the permissions granted to other users.
+//
+// Items are visible to every user unless set private, in which case only their owner, administrators and users they
+// have been granted to can see them. Editors can only modify the items they own or have been granted write access
+// to, or items having no owner.
+type ACL struct {
+	OwnerID *string   `gorm:"column:owner;type:varchar(36) DEFAULT NULL REFERENCES users (id) ON DELETE SET NULL ON UPDATE CASCADE" json:"owner,omitempty"`
+	Private bool      `gorm:"not null;default:false" json:"private"`
+	Grants  GrantList `gorm:"type:text" json:"grants,omitempty"`
+}
+
+// GetACL returns the item access control list.
+func (a *ACL) GetACL() *ACL {
+	return a
+}
+
+// BeforeSave handles the ORM 'BeforeSave' callback.
+func (a *ACL) BeforeSave(scope *gorm.Scope) error {
+	for _, grant := range a.Grants {
+		if grant.UserID == "" || grant.Access != AccessRead && grant.Access != AccessWrite {
+			return ErrInvalidGrant
+		}
+	}
+
+	// Ensure optional fields are null if empty
+	if a.OwnerID != nil && *a.OwnerID == "" {
+		scope.SetColumn("OwnerID", nil)
+	}
+
+	return nil
+}
+
+// Clone returns a copy of the access control list.
+func (a *ACL) Clone() *ACL {
+	clone := &ACL{Private: a.Private}
+
+	if a.OwnerID != nil {
+		owner := *a.OwnerID
+		clone.OwnerID = &owner
+	}
+
+	if a.Grants != nil {
+		clone.Grants = append(GrantList{}, a.Grants...)
+	}
+
+	return clone
+}
+
+// IsOwner returns whether or not a user owns the item.
+func (a *ACL) IsOwner(user *User) bool {
+	return user != nil && a.OwnerID != nil && *a.OwnerID == user.ID
+}
+
+// Allowed returns whether or not a user is given a specific access to the item. A nil user stands for an
+// unauthenticated context (i.e. authentication being disabled) and is thus always allowed.
+func (a *ACL) Allowed(user *User, access string) bool {
+	if user == nil || user.Role == RoleAdmin || a.IsOwner(user) {
+		return true
+	} else if access == AccessWrite && user.Role != RoleEditor {
+		return false
+	}
+
+	for _, grant := range a.Grants {
+		if grant.UserID == user.ID && (grant.Access == AccessWrite || access == AccessRead) {
+			return true
+		}
+	}
+
+	if access == AccessRead {
+		return !a.Private
+	}
+
+	return a.OwnerID == nil
+}
+
+// Grant represents an item access permission granted to a user.
+type Grant struct {
+	UserID string `json:"user"`
+	Access string `json:"access"`
+}
+
+// GrantList represents a list of item access permissions.
+type GrantList []Grant
+
+// Value marshals the grants list for compatibility with SQL drivers.
+func (gl GrantList) Value() (driver.Value, error) {
+	data, err := json.Marshal(gl)
+	return data, err
+}
+
+// Scan unmarshals the grants list retrieved from SQL drivers.
+func (gl *GrantList) Scan(v interface{}) error {
+	return scanValue(v, gl)
+}
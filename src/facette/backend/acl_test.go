@@ -0,0 +1,42 @@
+package backend
+
+import "testing"
+
+func Test_ACL_Allowed(t *testing.T) {
+	owner := "00000000-0000-0000-0000-000000000001"
+
+	var (
+		admin  = &User{Item: Item{ID: "00000000-0000-0000-0000-000000000002"}, Role: RoleAdmin}
+		editor = &User{Item: Item{ID: "00000000-0000-0000-0000-000000000003"}, Role: RoleEditor}
+		viewer = &User{Item: Item{ID: "00000000-0000-0000-0000-000000000004"}, Role: RoleViewer}
+		self   = &User{Item: Item{ID: owner}, Role: RoleEditor}
+	)
+
+	for _, entry := range []struct {
+		acl      ACL
+		user     *User
+		access   string
+		expected bool
+	}{
+		{ACL{}, nil, AccessWrite, true},
+		{ACL{}, viewer, AccessRead, true},
+		{ACL{}, viewer, AccessWrite, false},
+		{ACL{}, editor, AccessWrite, true},
+		{ACL{OwnerID: &owner}, self, AccessWrite, true},
+		{ACL{OwnerID: &owner}, admin, AccessWrite, true},
+		{ACL{OwnerID: &owner}, editor, AccessRead, true},
+		{ACL{OwnerID: &owner}, editor, AccessWrite, false},
+		{ACL{OwnerID: &owner, Private: true}, editor, AccessRead, false},
+		{ACL{OwnerID: &owner, Private: true}, admin, AccessRead, true},
+		{ACL{OwnerID: &owner, Private: true, Grants: GrantList{{editor.ID, AccessRead}}}, editor, AccessRead, true},
+		{ACL{OwnerID: &owner, Private: true, Grants: GrantList{{editor.ID, AccessRead}}}, editor, AccessWrite, false},
+		{ACL{OwnerID: &owner, Grants: GrantList{{editor.ID, AccessWrite}}}, editor, AccessWrite, true},
+		{ACL{OwnerID: &owner, Grants: GrantList{{viewer.ID, AccessWrite}}}, viewer, AccessWrite, false},
+	} {
+		if result := entry.acl.Allowed(entry.user, entry.access); result != entry.expected {
+			t.Logf("\nExpected %v\nbut got  %v (acl=%#v user=%#v access=%q)", entry.expected, result, entry.acl,
+				entry.user, entry.access)
+			t.Fail()
+		}
+	}
+}
@@ -36,12 +36,24 @@ func NewBackend(config *maputil.Map, log *logger.Logger) (*Backend, error) {
 
 	// Initialize database schema
 	if err := storage.Migrate(
+		&Organization{},
+		&User{},
+		&Token{},
 		&Provider{},
 		&SourceGroup{},
 		&MetricGroup{},
 		&Graph{},
 		&Collection{},
 		&CollectionEntry{},
+		&Channel{},
+		&AlertRule{},
+		&AlertEvent{},
+		&Annotation{},
+		&Report{},
+		&Revision{},
+		&TrashEntry{},
+		&AuditEntry{},
+		&Snapshot{},
 	); err != nil {
 		return nil, err
 	}
@@ -51,10 +63,39 @@ func NewBackend(config *maputil.Map, log *logger.Logger) (*Backend, error) {
 	if driver, err := config.GetString("driver", ""); err == nil && driver == "mysql" {
 		storage.
 			AddForeignKey(&Graph{}, "link", "graphs(id)", "CASCADE", "CASCADE").
+			AddForeignKey(&Graph{}, "owner", "users(id)", "SET NULL", "CASCADE").
 			AddForeignKey(&CollectionEntry{}, "collection", "collections(id)", "CASCADE", "CASCADE").
 			AddForeignKey(&CollectionEntry{}, "graph", "graphs(id)", "CASCADE", "CASCADE").
 			AddForeignKey(&Collection{}, "link", "collections(id)", "CASCADE", "CASCADE").
-			AddForeignKey(&Collection{}, "parent", "collections(id)", "SET NULL", "SET NULL")
+			AddForeignKey(&Collection{}, "parent", "collections(id)", "SET NULL", "SET NULL").
+			AddForeignKey(&Collection{}, "owner", "users(id)", "SET NULL", "CASCADE").
+			AddForeignKey(&AlertRule{}, "graph", "graphs(id)", "CASCADE", "CASCADE").
+			AddForeignKey(&AlertEvent{}, "rule", "alertrules(id)", "CASCADE", "CASCADE").
+			AddForeignKey(&Annotation{}, "graph", "graphs(id)", "CASCADE", "CASCADE").
+			AddForeignKey(&Annotation{}, "collection", "collections(id)", "CASCADE", "CASCADE").
+			AddForeignKey(&Report{}, "collection", "collections(id)", "CASCADE", "CASCADE").
+			AddForeignKey(&Report{}, "channel", "channels(id)", "SET NULL", "CASCADE").
+			AddForeignKey(&Report{}, "owner", "users(id)", "SET NULL", "CASCADE").
+			AddForeignKey(&Token{}, "user", "users(id)", "CASCADE", "CASCADE")
+
+		for _, item := range []interface{}{&User{}, &Provider{}, &SourceGroup{}, &MetricGroup{}, &Graph{},
+			&Collection{}, &Channel{}, &AlertRule{}, &Annotation{}, &Report{}, &Revision{},
+			&TrashEntry{}, &Snapshot{}} {
+			storage.AddForeignKey(item, "organization", "organizations(id)", "CASCADE", "CASCADE")
+		}
+	}
+
+	// Scope item names and aliases uniqueness to organizations, replacing the global unique indexes created by
+	// previous versions. User names remain unique across organizations.
+	storage.AddUniqueIndex(&User{}, "name")
+
+	for _, item := range []interface{}{&Provider{}, &SourceGroup{}, &MetricGroup{}, &Graph{}, &Collection{},
+		&Channel{}, &AlertRule{}, &Annotation{}, &Report{}} {
+		storage.RemoveUniqueIndex(item, "name").AddUniqueIndex(item, "organization", "name")
+	}
+
+	for _, item := range []interface{}{&Graph{}, &Collection{}} {
+		storage.RemoveUniqueIndex(item, "alias").AddUniqueIndex(item, "organization", "alias")
 	}
 
 	storage.Association(&Collection{}, "Entries")
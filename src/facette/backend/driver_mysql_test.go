@@ -13,12 +13,18 @@ import (
 )
 
 var (
-	mysqlBackend      *Backend
-	mysqlProviders    []*Provider
-	mysqlSourceGroups []*SourceGroup
-	mysqlMetricGroups []*MetricGroup
-	mysqlGraphs       []*Graph
-	mysqlCollections  []*Collection
+	mysqlBackend       *Backend
+	mysqlProviders     []*Provider
+	mysqlSourceGroups  []*SourceGroup
+	mysqlMetricGroups  []*MetricGroup
+	mysqlGraphs        []*Graph
+	mysqlCollections   []*Collection
+	mysqlAlertRules    []*AlertRule
+	mysqlChannels      []*Channel
+	mysqlAnnotations   []*Annotation
+	mysqlReports       []*Report
+	mysqlUsers         []*User
+	mysqlOrganizations []*Organization
 )
 
 func init() {
@@ -56,6 +62,12 @@ func init() {
 	mysqlMetricGroups = testMetricGroupNew()
 	mysqlGraphs = testGraphNew()
 	mysqlCollections = testCollectionNew()
+	mysqlAlertRules = testAlertRuleNew()
+	mysqlChannels = testChannelNew()
+	mysqlAnnotations = testAnnotationNew()
+	mysqlReports = testReportNew()
+	mysqlUsers = testUserNew()
+	mysqlOrganizations = testOrganizationNew()
 }
 
 func Test_MySQL_Providers_Create(t *testing.T) {
@@ -186,6 +198,26 @@ func Test_MySQL_Graphs_Update(t *testing.T) {
 	testGraphUpdate(mysqlBackend, mysqlGraphs, t)
 }
 
+func Test_MySQL_Graphs_Conditional_Save(t *testing.T) {
+	testGraphConditionalSave(mysqlBackend, mysqlGraphs, t)
+}
+
+func Test_MySQL_Graphs_Conflict(t *testing.T) {
+	testGraphConflict(mysqlBackend, mysqlGraphs, t)
+}
+
+func Test_MySQL_Graphs_Revisions(t *testing.T) {
+	testGraphRevisions(mysqlBackend, mysqlGraphs, t)
+}
+
+func Test_MySQL_Graphs_Trash(t *testing.T) {
+	testGraphTrash(mysqlBackend, mysqlGraphs, t)
+}
+
+func Test_MySQL_Graphs_Snapshots(t *testing.T) {
+	testGraphSnapshots(mysqlBackend, mysqlGraphs, t)
+}
+
 func Test_MySQL_Graphs_Delete(t *testing.T) {
 	testGraphDelete(mysqlBackend, mysqlGraphs, t)
 }
@@ -238,6 +270,14 @@ func Test_MySQL_Collections_List(t *testing.T) {
 	testCollectionList(mysqlBackend, mysqlCollections, mysqlGraphs, t)
 }
 
+func Test_MySQL_Collections_Conditional_Save(t *testing.T) {
+	testCollectionConditionalSave(mysqlBackend, mysqlCollections, t)
+}
+
+func Test_MySQL_Collections_Conflict(t *testing.T) {
+	testCollectionConflict(mysqlBackend, mysqlCollections, t)
+}
+
 func Test_MySQL_Collections_Count(t *testing.T) {
 	testCollectionCount(mysqlBackend, mysqlCollections, t)
 }
@@ -257,3 +297,243 @@ func Test_MySQL_Collections_Tree(t *testing.T) {
 func Test_MySQL_Collections_Delete_All(t *testing.T) {
 	testCollectionDeleteAll(mysqlBackend, mysqlCollections, t)
 }
+
+func Test_MySQL_AlertRules_Create(t *testing.T) {
+	testAlertRuleCreate(mysqlBackend, mysqlAlertRules, t)
+}
+
+func Test_MySQL_AlertRules_Create_Invalid(t *testing.T) {
+	testAlertRuleCreateInvalid(mysqlBackend, mysqlAlertRules, t)
+}
+
+func Test_MySQL_AlertRules_Get(t *testing.T) {
+	testAlertRuleGet(mysqlBackend, mysqlAlertRules, t)
+}
+
+func Test_MySQL_AlertRules_Get_Unknown(t *testing.T) {
+	testAlertRuleGetUnknown(mysqlBackend, mysqlAlertRules, t)
+}
+
+func Test_MySQL_AlertRules_Update(t *testing.T) {
+	testAlertRuleUpdate(mysqlBackend, mysqlAlertRules, t)
+}
+
+func Test_MySQL_AlertRules_Delete(t *testing.T) {
+	testAlertRuleDelete(mysqlBackend, mysqlAlertRules, t)
+}
+
+func Test_MySQL_AlertRules_List(t *testing.T) {
+	testAlertRuleList(mysqlBackend, mysqlAlertRules, t)
+}
+
+func Test_MySQL_AlertRules_Count(t *testing.T) {
+	testAlertRuleCount(mysqlBackend, mysqlAlertRules, t)
+}
+
+func Test_MySQL_AlertRules_Delete_All(t *testing.T) {
+	testAlertRuleDeleteAll(mysqlBackend, mysqlAlertRules, t)
+}
+
+func Test_MySQL_Channels_Create(t *testing.T) {
+	testChannelCreate(mysqlBackend, mysqlChannels, t)
+}
+
+func Test_MySQL_Channels_Create_Invalid(t *testing.T) {
+	testChannelCreateInvalid(mysqlBackend, mysqlChannels, t)
+}
+
+func Test_MySQL_Channels_Get(t *testing.T) {
+	testChannelGet(mysqlBackend, mysqlChannels, t)
+}
+
+func Test_MySQL_Channels_Get_Unknown(t *testing.T) {
+	testChannelGetUnknown(mysqlBackend, mysqlChannels, t)
+}
+
+func Test_MySQL_Channels_Update(t *testing.T) {
+	testChannelUpdate(mysqlBackend, mysqlChannels, t)
+}
+
+func Test_MySQL_Channels_Delete(t *testing.T) {
+	testChannelDelete(mysqlBackend, mysqlChannels, t)
+}
+
+func Test_MySQL_Channels_List(t *testing.T) {
+	testChannelList(mysqlBackend, mysqlChannels, t)
+}
+
+func Test_MySQL_Channels_Count(t *testing.T) {
+	testChannelCount(mysqlBackend, mysqlChannels, t)
+}
+
+func Test_MySQL_Channels_Delete_All(t *testing.T) {
+	testChannelDeleteAll(mysqlBackend, mysqlChannels, t)
+}
+
+func Test_MySQL_Annotations_Create(t *testing.T) {
+	testAnnotationCreate(mysqlBackend, mysqlAnnotations, t)
+}
+
+func Test_MySQL_Annotations_Create_Invalid(t *testing.T) {
+	testAnnotationCreateInvalid(mysqlBackend, mysqlAnnotations, t)
+}
+
+func Test_MySQL_Annotations_Get(t *testing.T) {
+	testAnnotationGet(mysqlBackend, mysqlAnnotations, t)
+}
+
+func Test_MySQL_Annotations_Get_Unknown(t *testing.T) {
+	testAnnotationGetUnknown(mysqlBackend, mysqlAnnotations, t)
+}
+
+func Test_MySQL_Annotations_Update(t *testing.T) {
+	testAnnotationUpdate(mysqlBackend, mysqlAnnotations, t)
+}
+
+func Test_MySQL_Annotations_Delete(t *testing.T) {
+	testAnnotationDelete(mysqlBackend, mysqlAnnotations, t)
+}
+
+func Test_MySQL_Annotations_List(t *testing.T) {
+	testAnnotationList(mysqlBackend, mysqlAnnotations, t)
+}
+
+func Test_MySQL_Annotations_Count(t *testing.T) {
+	testAnnotationCount(mysqlBackend, mysqlAnnotations, t)
+}
+
+func Test_MySQL_Annotations_Filter(t *testing.T) {
+	testAnnotationFilter(mysqlBackend, mysqlAnnotations, t)
+}
+
+func Test_MySQL_Annotations_Delete_All(t *testing.T) {
+	testAnnotationDeleteAll(mysqlBackend, mysqlAnnotations, t)
+}
+
+func Test_MySQL_Reports_Create(t *testing.T) {
+	testReportCreate(mysqlBackend, mysqlReports, t)
+}
+
+func Test_MySQL_Reports_Create_Invalid(t *testing.T) {
+	testReportCreateInvalid(mysqlBackend, mysqlReports, t)
+}
+
+func Test_MySQL_Reports_Get(t *testing.T) {
+	testReportGet(mysqlBackend, mysqlReports, t)
+}
+
+func Test_MySQL_Reports_Get_Unknown(t *testing.T) {
+	testReportGetUnknown(mysqlBackend, mysqlReports, t)
+}
+
+func Test_MySQL_Reports_Update(t *testing.T) {
+	testReportUpdate(mysqlBackend, mysqlReports, t)
+}
+
+func Test_MySQL_Reports_Delete(t *testing.T) {
+	testReportDelete(mysqlBackend, mysqlReports, t)
+}
+
+func Test_MySQL_Reports_List(t *testing.T) {
+	testReportList(mysqlBackend, mysqlReports, t)
+}
+
+func Test_MySQL_Reports_Count(t *testing.T) {
+	testReportCount(mysqlBackend, mysqlReports, t)
+}
+
+func Test_MySQL_Reports_Validate(t *testing.T) {
+	testReportValidate(mysqlBackend, mysqlReports, t)
+}
+
+func Test_MySQL_Reports_Delete_All(t *testing.T) {
+	testReportDeleteAll(mysqlBackend, mysqlReports, t)
+}
+
+func Test_MySQL_Users_Create(t *testing.T) {
+	testUserCreate(mysqlBackend, mysqlUsers, t)
+}
+
+func Test_MySQL_Users_Create_Invalid(t *testing.T) {
+	testUserCreateInvalid(mysqlBackend, mysqlUsers, t)
+}
+
+func Test_MySQL_Users_Get(t *testing.T) {
+	testUserGet(mysqlBackend, mysqlUsers, t)
+}
+
+func Test_MySQL_Users_Get_Unknown(t *testing.T) {
+	testUserGetUnknown(mysqlBackend, mysqlUsers, t)
+}
+
+func Test_MySQL_Users_Update(t *testing.T) {
+	testUserUpdate(mysqlBackend, mysqlUsers, t)
+}
+
+func Test_MySQL_Users_Delete(t *testing.T) {
+	testUserDelete(mysqlBackend, mysqlUsers, t)
+}
+
+func Test_MySQL_Users_List(t *testing.T) {
+	testUserList(mysqlBackend, mysqlUsers, t)
+}
+
+func Test_MySQL_Users_Count(t *testing.T) {
+	testUserCount(mysqlBackend, mysqlUsers, t)
+}
+
+func Test_MySQL_Users_Password(t *testing.T) {
+	testUserPassword(mysqlBackend, mysqlUsers, t)
+}
+
+func Test_MySQL_Users_Tokens(t *testing.T) {
+	testUserTokens(mysqlBackend, mysqlUsers, t)
+}
+
+func Test_MySQL_Users_Delete_All(t *testing.T) {
+	testUserDeleteAll(mysqlBackend, mysqlUsers, t)
+}
+
+func Test_MySQL_Organizations_Create(t *testing.T) {
+	testOrganizationCreate(mysqlBackend, mysqlOrganizations, t)
+}
+
+func Test_MySQL_Organizations_Create_Invalid(t *testing.T) {
+	testOrganizationCreateInvalid(mysqlBackend, mysqlOrganizations, t)
+}
+
+func Test_MySQL_Organizations_Get(t *testing.T) {
+	testOrganizationGet(mysqlBackend, mysqlOrganizations, t)
+}
+
+func Test_MySQL_Organizations_Get_Unknown(t *testing.T) {
+	testOrganizationGetUnknown(mysqlBackend, mysqlOrganizations, t)
+}
+
+func Test_MySQL_Organizations_Update(t *testing.T) {
+	testOrganizationUpdate(mysqlBackend, mysqlOrganizations, t)
+}
+
+func Test_MySQL_Organizations_Conditional_Save(t *testing.T) {
+	testOrganizationConditionalSave(mysqlBackend, mysqlOrganizations, t)
+}
+
+func Test_MySQL_Organizations_Delete(t *testing.T) {
+	testOrganizationDelete(mysqlBackend, mysqlOrganizations, t)
+}
+
+func Test_MySQL_Organizations_List(t *testing.T) {
+	testOrganizationList(mysqlBackend, mysqlOrganizations, t)
+}
+
+func Test_MySQL_Organizations_Count(t *testing.T) {
+	testOrganizationCount(mysqlBackend, mysqlOrganizations, t)
+}
+
+func Test_MySQL_Organizations_Items(t *testing.T) {
+	testOrganizationItems(mysqlBackend, mysqlOrganizations, t)
+}
+
+func Test_MySQL_Organizations_Delete_All(t *testing.T) {
+	testOrganizationDeleteAll(mysqlBackend, mysqlOrganizations, t)
+}
@@ -13,12 +13,18 @@ import (
 )
 
 var (
-	pgsqlBackend      *Backend
-	pgsqlProviders    []*Provider
-	pgsqlSourceGroups []*SourceGroup
-	pgsqlMetricGroups []*MetricGroup
-	pgsqlGraphs       []*Graph
-	pgsqlCollections  []*Collection
+	pgsqlBackend       *Backend
+	pgsqlProviders     []*Provider
+	pgsqlSourceGroups  []*SourceGroup
+	pgsqlMetricGroups  []*MetricGroup
+	pgsqlGraphs        []*Graph
+	pgsqlCollections   []*Collection
+	pgsqlAlertRules    []*AlertRule
+	pgsqlChannels      []*Channel
+	pgsqlAnnotations   []*Annotation
+	pgsqlReports       []*Report
+	pgsqlUsers         []*User
+	pgsqlOrganizations []*Organization
 )
 
 func init() {
@@ -58,6 +64,12 @@ func init() {
 	pgsqlMetricGroups = testMetricGroupNew()
 	pgsqlGraphs = testGraphNew()
 	pgsqlCollections = testCollectionNew()
+	pgsqlAlertRules = testAlertRuleNew()
+	pgsqlChannels = testChannelNew()
+	pgsqlAnnotations = testAnnotationNew()
+	pgsqlReports = testReportNew()
+	pgsqlUsers = testUserNew()
+	pgsqlOrganizations = testOrganizationNew()
 }
 
 func Test_PgSQL_Providers_Create(t *testing.T) {
@@ -188,6 +200,26 @@ func Test_PgSQL_Graphs_Update(t *testing.T) {
 	testGraphUpdate(pgsqlBackend, pgsqlGraphs, t)
 }
 
+func Test_PgSQL_Graphs_Conditional_Save(t *testing.T) {
+	testGraphConditionalSave(pgsqlBackend, pgsqlGraphs, t)
+}
+
+func Test_PgSQL_Graphs_Conflict(t *testing.T) {
+	testGraphConflict(pgsqlBackend, pgsqlGraphs, t)
+}
+
+func Test_PgSQL_Graphs_Revisions(t *testing.T) {
+	testGraphRevisions(pgsqlBackend, pgsqlGraphs, t)
+}
+
+func Test_PgSQL_Graphs_Trash(t *testing.T) {
+	testGraphTrash(pgsqlBackend, pgsqlGraphs, t)
+}
+
+func Test_PgSQL_Graphs_Snapshots(t *testing.T) {
+	testGraphSnapshots(pgsqlBackend, pgsqlGraphs, t)
+}
+
 func Test_PgSQL_Graphs_Delete(t *testing.T) {
 	testGraphDelete(pgsqlBackend, pgsqlGraphs, t)
 }
@@ -240,6 +272,14 @@ func Test_PgSQL_Collections_List(t *testing.T) {
 	testCollectionList(pgsqlBackend, pgsqlCollections, pgsqlGraphs, t)
 }
 
+func Test_PgSQL_Collections_Conditional_Save(t *testing.T) {
+	testCollectionConditionalSave(pgsqlBackend, pgsqlCollections, t)
+}
+
+func Test_PgSQL_Collections_Conflict(t *testing.T) {
+	testCollectionConflict(pgsqlBackend, pgsqlCollections, t)
+}
+
 func Test_PgSQL_Collections_Count(t *testing.T) {
 	testCollectionCount(pgsqlBackend, pgsqlCollections, t)
 }
@@ -259,3 +299,243 @@ func Test_PgSQL_Collections_Tree(t *testing.T) {
 func Test_PgSQL_Collections_Delete_All(t *testing.T) {
 	testCollectionDeleteAll(pgsqlBackend, pgsqlCollections, t)
 }
+
+func Test_PgSQL_AlertRules_Create(t *testing.T) {
+	testAlertRuleCreate(pgsqlBackend, pgsqlAlertRules, t)
+}
+
+func Test_PgSQL_AlertRules_Create_Invalid(t *testing.T) {
+	testAlertRuleCreateInvalid(pgsqlBackend, pgsqlAlertRules, t)
+}
+
+func Test_PgSQL_AlertRules_Get(t *testing.T) {
+	testAlertRuleGet(pgsqlBackend, pgsqlAlertRules, t)
+}
+
+func Test_PgSQL_AlertRules_Get_Unknown(t *testing.T) {
+	testAlertRuleGetUnknown(pgsqlBackend, pgsqlAlertRules, t)
+}
+
+func Test_PgSQL_AlertRules_Update(t *testing.T) {
+	testAlertRuleUpdate(pgsqlBackend, pgsqlAlertRules, t)
+}
+
+func Test_PgSQL_AlertRules_Delete(t *testing.T) {
+	testAlertRuleDelete(pgsqlBackend, pgsqlAlertRules, t)
+}
+
+func Test_PgSQL_AlertRules_List(t *testing.T) {
+	testAlertRuleList(pgsqlBackend, pgsqlAlertRules, t)
+}
+
+func Test_PgSQL_AlertRules_Count(t *testing.T) {
+	testAlertRuleCount(pgsqlBackend, pgsqlAlertRules, t)
+}
+
+func Test_PgSQL_AlertRules_Delete_All(t *testing.T) {
+	testAlertRuleDeleteAll(pgsqlBackend, pgsqlAlertRules, t)
+}
+
+func Test_PgSQL_Channels_Create(t *testing.T) {
+	testChannelCreate(pgsqlBackend, pgsqlChannels, t)
+}
+
+func Test_PgSQL_Channels_Create_Invalid(t *testing.T) {
+	testChannelCreateInvalid(pgsqlBackend, pgsqlChannels, t)
+}
+
+func Test_PgSQL_Channels_Get(t *testing.T) {
+	testChannelGet(pgsqlBackend, pgsqlChannels, t)
+}
+
+func Test_PgSQL_Channels_Get_Unknown(t *testing.T) {
+	testChannelGetUnknown(pgsqlBackend, pgsqlChannels, t)
+}
+
+func Test_PgSQL_Channels_Update(t *testing.T) {
+	testChannelUpdate(pgsqlBackend, pgsqlChannels, t)
+}
+
+func Test_PgSQL_Channels_Delete(t *testing.T) {
+	testChannelDelete(pgsqlBackend, pgsqlChannels, t)
+}
+
+func Test_PgSQL_Channels_List(t *testing.T) {
+	testChannelList(pgsqlBackend, pgsqlChannels, t)
+}
+
+func Test_PgSQL_Channels_Count(t *testing.T) {
+	testChannelCount(pgsqlBackend, pgsqlChannels, t)
+}
+
+func Test_PgSQL_Channels_Delete_All(t *testing.T) {
+	testChannelDeleteAll(pgsqlBackend, pgsqlChannels, t)
+}
+
+func Test_PgSQL_Annotations_Create(t *testing.T) {
+	testAnnotationCreate(pgsqlBackend, pgsqlAnnotations, t)
+}
+
+func Test_PgSQL_Annotations_Create_Invalid(t *testing.T) {
+	testAnnotationCreateInvalid(pgsqlBackend, pgsqlAnnotations, t)
+}
+
+func Test_PgSQL_Annotations_Get(t *testing.T) {
+	testAnnotationGet(pgsqlBackend, pgsqlAnnotations, t)
+}
+
+func Test_PgSQL_Annotations_Get_Unknown(t *testing.T) {
+	testAnnotationGetUnknown(pgsqlBackend, pgsqlAnnotations, t)
+}
+
+func Test_PgSQL_Annotations_Update(t *testing.T) {
+	testAnnotationUpdate(pgsqlBackend, pgsqlAnnotations, t)
+}
+
+func Test_PgSQL_Annotations_Delete(t *testing.T) {
+	testAnnotationDelete(pgsqlBackend, pgsqlAnnotations, t)
+}
+
+func Test_PgSQL_Annotations_List(t *testing.T) {
+	testAnnotationList(pgsqlBackend, pgsqlAnnotations, t)
+}
+
+func Test_PgSQL_Annotations_Count(t *testing.T) {
+	testAnnotationCount(pgsqlBackend, pgsqlAnnotations, t)
+}
+
+func Test_PgSQL_Annotations_Filter(t *testing.T) {
+	testAnnotationFilter(pgsqlBackend, pgsqlAnnotations, t)
+}
+
+func Test_PgSQL_Annotations_Delete_All(t *testing.T) {
+	testAnnotationDeleteAll(pgsqlBackend, pgsqlAnnotations, t)
+}
+
+func Test_PgSQL_Reports_Create(t *testing.T) {
+	testReportCreate(pgsqlBackend, pgsqlReports, t)
+}
+
+func Test_PgSQL_Reports_Create_Invalid(t *testing.T) {
+	testReportCreateInvalid(pgsqlBackend, pgsqlReports, t)
+}
+
+func Test_PgSQL_Reports_Get(t *testing.T) {
+	testReportGet(pgsqlBackend, pgsqlReports, t)
+}
+
+func Test_PgSQL_Reports_Get_Unknown(t *testing.T) {
+	testReportGetUnknown(pgsqlBackend, pgsqlReports, t)
+}
+
+func Test_PgSQL_Reports_Update(t *testing.T) {
+	testReportUpdate(pgsqlBackend, pgsqlReports, t)
+}
+
+func Test_PgSQL_Reports_Delete(t *testing.T) {
+	testReportDelete(pgsqlBackend, pgsqlReports, t)
+}
+
+func Test_PgSQL_Reports_List(t *testing.T) {
+	testReportList(pgsqlBackend, pgsqlReports, t)
+}
+
+func Test_PgSQL_Reports_Count(t *testing.T) {
+	testReportCount(pgsqlBackend, pgsqlReports, t)
+}
+
+func Test_PgSQL_Reports_Validate(t *testing.T) {
+	testReportValidate(pgsqlBackend, pgsqlReports, t)
+}
+
+func Test_PgSQL_Reports_Delete_All(t *testing.T) {
+	testReportDeleteAll(pgsqlBackend, pgsqlReports, t)
+}
+
+func Test_PgSQL_Users_Create(t *testing.T) {
+	testUserCreate(pgsqlBackend, pgsqlUsers, t)
+}
+
+func Test_PgSQL_Users_Create_Invalid(t *testing.T) {
+	testUserCreateInvalid(pgsqlBackend, pgsqlUsers, t)
+}
+
+func Test_PgSQL_Users_Get(t *testing.T) {
+	testUserGet(pgsqlBackend, pgsqlUsers, t)
+}
+
+func Test_PgSQL_Users_Get_Unknown(t *testing.T) {
+	testUserGetUnknown(pgsqlBackend, pgsqlUsers, t)
+}
+
+func Test_PgSQL_Users_Update(t *testing.T) {
+	testUserUpdate(pgsqlBackend, pgsqlUsers, t)
+}
+
+func Test_PgSQL_Users_Delete(t *testing.T) {
+	testUserDelete(pgsqlBackend, pgsqlUsers, t)
+}
+
+func Test_PgSQL_Users_List(t *testing.T) {
+	testUserList(pgsqlBackend, pgsqlUsers, t)
+}
+
+func Test_PgSQL_Users_Count(t *testing.T) {
+	testUserCount(pgsqlBackend, pgsqlUsers, t)
+}
+
+func Test_PgSQL_Users_Password(t *testing.T) {
+	testUserPassword(pgsqlBackend, pgsqlUsers, t)
+}
+
+func Test_PgSQL_Users_Tokens(t *testing.T) {
+	testUserTokens(pgsqlBackend, pgsqlUsers, t)
+}
+
+func Test_PgSQL_Users_Delete_All(t *testing.T) {
+	testUserDeleteAll(pgsqlBackend, pgsqlUsers, t)
+}
+
+func Test_PgSQL_Organizations_Create(t *testing.T) {
+	testOrganizationCreate(pgsqlBackend, pgsqlOrganizations, t)
+}
+
+func Test_PgSQL_Organizations_Create_Invalid(t *testing.T) {
+	testOrganizationCreateInvalid(pgsqlBackend, pgsqlOrganizations, t)
+}
+
+func Test_PgSQL_Organizations_Get(t *testing.T) {
+	testOrganizationGet(pgsqlBackend, pgsqlOrganizations, t)
+}
+
+func Test_PgSQL_Organizations_Get_Unknown(t *testing.T) {
+	testOrganizationGetUnknown(pgsqlBackend, pgsqlOrganizations, t)
+}
+
+func Test_PgSQL_Organizations_Update(t *testing.T) {
+	testOrganizationUpdate(pgsqlBackend, pgsqlOrganizations, t)
+}
+
+func Test_PgSQL_Organizations_Conditional_Save(t *testing.T) {
+	testOrganizationConditionalSave(pgsqlBackend, pgsqlOrganizations, t)
+}
+
+func Test_PgSQL_Organizations_Delete(t *testing.T) {
+	testOrganizationDelete(pgsqlBackend, pgsqlOrganizations, t)
+}
+
+func Test_PgSQL_Organizations_List(t *testing.T) {
+	testOrganizationList(pgsqlBackend, pgsqlOrganizations, t)
+}
+
+func Test_PgSQL_Organizations_Count(t *testing.T) {
+	testOrganizationCount(pgsqlBackend, pgsqlOrganizations, t)
+}
+
+func Test_PgSQL_Organizations_Items(t *testing.T) {
+	testOrganizationItems(pgsqlBackend, pgsqlOrganizations, t)
+}
+
+func Test_PgSQL_Organizations_Delete_All(t *testing.T) {
+	testOrganizationDeleteAll(pgsqlBackend, pgsqlOrganizations, t)
+}
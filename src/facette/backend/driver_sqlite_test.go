@@ -13,13 +13,19 @@ import (
 )
 
 var (
-	sqliteBackend      *Backend
-	sqliteProviders    []*Provider
-	sqliteSourceGroups []*SourceGroup
-	sqliteMetricGroups []*MetricGroup
-	sqliteGraphs       []*Graph
-	sqliteCollections  []*Collection
-	sqliteTempFile     string
+	sqliteBackend       *Backend
+	sqliteProviders     []*Provider
+	sqliteSourceGroups  []*SourceGroup
+	sqliteMetricGroups  []*MetricGroup
+	sqliteGraphs        []*Graph
+	sqliteCollections   []*Collection
+	sqliteAlertRules    []*AlertRule
+	sqliteChannels      []*Channel
+	sqliteAnnotations   []*Annotation
+	sqliteReports       []*Report
+	sqliteUsers         []*User
+	sqliteOrganizations []*Organization
+	sqliteTempFile      string
 )
 
 func init() {
@@ -49,6 +55,12 @@ func init() {
 	sqliteMetricGroups = testMetricGroupNew()
 	sqliteGraphs = testGraphNew()
 	sqliteCollections = testCollectionNew()
+	sqliteAlertRules = testAlertRuleNew()
+	sqliteChannels = testChannelNew()
+	sqliteAnnotations = testAnnotationNew()
+	sqliteReports = testReportNew()
+	sqliteUsers = testUserNew()
+	sqliteOrganizations = testOrganizationNew()
 }
 
 func Test_SQLite_Providers_Create(t *testing.T) {
@@ -179,6 +191,26 @@ func Test_SQLite_Graphs_Update(t *testing.T) {
 	testGraphUpdate(sqliteBackend, sqliteGraphs, t)
 }
 
+func Test_SQLite_Graphs_Conditional_Save(t *testing.T) {
+	testGraphConditionalSave(sqliteBackend, sqliteGraphs, t)
+}
+
+func Test_SQLite_Graphs_Conflict(t *testing.T) {
+	testGraphConflict(sqliteBackend, sqliteGraphs, t)
+}
+
+func Test_SQLite_Graphs_Revisions(t *testing.T) {
+	testGraphRevisions(sqliteBackend, sqliteGraphs, t)
+}
+
+func Test_SQLite_Graphs_Trash(t *testing.T) {
+	testGraphTrash(sqliteBackend, sqliteGraphs, t)
+}
+
+func Test_SQLite_Graphs_Snapshots(t *testing.T) {
+	testGraphSnapshots(sqliteBackend, sqliteGraphs, t)
+}
+
 func Test_SQLite_Graphs_Delete(t *testing.T) {
 	testGraphDelete(sqliteBackend, sqliteGraphs, t)
 }
@@ -231,6 +263,14 @@ func Test_SQLite_Collections_List(t *testing.T) {
 	testCollectionList(sqliteBackend, sqliteCollections, sqliteGraphs, t)
 }
 
+func Test_SQLite_Collections_Conditional_Save(t *testing.T) {
+	testCollectionConditionalSave(sqliteBackend, sqliteCollections, t)
+}
+
+func Test_SQLite_Collections_Conflict(t *testing.T) {
+	testCollectionConflict(sqliteBackend, sqliteCollections, t)
+}
+
 func Test_SQLite_Collections_Count(t *testing.T) {
 	testCollectionCount(sqliteBackend, sqliteCollections, t)
 }
@@ -251,6 +291,246 @@ func Test_SQLite_Collections_Delete_All(t *testing.T) {
 	testCollectionDeleteAll(sqliteBackend, sqliteCollections, t)
 }
 
+func Test_SQLite_AlertRules_Create(t *testing.T) {
+	testAlertRuleCreate(sqliteBackend, sqliteAlertRules, t)
+}
+
+func Test_SQLite_AlertRules_Create_Invalid(t *testing.T) {
+	testAlertRuleCreateInvalid(sqliteBackend, sqliteAlertRules, t)
+}
+
+func Test_SQLite_AlertRules_Get(t *testing.T) {
+	testAlertRuleGet(sqliteBackend, sqliteAlertRules, t)
+}
+
+func Test_SQLite_AlertRules_Get_Unknown(t *testing.T) {
+	testAlertRuleGetUnknown(sqliteBackend, sqliteAlertRules, t)
+}
+
+func Test_SQLite_AlertRules_Update(t *testing.T) {
+	testAlertRuleUpdate(sqliteBackend, sqliteAlertRules, t)
+}
+
+func Test_SQLite_AlertRules_Delete(t *testing.T) {
+	testAlertRuleDelete(sqliteBackend, sqliteAlertRules, t)
+}
+
+func Test_SQLite_AlertRules_List(t *testing.T) {
+	testAlertRuleList(sqliteBackend, sqliteAlertRules, t)
+}
+
+func Test_SQLite_AlertRules_Count(t *testing.T) {
+	testAlertRuleCount(sqliteBackend, sqliteAlertRules, t)
+}
+
+func Test_SQLite_AlertRules_Delete_All(t *testing.T) {
+	testAlertRuleDeleteAll(sqliteBackend, sqliteAlertRules, t)
+}
+
+func Test_SQLite_Channels_Create(t *testing.T) {
+	testChannelCreate(sqliteBackend, sqliteChannels, t)
+}
+
+func Test_SQLite_Channels_Create_Invalid(t *testing.T) {
+	testChannelCreateInvalid(sqliteBackend, sqliteChannels, t)
+}
+
+func Test_SQLite_Channels_Get(t *testing.T) {
+	testChannelGet(sqliteBackend, sqliteChannels, t)
+}
+
+func Test_SQLite_Channels_Get_Unknown(t *testing.T) {
+	testChannelGetUnknown(sqliteBackend, sqliteChannels, t)
+}
+
+func Test_SQLite_Channels_Update(t *testing.T) {
+	testChannelUpdate(sqliteBackend, sqliteChannels, t)
+}
+
+func Test_SQLite_Channels_Delete(t *testing.T) {
+	testChannelDelete(sqliteBackend, sqliteChannels, t)
+}
+
+func Test_SQLite_Channels_List(t *testing.T) {
+	testChannelList(sqliteBackend, sqliteChannels, t)
+}
+
+func Test_SQLite_Channels_Count(t *testing.T) {
+	testChannelCount(sqliteBackend, sqliteChannels, t)
+}
+
+func Test_SQLite_Channels_Delete_All(t *testing.T) {
+	testChannelDeleteAll(sqliteBackend, sqliteChannels, t)
+}
+
+func Test_SQLite_Annotations_Create(t *testing.T) {
+	testAnnotationCreate(sqliteBackend, sqliteAnnotations, t)
+}
+
+func Test_SQLite_Annotations_Create_Invalid(t *testing.T) {
+	testAnnotationCreateInvalid(sqliteBackend, sqliteAnnotations, t)
+}
+
+func Test_SQLite_Annotations_Get(t *testing.T) {
+	testAnnotationGet(sqliteBackend, sqliteAnnotations, t)
+}
+
+func Test_SQLite_Annotations_Get_Unknown(t *testing.T) {
+	testAnnotationGetUnknown(sqliteBackend, sqliteAnnotations, t)
+}
+
+func Test_SQLite_Annotations_Update(t *testing.T) {
+	testAnnotationUpdate(sqliteBackend, sqliteAnnotations, t)
+}
+
+func Test_SQLite_Annotations_Delete(t *testing.T) {
+	testAnnotationDelete(sqliteBackend, sqliteAnnotations, t)
+}
+
+func Test_SQLite_Annotations_List(t *testing.T) {
+	testAnnotationList(sqliteBackend, sqliteAnnotations, t)
+}
+
+func Test_SQLite_Annotations_Count(t *testing.T) {
+	testAnnotationCount(sqliteBackend, sqliteAnnotations, t)
+}
+
+func Test_SQLite_Annotations_Filter(t *testing.T) {
+	testAnnotationFilter(sqliteBackend, sqliteAnnotations, t)
+}
+
+func Test_SQLite_Annotations_Delete_All(t *testing.T) {
+	testAnnotationDeleteAll(sqliteBackend, sqliteAnnotations, t)
+}
+
+func Test_SQLite_Reports_Create(t *testing.T) {
+	testReportCreate(sqliteBackend, sqliteReports, t)
+}
+
+func Test_SQLite_Reports_Create_Invalid(t *testing.T) {
+	testReportCreateInvalid(sqliteBackend, sqliteReports, t)
+}
+
+func Test_SQLite_Reports_Get(t *testing.T) {
+	testReportGet(sqliteBackend, sqliteReports, t)
+}
+
+func Test_SQLite_Reports_Get_Unknown(t *testing.T) {
+	testReportGetUnknown(sqliteBackend, sqliteReports, t)
+}
+
+func Test_SQLite_Reports_Update(t *testing.T) {
+	testReportUpdate(sqliteBackend, sqliteReports, t)
+}
+
+func Test_SQLite_Reports_Delete(t *testing.T) {
+	testReportDelete(sqliteBackend, sqliteReports, t)
+}
+
+func Test_SQLite_Reports_List(t *testing.T) {
+	testReportList(sqliteBackend, sqliteReports, t)
+}
+
+func Test_SQLite_Reports_Count(t *testing.T) {
+	testReportCount(sqliteBackend, sqliteReports, t)
+}
+
+func Test_SQLite_Reports_Validate(t *testing.T) {
+	testReportValidate(sqliteBackend, sqliteReports, t)
+}
+
+func Test_SQLite_Reports_Delete_All(t *testing.T) {
+	testReportDeleteAll(sqliteBackend, sqliteReports, t)
+}
+
+func Test_SQLite_Users_Create(t *testing.T) {
+	testUserCreate(sqliteBackend, sqliteUsers, t)
+}
+
+func Test_SQLite_Users_Create_Invalid(t *testing.T) {
+	testUserCreateInvalid(sqliteBackend, sqliteUsers, t)
+}
+
+func Test_SQLite_Users_Get(t *testing.T) {
+	testUserGet(sqliteBackend, sqliteUsers, t)
+}
+
+func Test_SQLite_Users_Get_Unknown(t *testing.T) {
+	testUserGetUnknown(sqliteBackend, sqliteUsers, t)
+}
+
+func Test_SQLite_Users_Update(t *testing.T) {
+	testUserUpdate(sqliteBackend, sqliteUsers, t)
+}
+
+func Test_SQLite_Users_Delete(t *testing.T) {
+	testUserDelete(sqliteBackend, sqliteUsers, t)
+}
+
+func Test_SQLite_Users_List(t *testing.T) {
+	testUserList(sqliteBackend, sqliteUsers, t)
+}
+
+func Test_SQLite_Users_Count(t *testing.T) {
+	testUserCount(sqliteBackend, sqliteUsers, t)
+}
+
+func Test_SQLite_Users_Password(t *testing.T) {
+	testUserPassword(sqliteBackend, sqliteUsers, t)
+}
+
+func Test_SQLite_Users_Tokens(t *testing.T) {
+	testUserTokens(sqliteBackend, sqliteUsers, t)
+}
+
+func Test_SQLite_Users_Delete_All(t *testing.T) {
+	testUserDeleteAll(sqliteBackend, sqliteUsers, t)
+}
+
+func Test_SQLite_Organizations_Create(t *testing.T) {
+	testOrganizationCreate(sqliteBackend, sqliteOrganizations, t)
+}
+
+func Test_SQLite_Organizations_Create_Invalid(t *testing.T) {
+	testOrganizationCreateInvalid(sqliteBackend, sqliteOrganizations, t)
+}
+
+func Test_SQLite_Organizations_Get(t *testing.T) {
+	testOrganizationGet(sqliteBackend, sqliteOrganizations, t)
+}
+
+func Test_SQLite_Organizations_Get_Unknown(t *testing.T) {
+	testOrganizationGetUnknown(sqliteBackend, sqliteOrganizations, t)
+}
+
+func Test_SQLite_Organizations_Update(t *testing.T) {
+	testOrganizationUpdate(sqliteBackend, sqliteOrganizations, t)
+}
+
+func Test_SQLite_Organizations_Conditional_Save(t *testing.T) {
+	testOrganizationConditionalSave(sqliteBackend, sqliteOrganizations, t)
+}
+
+func Test_SQLite_Organizations_Delete(t *testing.T) {
+	testOrganizationDelete(sqliteBackend, sqliteOrganizations, t)
+}
+
+func Test_SQLite_Organizations_List(t *testing.T) {
+	testOrganizationList(sqliteBackend, sqliteOrganizations, t)
+}
+
+func Test_SQLite_Organizations_Count(t *testing.T) {
+	testOrganizationCount(sqliteBackend, sqliteOrganizations, t)
+}
+
+func Test_SQLite_Organizations_Items(t *testing.T) {
+	testOrganizationItems(sqliteBackend, sqliteOrganizations, t)
+}
+
+func Test_SQLite_Organizations_Delete_All(t *testing.T) {
+	testOrganizationDeleteAll(sqliteBackend, sqliteOrganizations, t)
+}
+
 func Test_SQLite_Cleanup(t *testing.T) {
 	os.Remove(sqliteTempFile)
 }
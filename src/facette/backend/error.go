@@ -5,14 +5,34 @@ import "errors"
 var (
 	// ErrInvalidAlias represents an invalid alias error.
 	ErrInvalidAlias = errors.New("invalid alias")
+	// ErrInvalidCondition represents an invalid alert condition error.
+	ErrInvalidCondition = errors.New("invalid condition")
+	// ErrInvalidGrant represents an invalid permission grant error.
+	ErrInvalidGrant = errors.New("invalid grant")
 	// ErrInvalidID represents an invalid identifier error.
 	ErrInvalidID = errors.New("invalid identifier")
 	// ErrInvalidInterval represents an invalid interval error.
 	ErrInvalidInterval = errors.New("invalid interval")
+	// ErrInvalidKind represents an invalid kind error.
+	ErrInvalidKind = errors.New("invalid kind")
 	// ErrInvalidName represents an invalid name error.
 	ErrInvalidName = errors.New("invalid name")
+	// ErrInvalidPassword represents an invalid password error.
+	ErrInvalidPassword = errors.New("invalid password")
 	// ErrInvalidPriority represents an invalid priority error.
 	ErrInvalidPriority = errors.New("invalid priority")
+	// ErrInvalidRole represents an invalid user role error.
+	ErrInvalidRole = errors.New("invalid role")
+	// ErrInvalidSchedule represents an invalid schedule error.
+	ErrInvalidSchedule = errors.New("invalid schedule")
+	// ErrInvalidTarget represents an invalid target error.
+	ErrInvalidTarget = errors.New("invalid target")
+	// ErrInvalidThreshold represents an invalid alert threshold error.
+	ErrInvalidThreshold = errors.New("invalid threshold")
+	// ErrInvalidTimerange represents an invalid time range error.
+	ErrInvalidTimerange = errors.New("invalid time range")
+	// ErrItemModified represents a concurrently modified item error.
+	ErrItemModified = errors.New("item modified")
 	// ErrUnresolvableItem represents an unresolvable item error.
 	ErrUnresolvableItem = errors.New("unresolvable item")
 	// ErrUnscannableValue represents an unscannable value error.
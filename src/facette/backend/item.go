@@ -1,22 +1,31 @@
 package backend
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/facette/sqlstorage"
 	"github.com/hashicorp/go-uuid"
 	"github.com/jinzhu/gorm"
 )
 
-// Item represents a back-end item instance.
+// Item represents a back-end item instance. Item names are unique within their organization.
 type Item struct {
-	Type        string    `gorm:"-" json:"type,omitempty"`
-	ID          string    `gorm:"type:varchar(36);not null;primary_key" json:"id"`
-	Name        string    `gorm:"type:varchar(128);not null;unique_index" json:"name"`
-	Description *string   `gorm:"type:text" json:"description"`
-	Created     time.Time `gorm:"not null;default:current_timestamp" json:"created"`
-	Modified    time.Time `gorm:"not null;default:current_timestamp" json:"modified"`
+	Type           string    `gorm:"-" json:"type,omitempty"`
+	ID             string    `gorm:"type:varchar(36);not null;primary_key" json:"id"`
+	Name           string    `gorm:"type:varchar(128);not null;index" json:"name"`
+	Description    *string   `gorm:"type:text" json:"description"`
+	OrganizationID *string   `gorm:"column:organization;type:varchar(36) DEFAULT NULL REFERENCES organizations (id) ON DELETE CASCADE ON UPDATE CASCADE" json:"organization,omitempty"`
+	Created        time.Time `gorm:"not null;default:current_timestamp" json:"created"`
+	Modified       time.Time `gorm:"not null;default:current_timestamp" json:"modified"`
 
-	backend *Backend
+	backend  *Backend
+	expected *time.Time
+}
+
+// ConditionalItem represents a back-end item supporting conditional saves.
+type ConditionalItem interface {
+	ExpectModified(modified time.Time)
 }
 
 // BeforeSave handles the ORM 'BeforeSave' callback.
@@ -34,9 +43,16 @@ func (i *Item) BeforeSave(scope *gorm.Scope) error {
 
 	if !nameRegexp.MatchString(i.Name) {
 		return ErrInvalidName
+	} else if err := checkUnique(scope, i.ID, "name", i.Name, i.OrganizationID); err != nil {
+		return err
 	}
 
-	now := time.Now().UTC().Round(time.Second)
+	now, err := conditionalSave(scope, i.ID, i.expected)
+	if err != nil {
+		return err
+	}
+
+	i.expected = nil
 
 	if i.Created.IsZero() {
 		scope.SetColumn("Created", now)
@@ -49,10 +65,93 @@ func (i *Item) BeforeSave(scope *gorm.Scope) error {
 		scope.SetColumn("Description", nil)
 	}
 
+	if i.OrganizationID != nil && *i.OrganizationID == "" {
+		scope.SetColumn("OrganizationID", nil)
+	}
+
 	return nil
 }
 
+// ExpectModified makes the next save of the item conditional to its stored modification time, the save failing with
+// ErrItemModified if the item has been modified in the meantime.
+func (i *Item) ExpectModified(modified time.Time) {
+	i.expected = &modified
+}
+
+// GetOrganization returns the identifier of the organization the item belongs to, or an empty string if none.
+func (i *Item) GetOrganization() string {
+	if i.OrganizationID == nil {
+		return ""
+	}
+
+	return *i.OrganizationID
+}
+
+// SetOrganization sets the organization the item belongs to given its identifier, an empty identifier meaning none.
+func (i *Item) SetOrganization(id string) {
+	if id == "" {
+		i.OrganizationID = nil
+	} else {
+		i.OrganizationID = &id
+	}
+}
+
 // SetBackend sets the item internal back-end reference.
 func (i *Item) SetBackend(b *Backend) {
 	i.backend = b
 }
+
+// conditionalSave returns the modification time to save an item with. If an expected modification time is given, it
+// also updates the stored one provided it still matches within the save transaction, thus returning ErrItemModified
+// if the item has been modified in the meantime.
+func conditionalSave(scope *gorm.Scope, id string, expected *time.Time) (time.Time, error) {
+	now := time.Now().UTC().Round(time.Second)
+	if expected == nil {
+		return now, nil
+	}
+
+	// Ensure modification time always increases, as modifications happening within the same second would otherwise
+	// remain unnoticed
+	if !now.After(*expected) {
+		now = expected.Add(time.Second)
+	}
+
+	result := scope.NewDB().Exec(fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ? AND %s = ?", scope.QuotedTableName(),
+		scope.Quote("modified"), scope.Quote("id"), scope.Quote("modified")), now, id, *expected)
+	if result.Error != nil {
+		return now, result.Error
+	} else if result.RowsAffected == 0 {
+		// Roll back the save transaction, as the storage would otherwise commit the item associations already
+		// removed prior to saving it
+		scope.NewDB().Rollback()
+		return now, ErrItemModified
+	}
+
+	return now, nil
+}
+
+// checkUnique returns ErrItemConflict if another item stored in the same table and organization already has the given
+// column value. Unique indexes over the organization column don't apply to items having no organization, as NULL
+// values are always distinct.
+func checkUnique(scope *gorm.Scope, id, column string, value interface{}, organization *string) error {
+	db := scope.NewDB().Table(scope.TableName()).
+		Where(fmt.Sprintf("%s = ? AND %s <> ?", scope.Quote(column), scope.Quote("id")), value, id)
+
+	if organization != nil && *organization != "" {
+		db = db.Where(fmt.Sprintf("%s = ?", scope.Quote("organization")), *organization)
+	} else {
+		db = db.Where(fmt.Sprintf("%s IS NULL", scope.Quote("organization")))
+	}
+
+	var count int
+	if err := db.Count(&count).Error; err != nil {
+		return err
+	} else if count > 0 {
+		// Roll back the save transaction, as the storage would otherwise commit the item associations already
+		// removed prior to saving it
+		scope.NewDB().Rollback()
+		return sqlstorage.ErrItemConflict
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package backend
+
+import (
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// AlertConditionAbove represents a value above threshold alert condition.
+	AlertConditionAbove = "above"
+	// AlertConditionBelow represents a value below threshold alert condition.
+	AlertConditionBelow = "below"
+	// AlertConditionAbsent represents a missing data alert condition.
+	AlertConditionAbsent = "absent"
+)
+
+const (
+	// AlertStateUnknown represents an unknown alert state (e.g. rule not evaluated yet or evaluation failure).
+	AlertStateUnknown = "unknown"
+	// AlertStateOK represents an OK alert state.
+	AlertStateOK = "ok"
+	// AlertStatePending represents a pending alert state, the rule condition not having held for its duration yet.
+	AlertStatePending = "pending"
+	// AlertStateWarning represents a warning alert state.
+	AlertStateWarning = "warning"
+	// AlertStateCritical represents a critical alert state.
+	AlertStateCritical = "critical"
+)
+
+// AlertRule represents a back-end alert rule item instance.
+type AlertRule struct {
+	Item
+	GraphID   *string     `gorm:"column:graph;type:varchar(36) DEFAULT NULL REFERENCES graphs (id) ON DELETE CASCADE ON UPDATE CASCADE" json:"graph,omitempty"`
+	Origin    string      `gorm:"type:varchar(128)" json:"origin,omitempty"`
+	Source    string      `gorm:"type:varchar(128)" json:"source,omitempty"`
+	Metric    string      `gorm:"type:varchar(128)" json:"metric,omitempty"`
+	Statistic string      `gorm:"type:varchar(32);not null" json:"statistic"`
+	Condition string      `gorm:"type:varchar(32);not null" json:"condition"`
+	Warning   *float64    `json:"warning"`
+	Critical  *float64    `json:"critical"`
+	Duration  int         `gorm:"not null;default:0" json:"duration"`
+	Interval  int         `gorm:"not null;default:0" json:"interval"`
+	Channels  ChannelList `gorm:"type:text" json:"channels,omitempty"`
+	Enabled   bool        `gorm:"not null" json:"enabled"`
+}
+
+// NewAlertRule creates a new back-end alert rule item instance.
+func (b *Backend) NewAlertRule() *AlertRule {
+	return &AlertRule{Item: Item{backend: b}}
+}
+
+// TableName returns the table name to use in the database.
+func (AlertRule) TableName() string {
+	return "alertrules"
+}
+
+// BeforeSave handles the ORM 'BeforeSave' callback.
+func (a *AlertRule) BeforeSave(scope *gorm.Scope) error {
+	if err := a.Item.BeforeSave(scope); err != nil {
+		return err
+	} else if a.Duration < 0 || a.Interval < 0 {
+		return ErrInvalidInterval
+	}
+
+	// Ensure rule either references a graph or a series
+	if (a.GraphID == nil || *a.GraphID == "") && (a.Origin == "" || a.Source == "" || a.Metric == "") {
+		return ErrInvalidTarget
+	}
+
+	switch a.Condition {
+	case AlertConditionAbove, AlertConditionBelow:
+		if a.Warning == nil && a.Critical == nil {
+			return ErrInvalidThreshold
+		}
+
+	case AlertConditionAbsent:
+		// no threshold needed
+
+	default:
+		return ErrInvalidCondition
+	}
+
+	if a.Statistic == "" {
+		scope.SetColumn("Statistic", "avg")
+	}
+
+	// Ensure optional fields are null if empty
+	if a.GraphID != nil && *a.GraphID == "" {
+		scope.SetColumn("GraphID", nil)
+	}
+
+	return nil
+}
+
+// Evaluate returns the alert state matching a given summary value, or missing data if missing is true.
+func (a *AlertRule) Evaluate(value float64, missing bool) string {
+	if a.Condition == AlertConditionAbsent {
+		if missing {
+			return AlertStateCritical
+		}
+
+		return AlertStateOK
+	} else if missing {
+		return AlertStateUnknown
+	}
+
+	exceeds := func(threshold *float64) bool {
+		if threshold == nil {
+			return false
+		} else if a.Condition == AlertConditionBelow {
+			return value < *threshold
+		}
+
+		return value > *threshold
+	}
+
+	if exceeds(a.Critical) {
+		return AlertStateCritical
+	} else if exceeds(a.Warning) {
+		return AlertStateWarning
+	}
+
+	return AlertStateOK
+}
+
+// AlertEvent represents a back-end alert rule state change event instance.
+type AlertEvent struct {
+	ID       string    `gorm:"type:varchar(36);not null;primary_key" json:"id"`
+	RuleID   string    `gorm:"column:rule;type:varchar(36) NOT NULL REFERENCES alertrules (id) ON DELETE CASCADE ON UPDATE CASCADE" json:"rule"`
+	Series   string    `gorm:"type:varchar(256)" json:"series,omitempty"`
+	State    string    `gorm:"type:varchar(16);not null" json:"state"`
+	Previous string    `gorm:"type:varchar(16);not null" json:"previous"`
+	Value    *float64  `json:"value"`
+	Time     time.Time `gorm:"not null;default:current_timestamp" json:"time"`
+}
+
+// TableName returns the table name to use in the database.
+func (AlertEvent) TableName() string {
+	return "alertevents"
+}
+
+// BeforeSave handles the ORM 'BeforeSave' callback.
+func (e *AlertEvent) BeforeSave(scope *gorm.Scope) error {
+	if e.ID == "" {
+		id, err := uuid.GenerateUUID()
+		if err != nil {
+			return err
+		}
+
+		scope.SetColumn("ID", id)
+	}
+
+	if e.Time.IsZero() {
+		scope.SetColumn("Time", time.Now().UTC().Round(time.Second))
+	}
+
+	return nil
+}
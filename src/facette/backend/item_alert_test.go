@@ -0,0 +1,148 @@
+package backend
+
+import "testing"
+
+func testAlertRuleNew() []*AlertRule {
+	warning, critical := 80.0, 95.0
+
+	return []*AlertRule{
+		&AlertRule{
+			Item: Item{
+				Name: "item1",
+			},
+			Origin:    "origin1",
+			Source:    "source1",
+			Metric:    "metric1",
+			Statistic: "avg",
+			Condition: AlertConditionAbove,
+			Warning:   &warning,
+			Critical:  &critical,
+			Duration:  300,
+			Interval:  60,
+			Channels:  ChannelList{"00000000-0000-0000-0000-000000000001"},
+			Enabled:   true,
+		},
+
+		&AlertRule{
+			Item: Item{
+				Name: "item2",
+			},
+			Origin:    "origin1",
+			Source:    "source2",
+			Metric:    "metric1",
+			Statistic: "last",
+			Condition: AlertConditionAbsent,
+			Duration:  600,
+			Enabled:   true,
+		},
+
+		&AlertRule{
+			Item: Item{
+				Name: "item3",
+			},
+			Origin:    "origin2",
+			Source:    "source1",
+			Metric:    "metric2",
+			Statistic: "min",
+			Condition: AlertConditionBelow,
+			Critical:  &warning,
+			Enabled:   false,
+		},
+	}
+}
+
+func testAlertRuleCreate(b *Backend, testAlertRules []*AlertRule, t *testing.T) {
+	testItemCreate(b, &AlertRule{}, testInterfaceToSlice(testAlertRules), t)
+}
+
+func testAlertRuleCreateInvalid(b *Backend, testAlertRules []*AlertRule, t *testing.T) {
+	testItemCreateInvalid(b, &AlertRule{}, testInterfaceToSlice(testAlertRules), t)
+
+	threshold := 1.0
+
+	if err := b.Storage().Save(&AlertRule{Item: Item{Name: "name"}, Condition: AlertConditionAbsent}); err !=
+		ErrInvalidTarget {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrInvalidTarget, err)
+		t.Fail()
+	}
+
+	if err := b.Storage().Save(&AlertRule{Item: Item{Name: "name"}, Origin: "origin1", Source: "source1",
+		Metric: "metric1", Condition: "unknown", Warning: &threshold}); err != ErrInvalidCondition {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrInvalidCondition, err)
+		t.Fail()
+	}
+
+	if err := b.Storage().Save(&AlertRule{Item: Item{Name: "name"}, Origin: "origin1", Source: "source1",
+		Metric: "metric1", Condition: AlertConditionAbove}); err != ErrInvalidThreshold {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrInvalidThreshold, err)
+		t.Fail()
+	}
+
+	if err := b.Storage().Save(&AlertRule{Item: Item{Name: "name"}, Origin: "origin1", Source: "source1",
+		Metric: "metric1", Condition: AlertConditionAbsent, Interval: -1}); err != ErrInvalidInterval {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrInvalidInterval, err)
+		t.Fail()
+	}
+}
+
+func testAlertRuleGet(b *Backend, testAlertRules []*AlertRule, t *testing.T) {
+	testItemGet(b, &AlertRule{}, testInterfaceToSlice(testAlertRules), t)
+}
+
+func testAlertRuleGetUnknown(b *Backend, testAlertRules []*AlertRule, t *testing.T) {
+	testItemGetUnknown(b, &AlertRule{}, testInterfaceToSlice(testAlertRules), t)
+}
+
+func testAlertRuleUpdate(b *Backend, testAlertRules []*AlertRule, t *testing.T) {
+	testItemUpdate(b, &AlertRule{}, testInterfaceToSlice(testAlertRules), t)
+}
+
+func testAlertRuleCount(b *Backend, testAlertRules []*AlertRule, t *testing.T) {
+	testItemCount(b, &AlertRule{}, testInterfaceToSlice(testAlertRules), t)
+}
+
+func testAlertRuleList(b *Backend, testAlertRules []*AlertRule, t *testing.T) {
+	testItemList(b, &AlertRule{}, testInterfaceToSlice(testAlertRules), t)
+}
+
+func testAlertRuleDelete(b *Backend, testAlertRules []*AlertRule, t *testing.T) {
+	testItemDelete(b, &AlertRule{}, testInterfaceToSlice(testAlertRules), t)
+}
+
+func testAlertRuleDeleteAll(b *Backend, testAlertRules []*AlertRule, t *testing.T) {
+	testItemDeleteAll(b, &AlertRule{}, testInterfaceToSlice(testAlertRules), t)
+}
+
+func Test_AlertRule_Evaluate(t *testing.T) {
+	warning, critical := 80.0, 95.0
+
+	rule := AlertRule{Condition: AlertConditionAbove, Warning: &warning, Critical: &critical}
+
+	for _, entry := range []struct {
+		value    float64
+		missing  bool
+		expected string
+	}{
+		{50, false, AlertStateOK},
+		{85, false, AlertStateWarning},
+		{99, false, AlertStateCritical},
+		{0, true, AlertStateUnknown},
+	} {
+		if state := rule.Evaluate(entry.value, entry.missing); state != entry.expected {
+			t.Logf("\nExpected %q\nbut got  %q", entry.expected, state)
+			t.Fail()
+		}
+	}
+
+	rule = AlertRule{Condition: AlertConditionBelow, Critical: &warning}
+	if state := rule.Evaluate(50, false); state != AlertStateCritical {
+		t.Logf("\nExpected %q\nbut got  %q", AlertStateCritical, state)
+		t.Fail()
+	}
+
+	rule = AlertRule{Condition: AlertConditionAbsent}
+	if state := rule.Evaluate(0, true); state != AlertStateCritical {
+		t.Logf("\nExpected %q\nbut got  %q", AlertStateCritical, state)
+		t.Fail()
+	}
+}
@@ -0,0 +1,150 @@
+package backend
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/facette/sliceutil"
+	"github.com/facette/sqlstorage"
+	"github.com/jinzhu/gorm"
+)
+
+// Annotation represents a back-end annotation item instance.
+type Annotation struct {
+	Item
+	Start        time.Time      `gorm:"not null" json:"start"`
+	End          *time.Time     `json:"end,omitempty"`
+	Text         string         `gorm:"type:text;not null" json:"text"`
+	Tags         AnnotationTags `gorm:"type:text" json:"tags,omitempty"`
+	GraphID      *string        `gorm:"column:graph;type:varchar(36) DEFAULT NULL REFERENCES graphs (id) ON DELETE CASCADE ON UPDATE CASCADE" json:"graph,omitempty"`
+	CollectionID *string        `gorm:"column:collection;type:varchar(36) DEFAULT NULL REFERENCES collections (id) ON DELETE CASCADE ON UPDATE CASCADE" json:"collection,omitempty"`
+}
+
+// NewAnnotation creates a new back-end annotation item instance.
+func (b *Backend) NewAnnotation() *Annotation {
+	return &Annotation{Item: Item{backend: b}}
+}
+
+// BeforeSave handles the ORM 'BeforeSave' callback.
+func (a *Annotation) BeforeSave(scope *gorm.Scope) error {
+	// Generate name if none provided, as annotations are mostly pushed by external tools
+	if a.Name == "" {
+		a.Name = fmt.Sprintf("annotation-%d", time.Now().UnixNano())
+		scope.SetColumn("Name", a.Name)
+	}
+
+	if err := a.Item.BeforeSave(scope); err != nil {
+		return err
+	}
+
+	if a.Start.IsZero() {
+		a.Start = time.Now().UTC().Truncate(time.Second)
+	}
+
+	// Store times as UTC, as they are compared when filtering annotations
+	scope.SetColumn("Start", a.Start.UTC())
+
+	if a.End != nil {
+		scope.SetColumn("End", a.End.UTC())
+	}
+
+	if a.End != nil && a.End.Before(a.Start) {
+		return ErrInvalidTimerange
+	}
+
+	// Ensure optional fields are null if empty
+	if a.GraphID != nil && *a.GraphID == "" {
+		scope.SetColumn("GraphID", nil)
+	}
+
+	if a.CollectionID != nil && *a.CollectionID == "" {
+		scope.SetColumn("CollectionID", nil)
+	}
+
+	return nil
+}
+
+// Overlaps returns whether or not the annotation overlaps a given time range.
+func (a *Annotation) Overlaps(startTime, endTime time.Time) bool {
+	end := a.Start
+	if a.End != nil {
+		end = *a.End
+	}
+
+	return !a.Start.After(endTime) && !end.Before(startTime)
+}
+
+// AnnotationTags represents a list of annotation tags.
+type AnnotationTags []string
+
+// Value marshals the annotation tags for compatibility with SQL drivers.
+func (at AnnotationTags) Value() (driver.Value, error) {
+	data, err := json.Marshal(at)
+	return data, err
+}
+
+// Scan unmarshals the annotation tags retrieved from SQL drivers.
+func (at *AnnotationTags) Scan(v interface{}) error {
+	return scanValue(v, at)
+}
+
+// Annotations returns the list of annotations of an organization (empty for none) overlapping a given time range and
+// applying to a graph, either being global, scoped to the graph itself or to one of the collections it belongs to. If
+// tags are provided, only annotations having at least one of them are returned.
+func (b *Backend) Annotations(organization string, startTime, endTime time.Time, graphID string,
+	tags []string) ([]*Annotation, error) {
+
+	// Only fetch annotations starting before the end of the time range, overlapping being checked afterwards as
+	// annotations might have no end time
+	annotations := []*Annotation{}
+	if _, err := b.Storage().List(&annotations, map[string]interface{}{
+		"organization": OrganizationFilter(organization),
+		"start":        sqlstorage.LessOrEqualModifier{Value: endTime.UTC()},
+	}, []string{"start"}, 0, 0); err != nil {
+		return nil, err
+	}
+
+	// Get collections the graph belongs to
+	collections := []string{}
+	if graphID != "" {
+		entries := []*CollectionEntry{}
+		if _, err := b.Storage().List(&entries, map[string]interface{}{"graph": graphID}, nil, 0, 0); err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			collections = append(collections, entry.CollectionID)
+		}
+	}
+
+	result := []*Annotation{}
+	for _, a := range annotations {
+		if !a.Overlaps(startTime, endTime) {
+			continue
+		} else if a.GraphID != nil && *a.GraphID != graphID {
+			continue
+		} else if a.CollectionID != nil && !sliceutil.Has(collections, *a.CollectionID) {
+			continue
+		}
+
+		if len(tags) > 0 {
+			matched := false
+			for _, tag := range a.Tags {
+				if sliceutil.Has(tags, tag) {
+					matched = true
+					break
+				}
+			}
+
+			if !matched {
+				continue
+			}
+		}
+
+		result = append(result, a)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,133 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func testAnnotationNew() []*Annotation {
+	end := time.Date(2017, 1, 1, 12, 30, 0, 0, time.UTC)
+
+	return []*Annotation{
+		&Annotation{
+			Item: Item{
+				Name: "item1",
+			},
+			Start: time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC),
+			End:   &end,
+			Text:  "Maintenance window",
+			Tags:  AnnotationTags{"maintenance"},
+		},
+
+		&Annotation{
+			Item: Item{
+				Name: "item2",
+			},
+			Start: time.Date(2017, 1, 1, 14, 0, 0, 0, time.UTC),
+			Text:  "Deployed version 1.2.3",
+			Tags:  AnnotationTags{"deploy", "backend"},
+		},
+
+		&Annotation{
+			Item: Item{
+				Name: "item3",
+			},
+			Start: time.Date(2017, 1, 2, 9, 0, 0, 0, time.UTC),
+			Text:  "Deployed version 1.2.4",
+			Tags:  AnnotationTags{"deploy"},
+		},
+	}
+}
+
+func testAnnotationCreate(b *Backend, testAnnotations []*Annotation, t *testing.T) {
+	testItemCreate(b, &Annotation{}, testInterfaceToSlice(testAnnotations), t)
+}
+
+func testAnnotationCreateInvalid(b *Backend, testAnnotations []*Annotation, t *testing.T) {
+	testItemCreateInvalid(b, &Annotation{}, testInterfaceToSlice(testAnnotations), t)
+
+	end := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := b.Storage().Save(&Annotation{Item: Item{Name: "name"}, Start: end.Add(time.Hour),
+		End: &end}); err != ErrInvalidTimerange {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrInvalidTimerange, err)
+		t.Fail()
+	}
+}
+
+func testAnnotationGet(b *Backend, testAnnotations []*Annotation, t *testing.T) {
+	testItemGet(b, &Annotation{}, testInterfaceToSlice(testAnnotations), t)
+}
+
+func testAnnotationGetUnknown(b *Backend, testAnnotations []*Annotation, t *testing.T) {
+	testItemGetUnknown(b, &Annotation{}, testInterfaceToSlice(testAnnotations), t)
+}
+
+func testAnnotationUpdate(b *Backend, testAnnotations []*Annotation, t *testing.T) {
+	testItemUpdate(b, &Annotation{}, testInterfaceToSlice(testAnnotations), t)
+}
+
+func testAnnotationCount(b *Backend, testAnnotations []*Annotation, t *testing.T) {
+	testItemCount(b, &Annotation{}, testInterfaceToSlice(testAnnotations), t)
+}
+
+func testAnnotationList(b *Backend, testAnnotations []*Annotation, t *testing.T) {
+	testItemList(b, &Annotation{}, testInterfaceToSlice(testAnnotations), t)
+}
+
+func testAnnotationFilter(b *Backend, testAnnotations []*Annotation, t *testing.T) {
+	startTime := time.Date(2017, 1, 1, 12, 15, 0, 0, time.UTC)
+	endTime := time.Date(2017, 1, 1, 18, 0, 0, 0, time.UTC)
+
+	for _, entry := range []struct {
+		tags     []string
+		expected []string
+	}{
+		{nil, []string{"item1", "item2"}},
+		{[]string{"deploy"}, []string{"item2"}},
+		{[]string{"unknown"}, []string{}},
+	} {
+		annotations, err := b.Annotations("", startTime, endTime, "", entry.tags)
+		if err != nil {
+			t.Logf("\nExpected <nil>\nbut got  %#v", err)
+			t.Fail()
+			continue
+		}
+
+		names := []string{}
+		for _, a := range annotations {
+			names = append(names, a.Name)
+		}
+
+		if len(names) != len(entry.expected) {
+			t.Logf("\nExpected %#v\nbut got  %#v", entry.expected, names)
+			t.Fail()
+			continue
+		}
+
+		for i := range names {
+			if names[i] != entry.expected[i] {
+				t.Logf("\nExpected %#v\nbut got  %#v", entry.expected, names)
+				t.Fail()
+				break
+			}
+		}
+	}
+
+	// Ensure annotations starting at the end of the time range are kept
+	annotations, err := b.Annotations("", endTime.Add(-5*time.Hour), endTime.Add(-4*time.Hour), "", nil)
+	if err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	} else if len(annotations) != 1 || annotations[0].Name != "item2" {
+		t.Logf("\nExpected [\"item2\"]\nbut got  %#v", annotations)
+		t.Fail()
+	}
+}
+
+func testAnnotationDelete(b *Backend, testAnnotations []*Annotation, t *testing.T) {
+	testItemDelete(b, &Annotation{}, testInterfaceToSlice(testAnnotations), t)
+}
+
+func testAnnotationDeleteAll(b *Backend, testAnnotations []*Annotation, t *testing.T) {
+	testItemDeleteAll(b, &Annotation{}, testInterfaceToSlice(testAnnotations), t)
+}
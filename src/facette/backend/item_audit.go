@@ -0,0 +1,128 @@
+package backend
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// AuditActionCreate represents an item creation.
+	AuditActionCreate = "create"
+	// AuditActionUpdate represents an item update, including restoration of a previous revision.
+	AuditActionUpdate = "update"
+	// AuditActionDelete represents an item deletion.
+	AuditActionDelete = "delete"
+	// AuditActionRestore represents an item restoration from trash.
+	AuditActionRestore = "restore"
+	// AuditActionPurge represents an item permanent removal from trash.
+	AuditActionPurge = "purge"
+	// AuditActionRefresh represents a provider refresh.
+	AuditActionRefresh = "refresh"
+)
+
+// AuditEntry represents a back-end audit log entry instance, recording an action performed on an item along with a
+// summary of its changes. Entries aren't removed along with the organization they belong to, as they might be
+// required for compliance purposes.
+type AuditEntry struct {
+	ID             string             `gorm:"type:varchar(36);not null;primary_key" json:"id"`
+	Action         string             `gorm:"type:varchar(16);not null;index" json:"action"`
+	ItemID         string             `gorm:"column:item;type:varchar(36);not null;index" json:"item"`
+	ItemType       string             `gorm:"column:item_type;type:varchar(32);not null" json:"item_type"`
+	Name           *string            `gorm:"type:varchar(128)" json:"name"`
+	User           *string            `gorm:"type:varchar(128);index" json:"user"`
+	Address        *string            `gorm:"type:varchar(64)" json:"address"`
+	Changes        RevisionChangeList `gorm:"type:text" json:"changes,omitempty"`
+	OrganizationID *string            `gorm:"column:organization;type:varchar(36);index" json:"-"`
+	Date           time.Time          `gorm:"not null;default:current_timestamp" json:"date"`
+}
+
+// TableName returns the table name to use in the database.
+func (AuditEntry) TableName() string {
+	return "audit"
+}
+
+// NewAuditEntry creates a new back-end audit log entry instance given the action performed on an item of a given type.
+// Changes are summarized upon creation and update, given the item previous state (nil if none).
+func NewAuditEntry(action, typ string, item, prev interface{}) (*AuditEntry, error) {
+	rv := reflect.Indirect(reflect.ValueOf(item))
+
+	entry := &AuditEntry{
+		Action:   action,
+		ItemID:   rv.FieldByName("ID").String(),
+		ItemType: typ,
+	}
+
+	if v := rv.FieldByName("Name"); v.IsValid() && v.Kind() == reflect.String {
+		name := v.String()
+		entry.Name = &name
+	}
+
+	if action == AuditActionCreate || action == AuditActionUpdate {
+		changes, err := DiffItems(prev, item)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.Changes = changes
+	}
+
+	return entry, nil
+}
+
+// BeforeSave handles the ORM 'BeforeSave' callback.
+func (e *AuditEntry) BeforeSave(scope *gorm.Scope) error {
+	if e.ID == "" {
+		id, err := uuid.GenerateUUID()
+		if err != nil {
+			return err
+		}
+
+		scope.SetColumn("ID", id)
+	}
+
+	if e.Date.IsZero() {
+		scope.SetColumn("Date", time.Now().UTC().Round(time.Second))
+	}
+
+	// Ensure optional fields are null if empty
+	if e.Name != nil && *e.Name == "" {
+		scope.SetColumn("Name", nil)
+	}
+
+	if e.User != nil && *e.User == "" {
+		scope.SetColumn("User", nil)
+	}
+
+	if e.Address != nil && *e.Address == "" {
+		scope.SetColumn("Address", nil)
+	}
+
+	if e.OrganizationID != nil && *e.OrganizationID == "" {
+		scope.SetColumn("OrganizationID", nil)
+	}
+
+	return nil
+}
+
+// GetOrganization returns the identifier of the organization the audited action applies to, or an empty string if
+// none.
+func (e *AuditEntry) GetOrganization() string {
+	if e.OrganizationID == nil {
+		return ""
+	}
+
+	return *e.OrganizationID
+}
+
+// SetOrganization sets the organization the audited action applies to given its identifier, an empty identifier
+// meaning none.
+func (e *AuditEntry) SetOrganization(id string) {
+	if id == "" {
+		e.OrganizationID = nil
+	} else {
+		e.OrganizationID = &id
+	}
+}
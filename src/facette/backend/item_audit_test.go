@@ -0,0 +1,40 @@
+package backend
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_NewAuditEntry(t *testing.T) {
+	desc := "description1"
+
+	prev := &Graph{Item: Item{ID: "00000000-0000-0000-0000-000000000001", Name: "graph1"}}
+	cur := &Graph{Item: Item{ID: prev.ID, Name: "graph1", Description: &desc}}
+
+	entry, err := NewAuditEntry(AuditActionUpdate, "graphs", cur, prev)
+	if err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	}
+
+	if entry.ItemID != prev.ID || entry.Name == nil || *entry.Name != "graph1" {
+		t.Logf("\nExpected %q item\nbut got  %q (%v)", prev.ID, entry.ItemID, entry.Name)
+		t.Fail()
+	}
+
+	expected := RevisionChangeList{
+		{Path: "description", Kind: RevisionChangeModified, Old: nil, New: "description1"},
+	}
+
+	if !reflect.DeepEqual(entry.Changes, expected) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, entry.Changes)
+		t.Fail()
+	}
+
+	// Ensure changes are only summarized upon creation and update
+	if entry, err = NewAuditEntry(AuditActionDelete, "graphs", cur, nil); err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	} else if len(entry.Changes) != 0 {
+		t.Logf("\nExpected no changes\nbut got  %#v", entry.Changes)
+		t.Fail()
+	}
+}
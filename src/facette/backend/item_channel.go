@@ -0,0 +1,104 @@
+package backend
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/facette/maputil"
+)
+
+// ChannelSecretMask represents the value replacing the secret settings of notification channels when returned.
+const ChannelSecretMask = "********"
+
+// channelSecrets represents the secret settings of notification channels given their kind, map settings (e.g. webhook
+// headers) having each of their values masked.
+var channelSecrets = map[string][]string{
+	"smtp":    {"password"},
+	"webhook": {"url", "headers"},
+}
+
+// Channel represents a back-end notification channel item instance.
+type Channel struct {
+	Item
+	Kind     string      `gorm:"type:varchar(32);not null" json:"kind"`
+	Settings maputil.Map `gorm:"type:text" json:"settings"`
+	Enabled  bool        `gorm:"not null" json:"enabled"`
+}
+
+// NewChannel creates a new back-end notification channel item instance.
+func (b *Backend) NewChannel() *Channel {
+	return &Channel{Item: Item{backend: b}}
+}
+
+// Masked returns a copy of the notification channel having its secret settings masked.
+func (c *Channel) Masked() *Channel {
+	clone := *c
+	clone.Settings = make(maputil.Map, len(c.Settings))
+
+	for key, value := range c.Settings {
+		clone.Settings[key] = value
+	}
+
+	for _, key := range channelSecrets[c.Kind] {
+		switch v := c.Settings[key].(type) {
+		case string:
+			if v != "" {
+				clone.Settings[key] = ChannelSecretMask
+			}
+
+		case map[string]interface{}:
+			masked := make(map[string]interface{}, len(v))
+			for k := range v {
+				masked[k] = ChannelSecretMask
+			}
+
+			clone.Settings[key] = masked
+		}
+	}
+
+	return &clone
+}
+
+// Unmask restores the secret settings sent back masked given the notification channel previous state (nil if none),
+// masked values being kept as-is otherwise.
+func (c *Channel) Unmask(prev *Channel) {
+	if prev == nil || prev.Kind != c.Kind {
+		return
+	}
+
+	for _, key := range channelSecrets[c.Kind] {
+		switch v := c.Settings[key].(type) {
+		case string:
+			if v == ChannelSecretMask {
+				c.Settings[key] = prev.Settings[key]
+			}
+
+		case map[string]interface{}:
+			orig, _ := prev.Settings[key].(map[string]interface{})
+
+			for k, value := range v {
+				if value != ChannelSecretMask {
+					continue
+				} else if pv, ok := orig[k]; ok {
+					v[k] = pv
+				} else {
+					delete(v, k)
+				}
+			}
+		}
+	}
+}
+
+// ChannelList represents a list of notification channels identifiers.
+type ChannelList []string
+
+// Value marshals the channels list for compatibility with SQL drivers.
+func (cl ChannelList) Value() (driver.Value, error) {
+	data, err := json.Marshal(cl)
+	return data, err
+}
+
+// Scan unmarshals the channels list retrieved from SQL drivers.
+func (cl *ChannelList) Scan(v interface{}) error {
+	return scanValue(v, cl)
+}
@@ -0,0 +1,139 @@
+package backend
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/facette/maputil"
+)
+
+func testChannelNew() []*Channel {
+	return []*Channel{
+		&Channel{
+			Item: Item{
+				Name: "item1",
+			},
+			Kind: "webhook",
+			Settings: maputil.Map{
+				"url":  "http://localhost/hook",
+				"body": "{{ .subject }}",
+			},
+			Enabled: true,
+		},
+
+		&Channel{
+			Item: Item{
+				Name: "item2",
+			},
+			Kind: "smtp",
+			Settings: maputil.Map{
+				"host": "localhost",
+				"from": "facette@localhost",
+				"to":   []interface{}{"admin@localhost"},
+			},
+			Enabled: true,
+		},
+
+		&Channel{
+			Item: Item{
+				Name: "item3",
+			},
+			Kind: "command",
+			Settings: maputil.Map{
+				"command": "/usr/local/bin/notify",
+			},
+			Enabled: false,
+		},
+	}
+}
+
+func testChannelCreate(b *Backend, testChannels []*Channel, t *testing.T) {
+	testItemCreate(b, &Channel{}, testInterfaceToSlice(testChannels), t)
+}
+
+func testChannelCreateInvalid(b *Backend, testChannels []*Channel, t *testing.T) {
+	testItemCreateInvalid(b, &Channel{}, testInterfaceToSlice(testChannels), t)
+}
+
+func testChannelGet(b *Backend, testChannels []*Channel, t *testing.T) {
+	testItemGet(b, &Channel{}, testInterfaceToSlice(testChannels), t)
+}
+
+func testChannelGetUnknown(b *Backend, testChannels []*Channel, t *testing.T) {
+	testItemGetUnknown(b, &Channel{}, testInterfaceToSlice(testChannels), t)
+}
+
+func testChannelUpdate(b *Backend, testChannels []*Channel, t *testing.T) {
+	testItemUpdate(b, &Channel{}, testInterfaceToSlice(testChannels), t)
+}
+
+func testChannelCount(b *Backend, testChannels []*Channel, t *testing.T) {
+	testItemCount(b, &Channel{}, testInterfaceToSlice(testChannels), t)
+}
+
+func testChannelList(b *Backend, testChannels []*Channel, t *testing.T) {
+	testItemList(b, &Channel{}, testInterfaceToSlice(testChannels), t)
+}
+
+func testChannelDelete(b *Backend, testChannels []*Channel, t *testing.T) {
+	testItemDelete(b, &Channel{}, testInterfaceToSlice(testChannels), t)
+}
+
+func testChannelDeleteAll(b *Backend, testChannels []*Channel, t *testing.T) {
+	testItemDeleteAll(b, &Channel{}, testInterfaceToSlice(testChannels), t)
+}
+
+func Test_Channel_Masked(t *testing.T) {
+	channel := &Channel{
+		Kind: "webhook",
+		Settings: maputil.Map{
+			"url":     "http://localhost/hook?token=abc",
+			"method":  "POST",
+			"headers": map[string]interface{}{"Authorization": "Bearer abc", "X-Test": "1"},
+		},
+	}
+
+	expected := maputil.Map{
+		"url":     ChannelSecretMask,
+		"method":  "POST",
+		"headers": map[string]interface{}{"Authorization": ChannelSecretMask, "X-Test": ChannelSecretMask},
+	}
+
+	masked := channel.Masked()
+	if !reflect.DeepEqual(masked.Settings, expected) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, masked.Settings)
+		t.Fail()
+	} else if channel.Settings["url"] != "http://localhost/hook?token=abc" {
+		t.Logf("\nExpected original channel to be left untouched\nbut got  %#v", channel.Settings)
+		t.Fail()
+	}
+
+	// Masked values sent back are restored from the previous state, others being updated
+	update := &Channel{
+		Kind: "webhook",
+		Settings: maputil.Map{
+			"url":     ChannelSecretMask,
+			"method":  "PUT",
+			"headers": map[string]interface{}{"Authorization": ChannelSecretMask, "X-Other": ChannelSecretMask},
+		},
+	}
+
+	expected = maputil.Map{
+		"url":     "http://localhost/hook?token=abc",
+		"method":  "PUT",
+		"headers": map[string]interface{}{"Authorization": "Bearer abc"},
+	}
+
+	update.Unmask(channel)
+	if !reflect.DeepEqual(update.Settings, expected) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, update.Settings)
+		t.Fail()
+	}
+
+	// Settings of other kinds of channels aren't restored
+	update = &Channel{Kind: "smtp", Settings: maputil.Map{"password": ChannelSecretMask}}
+	if update.Unmask(channel); update.Settings["password"] != ChannelSecretMask {
+		t.Logf("\nExpected %q\nbut got  %#v", ChannelSecretMask, update.Settings["password"])
+		t.Fail()
+	}
+}
@@ -13,11 +13,12 @@ import (
 // Collection represents a back-end collection item instance.
 type Collection struct {
 	Item
+	ACL
 	Entries    []*CollectionEntry `json:"entries,omitempty"`
 	Link       *Collection        `json:"-"`
 	LinkID     *string            `gorm:"column:link;type:varchar(36) DEFAULT NULL REFERENCES collections (id) ON DELETE CASCADE ON UPDATE CASCADE" json:"link,omitempty"`
 	Attributes maputil.Map        `gorm:"type:text" json:"attributes,omitempty"`
-	Alias      *string            `gorm:"type:varchar(128);unique_index" json:"alias,omitempty"`
+	Alias      *string            `gorm:"type:varchar(128);index" json:"alias,omitempty"`
 	Options    maputil.Map        `gorm:"type:text" json:"options,omitempty"`
 	Parent     *Collection        `json:"-"`
 	ParentID   *string            `gorm:"column:parent;type:varchar(36) DEFAULT NULL REFERENCES collections (id) ON DELETE SET NULL ON UPDATE SET NULL" json:"parent,omitempty"`
@@ -36,8 +37,14 @@ func (b *Backend) NewCollection() *Collection {
 func (c *Collection) BeforeSave(scope *gorm.Scope) error {
 	if err := c.Item.BeforeSave(scope); err != nil {
 		return err
-	} else if c.Alias != nil && *c.Alias != "" && !nameRegexp.MatchString(*c.Alias) {
-		return ErrInvalidAlias
+	} else if err := c.ACL.BeforeSave(scope); err != nil {
+		return err
+	} else if c.Alias != nil && *c.Alias != "" {
+		if !nameRegexp.MatchString(*c.Alias) {
+			return ErrInvalidAlias
+		} else if err := checkUnique(scope, c.ID, "alias", *c.Alias, c.OrganizationID); err != nil {
+			return err
+		}
 	}
 
 	for idx, entry := range c.Entries {
@@ -116,6 +123,8 @@ func (c *Collection) Expand(attrs maputil.Map) error {
 		// Expand template and applies current collection's attributes and options
 		tmpl := c.Link.Clone()
 		tmpl.ID = c.ID
+		tmpl.ACL = c.ACL
+		tmpl.OrganizationID = c.OrganizationID
 		tmpl.Attributes.Merge(c.Attributes, true)
 		tmpl.Options.Merge(c.Options, true)
 		tmpl.Template = false
@@ -264,10 +273,13 @@ type CollectionEntry struct {
 // CollectionTree represents a back-end collection tree instance.
 type CollectionTree []*CollectionTreeEntry
 
-// NewCollectionTree creates a new back-end collection tree instance.
-func (b *Backend) NewCollectionTree(root string) (*CollectionTree, error) {
+// NewCollectionTree creates a new back-end collection tree instance, given the identifier of the organization the
+// collections belong to (empty for none).
+func (b *Backend) NewCollectionTree(organization, root string) (*CollectionTree, error) {
 	collections := []*Collection{}
-	if _, err := b.Storage().List(&collections, nil, nil, 0, 0); err != nil {
+	if _, err := b.Storage().List(&collections, map[string]interface{}{
+		"organization": OrganizationFilter(organization),
+	}, nil, 0, 0); err != nil {
 		return nil, err
 	}
 
@@ -3,6 +3,7 @@ package backend
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/facette/maputil"
 )
@@ -96,6 +97,10 @@ func testCollectionUpdate(b *Backend, testCollections []*Collection, t *testing.
 	}
 }
 
+func testCollectionConflict(b *Backend, testCollections []*Collection, t *testing.T) {
+	testItemConflict(b, &Collection{}, testInterfaceToSlice(testCollections), t)
+}
+
 func testCollectionCount(b *Backend, testCollections []*Collection, t *testing.T) {
 	testItemCount(b, &Collection{}, testInterfaceToSlice(testCollections), t)
 }
@@ -113,6 +118,33 @@ func testCollectionList(b *Backend, testCollections []*Collection, testGraphs []
 	testItemList(b, &Collection{}, testInterfaceToSlice(testCollections), t)
 }
 
+func testCollectionConditionalSave(b *Backend, testCollections []*Collection, t *testing.T) {
+	testItemConditionalSave(b, &Collection{}, testInterfaceToSlice(testCollections), t)
+
+	// Ensure entries are kept upon failed conditional save
+	collection := &Collection{}
+	if err := b.Storage().Get("name", "item2", collection); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+		return
+	}
+
+	collection.ExpectModified(collection.Modified.Add(-time.Second))
+	if err := b.Storage().Save(collection); err != ErrItemModified {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrItemModified, err)
+		t.Fail()
+	}
+
+	collection = &Collection{}
+	if err := b.Storage().Get("name", "item2", collection); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	} else if len(collection.Entries) != len(testCollections[1].Entries) {
+		t.Logf("\nExpected %d entries\nbut got  %d", len(testCollections[1].Entries), len(collection.Entries))
+		t.Fail()
+	}
+}
+
 func testCollectionDelete(b *Backend, testCollections []*Collection, t *testing.T) {
 	testItemDelete(b, &Collection{}, testInterfaceToSlice(testCollections), t)
 }
@@ -185,7 +217,7 @@ func testCollectionTree(b *Backend, testCollections []*Collection, t *testing.T)
 		},
 	}
 
-	tree, err := b.NewCollectionTree("")
+	tree, err := b.NewCollectionTree("", "")
 	if err != nil {
 		t.Logf("\nExpected <nil>\nbut got  %#v", err)
 		t.Fail()
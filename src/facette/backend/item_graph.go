@@ -14,11 +14,12 @@ import (
 // Graph represents a library graph item instance.
 type Graph struct {
 	Item
+	ACL
 	Groups     SeriesGroups `gorm:"type:text;not null" json:"groups,omitempty"`
 	Link       *Graph       `json:"-"`
 	LinkID     *string      `gorm:"column:link;type:varchar(36) DEFAULT NULL REFERENCES graphs (id) ON DELETE CASCADE ON UPDATE CASCADE" json:"link,omitempty"`
 	Attributes maputil.Map  `gorm:"type:text" json:"attributes,omitempty"`
-	Alias      *string      `gorm:"type:varchar(128);unique_index" json:"alias,omitempty"`
+	Alias      *string      `gorm:"type:varchar(128);index" json:"alias,omitempty"`
 	Options    maputil.Map  `gorm:"type:text" json:"options,omitempty"`
 	Template   bool         `gorm:"not null" json:"template"`
 
@@ -35,8 +36,14 @@ func (b *Backend) NewGraph() *Graph {
 func (g *Graph) BeforeSave(scope *gorm.Scope) error {
 	if err := g.Item.BeforeSave(scope); err != nil {
 		return err
-	} else if g.Alias != nil && *g.Alias != "" && !nameRegexp.MatchString(*g.Alias) {
-		return ErrInvalidAlias
+	} else if err := g.ACL.BeforeSave(scope); err != nil {
+		return err
+	} else if g.Alias != nil && *g.Alias != "" {
+		if !nameRegexp.MatchString(*g.Alias) {
+			return ErrInvalidAlias
+		} else if err := checkUnique(scope, g.ID, "alias", *g.Alias, g.OrganizationID); err != nil {
+			return err
+		}
 	}
 
 	// Ensure optional fields are null if empty
@@ -65,6 +72,12 @@ func (g *Graph) Clone() *Graph {
 			clone.Groups[i].Options = group.Options.Clone()
 		}
 
+		if group.Histogram != nil {
+			clone.Groups[i].Histogram = &Histogram{}
+			*clone.Groups[i].Histogram = *group.Histogram
+			clone.Groups[i].Histogram.Bounds = append([]float64(nil), group.Histogram.Bounds...)
+		}
+
 		clone.Groups[i].Series = make([]*Series, len(group.Series))
 		for j, series := range group.Series {
 			clone.Groups[i].Series[j] = &Series{}
@@ -112,6 +125,8 @@ func (g *Graph) Expand(attrs maputil.Map) error {
 		// Expand template and applies current graph's attributes
 		tmpl := g.Link.Clone()
 		tmpl.ID = g.ID
+		tmpl.ACL = g.ACL
+		tmpl.OrganizationID = g.OrganizationID
 		tmpl.Attributes.Merge(g.Attributes, true)
 		tmpl.Options.Merge(g.Options, true)
 		tmpl.Template = false
@@ -185,6 +200,16 @@ type SeriesGroup struct {
 	Consolidate int         `json:"consolidate"`
 	Series      []*Series   `json:"series"`
 	Options     maputil.Map `json:"options,omitempty"`
+	Histogram   *Histogram  `json:"histogram,omitempty"`
+}
+
+// Histogram represents a library graph series group histogram settings instance. If bounds are set, each group
+// series is considered as a native histogram bucket whose upper bound is given in the matching position, otherwise
+// series plots values are dispatched into evenly distributed value ranges.
+type Histogram struct {
+	Buckets    int       `json:"buckets,omitempty"`
+	Bounds     []float64 `json:"bounds,omitempty"`
+	Cumulative bool      `json:"cumulative,omitempty"`
 }
 
 // Series represents a library graph series entry instance.
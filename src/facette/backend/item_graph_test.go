@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/facette/maputil"
+	"github.com/facette/sqlstorage"
 )
 
 func testGraphNew() []*Graph {
@@ -117,6 +118,34 @@ func testGraphUpdate(b *Backend, testGraphs []*Graph, t *testing.T) {
 	}
 }
 
+func testGraphConditionalSave(b *Backend, testGraphs []*Graph, t *testing.T) {
+	testItemConditionalSave(b, &Graph{}, testInterfaceToSlice(testGraphs), t)
+}
+
+func testGraphConflict(b *Backend, testGraphs []*Graph, t *testing.T) {
+	testItemConflict(b, &Graph{}, testInterfaceToSlice(testGraphs), t)
+
+	// Aliases are unique within organizations as well
+	alias := "alias1"
+
+	testGraphs[0].Alias = &alias
+	if err := b.Storage().Save(testGraphs[0]); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	}
+
+	if err := b.Storage().Save(&Graph{Item: Item{Name: "alias"}, Alias: &alias}); err != sqlstorage.ErrItemConflict {
+		t.Logf("\nExpected %#v\nbut got  %#v", sqlstorage.ErrItemConflict, err)
+		t.Fail()
+	}
+
+	testGraphs[0].Alias = nil
+	if err := b.Storage().Save(testGraphs[0]); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	}
+}
+
 func testGraphCount(b *Backend, testGraphs []*Graph, t *testing.T) {
 	testItemCount(b, &Graph{}, testInterfaceToSlice(testGraphs), t)
 }
@@ -0,0 +1,85 @@
+package backend
+
+import (
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/jinzhu/gorm"
+)
+
+// Organization represents a back-end organization instance. Organizations partition the other back-end items between
+// tenants, items not belonging to any organization forming the default one.
+type Organization struct {
+	ID          string    `gorm:"type:varchar(36);not null;primary_key" json:"id"`
+	Name        string    `gorm:"type:varchar(128);not null;unique_index" json:"name"`
+	Description *string   `gorm:"type:text" json:"description"`
+	Created     time.Time `gorm:"not null;default:current_timestamp" json:"created"`
+	Modified    time.Time `gorm:"not null;default:current_timestamp" json:"modified"`
+
+	expected *time.Time
+}
+
+// NewOrganization creates a new back-end organization instance.
+func (b *Backend) NewOrganization() *Organization {
+	return &Organization{}
+}
+
+// BeforeSave handles the ORM 'BeforeSave' callback.
+func (o *Organization) BeforeSave(scope *gorm.Scope) error {
+	if o.ID == "" {
+		id, err := uuid.GenerateUUID()
+		if err != nil {
+			return err
+		}
+
+		scope.SetColumn("ID", id)
+	} else if _, err := uuid.ParseUUID(o.ID); err != nil {
+		return ErrInvalidID
+	}
+
+	if !nameRegexp.MatchString(o.Name) {
+		return ErrInvalidName
+	}
+
+	now, err := conditionalSave(scope, o.ID, o.expected)
+	if err != nil {
+		return err
+	}
+
+	o.expected = nil
+
+	if o.Created.IsZero() {
+		scope.SetColumn("Created", now)
+	}
+
+	scope.SetColumn("Modified", now)
+
+	// Ensure optional fields are null if empty
+	if o.Description != nil && *o.Description == "" {
+		scope.SetColumn("Description", nil)
+	}
+
+	return nil
+}
+
+// ExpectModified makes the next save of the organization conditional to its stored modification time, the save
+// failing with ErrItemModified if the organization has been modified in the meantime.
+func (o *Organization) ExpectModified(modified time.Time) {
+	o.expected = &modified
+}
+
+// OrganizationItem represents a back-end item scoped to an organization.
+type OrganizationItem interface {
+	GetOrganization() string
+	SetOrganization(id string)
+}
+
+// OrganizationFilter returns the storage filter value matching the items belonging to an organization given its
+// identifier, an empty identifier matching the items not belonging to any.
+func OrganizationFilter(id string) interface{} {
+	if id == "" {
+		return "null"
+	}
+
+	return id
+}
@@ -0,0 +1,104 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/facette/sqlstorage"
+)
+
+func testOrganizationNew() []*Organization {
+	return []*Organization{
+		&Organization{
+			Name: "item1",
+		},
+
+		&Organization{
+			Name: "item2",
+		},
+
+		&Organization{
+			Name: "item3",
+		},
+	}
+}
+
+func testOrganizationCreate(b *Backend, testOrganizations []*Organization, t *testing.T) {
+	testItemCreate(b, &Organization{}, testInterfaceToSlice(testOrganizations), t)
+}
+
+func testOrganizationCreateInvalid(b *Backend, testOrganizations []*Organization, t *testing.T) {
+	testItemCreateInvalid(b, &Organization{}, testInterfaceToSlice(testOrganizations), t)
+}
+
+func testOrganizationGet(b *Backend, testOrganizations []*Organization, t *testing.T) {
+	testItemGet(b, &Organization{}, testInterfaceToSlice(testOrganizations), t)
+}
+
+func testOrganizationGetUnknown(b *Backend, testOrganizations []*Organization, t *testing.T) {
+	testItemGetUnknown(b, &Organization{}, testInterfaceToSlice(testOrganizations), t)
+}
+
+func testOrganizationUpdate(b *Backend, testOrganizations []*Organization, t *testing.T) {
+	testItemUpdate(b, &Organization{}, testInterfaceToSlice(testOrganizations), t)
+}
+
+func testOrganizationConditionalSave(b *Backend, testOrganizations []*Organization, t *testing.T) {
+	testItemConditionalSave(b, &Organization{}, testInterfaceToSlice(testOrganizations), t)
+}
+
+func testOrganizationCount(b *Backend, testOrganizations []*Organization, t *testing.T) {
+	testItemCount(b, &Organization{}, testInterfaceToSlice(testOrganizations), t)
+}
+
+func testOrganizationList(b *Backend, testOrganizations []*Organization, t *testing.T) {
+	testItemList(b, &Organization{}, testInterfaceToSlice(testOrganizations), t)
+}
+
+func testOrganizationItems(b *Backend, testOrganizations []*Organization, t *testing.T) {
+	org := testOrganizations[1]
+
+	graph := b.NewGraph()
+	graph.Name = "organization-graph1"
+	graph.SetOrganization(org.ID)
+
+	if err := b.Storage().Save(graph); err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	}
+
+	// Ensure items are only listed within their organization
+	for _, entry := range []struct {
+		organization string
+		expected     int
+	}{
+		{org.ID, 1},
+		{"", 0},
+	} {
+		graphs := []*Graph{}
+		if count, err := b.Storage().List(&graphs, map[string]interface{}{
+			"organization": OrganizationFilter(entry.organization),
+		}, nil, 0, 0); err != nil {
+			t.Logf("\nExpected <nil>\nbut got  %#v", err)
+			t.Fail()
+		} else if count != entry.expected {
+			t.Logf("\nExpected %d\nbut got  %d", entry.expected, count)
+			t.Fail()
+		}
+	}
+
+	// Ensure items are deleted along with their organization
+	if err := b.Storage().Delete(org); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	} else if err := b.Storage().Get("id", graph.ID, b.NewGraph()); err != sqlstorage.ErrItemNotFound {
+		t.Logf("\nExpected %#v\nbut got  %#v", sqlstorage.ErrItemNotFound, err)
+		t.Fail()
+	}
+}
+
+func testOrganizationDelete(b *Backend, testOrganizations []*Organization, t *testing.T) {
+	testItemDelete(b, &Organization{}, testInterfaceToSlice(testOrganizations), t)
+}
+
+func testOrganizationDeleteAll(b *Backend, testOrganizations []*Organization, t *testing.T) {
+	testItemDeleteAll(b, &Organization{}, testInterfaceToSlice(testOrganizations), t)
+}
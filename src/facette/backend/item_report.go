@@ -0,0 +1,64 @@
+package backend
+
+import (
+	"time"
+
+	"facette/cron"
+	"facette/timerange"
+
+	"github.com/facette/maputil"
+	"github.com/jinzhu/gorm"
+)
+
+// ReportDefaultRange represents the default report time range.
+const ReportDefaultRange = "-1w"
+
+// Report represents a back-end scheduled report item instance. Reports only include the graphs visible to their
+// owner, private ones being left out if they have none.
+type Report struct {
+	Item
+	CollectionID string      `gorm:"column:collection;type:varchar(36) NOT NULL REFERENCES collections (id) ON DELETE CASCADE ON UPDATE CASCADE" json:"collection"`
+	Attributes   maputil.Map `gorm:"type:text" json:"attributes,omitempty"`
+	Range        string      `gorm:"type:varchar(32);not null" json:"range"`
+	Schedule     string      `gorm:"type:varchar(64);not null" json:"schedule"`
+	ChannelID    *string     `gorm:"column:channel;type:varchar(36) DEFAULT NULL REFERENCES channels (id) ON DELETE SET NULL ON UPDATE CASCADE" json:"channel,omitempty"`
+	Directory    string      `gorm:"type:varchar(512)" json:"directory,omitempty"`
+	Enabled      bool        `gorm:"not null" json:"enabled"`
+	OwnerID      *string     `gorm:"column:owner;type:varchar(36) DEFAULT NULL REFERENCES users (id) ON DELETE SET NULL ON UPDATE CASCADE" json:"owner,omitempty"`
+}
+
+// NewReport creates a new back-end report item instance.
+func (b *Backend) NewReport() *Report {
+	return &Report{Item: Item{backend: b}}
+}
+
+// BeforeSave handles the ORM 'BeforeSave' callback.
+func (r *Report) BeforeSave(scope *gorm.Scope) error {
+	if err := r.Item.BeforeSave(scope); err != nil {
+		return err
+	} else if _, err := cron.Parse(r.Schedule); err != nil {
+		return ErrInvalidSchedule
+	}
+
+	if r.Range == "" {
+		scope.SetColumn("Range", ReportDefaultRange)
+	} else if _, err := timerange.Apply(time.Now(), r.Range); err != nil {
+		return ErrInvalidTimerange
+	}
+
+	// Ensure report is either delivered through a channel or written to a directory
+	if (r.ChannelID == nil || *r.ChannelID == "") && r.Directory == "" {
+		return ErrInvalidTarget
+	}
+
+	// Ensure optional fields are null if empty
+	if r.ChannelID != nil && *r.ChannelID == "" {
+		scope.SetColumn("ChannelID", nil)
+	}
+
+	if r.OwnerID != nil && *r.OwnerID == "" {
+		scope.SetColumn("OwnerID", nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/facette/maputil"
+	"github.com/facette/sqlstorage"
+)
+
+const testReportCollectionID = "00000000-0000-0000-0000-000000000001"
+
+func testReportNew() []*Report {
+	directory := "/var/lib/facette/reports"
+
+	return []*Report{
+		&Report{
+			Item: Item{
+				Name: "item1",
+			},
+			CollectionID: testReportCollectionID,
+			Attributes: maputil.Map{
+				"source": "host1.example.net",
+			},
+			Range:     "-1w",
+			Schedule:  "0 8 * * mon",
+			Directory: directory,
+			Enabled:   true,
+		},
+
+		&Report{
+			Item: Item{
+				Name: "item2",
+			},
+			CollectionID: testReportCollectionID,
+			Range:        "-1d",
+			Schedule:     "@daily",
+			Directory:    directory,
+			Enabled:      true,
+		},
+
+		&Report{
+			Item: Item{
+				Name: "item3",
+			},
+			CollectionID: testReportCollectionID,
+			Range:        "-1mo",
+			Schedule:     "@monthly",
+			Directory:    directory,
+			Enabled:      false,
+		},
+	}
+}
+
+func testReportCreate(b *Backend, testReports []*Report, t *testing.T) {
+	// Ensure referenced collection exists
+	collection := b.NewCollection()
+	if err := b.Storage().Get("id", testReportCollectionID, collection); err == sqlstorage.ErrItemNotFound {
+		collection.ID = testReportCollectionID
+		collection.Name = "report-collection"
+
+		if err := b.Storage().Save(collection); err != nil {
+			t.Logf("\nExpected <nil>\nbut got  %#v", err)
+			t.Fail()
+			return
+		}
+	}
+
+	testItemCreate(b, &Report{}, testInterfaceToSlice(testReports), t)
+}
+
+func testReportCreateInvalid(b *Backend, testReports []*Report, t *testing.T) {
+	testItemCreateInvalid(b, &Report{}, testInterfaceToSlice(testReports), t)
+}
+
+func testReportGet(b *Backend, testReports []*Report, t *testing.T) {
+	testItemGet(b, &Report{}, testInterfaceToSlice(testReports), t)
+}
+
+func testReportGetUnknown(b *Backend, testReports []*Report, t *testing.T) {
+	testItemGetUnknown(b, &Report{}, testInterfaceToSlice(testReports), t)
+}
+
+func testReportUpdate(b *Backend, testReports []*Report, t *testing.T) {
+	testItemUpdate(b, &Report{}, testInterfaceToSlice(testReports), t)
+}
+
+func testReportCount(b *Backend, testReports []*Report, t *testing.T) {
+	testItemCount(b, &Report{}, testInterfaceToSlice(testReports), t)
+}
+
+func testReportList(b *Backend, testReports []*Report, t *testing.T) {
+	testItemList(b, &Report{}, testInterfaceToSlice(testReports), t)
+}
+
+func testReportValidate(b *Backend, testReports []*Report, t *testing.T) {
+	for _, entry := range []struct {
+		update   func(*Report)
+		expected error
+	}{
+		{func(r *Report) { r.Schedule = "* * *" }, ErrInvalidSchedule},
+		{func(r *Report) { r.Range = "invalid" }, ErrInvalidTimerange},
+		{func(r *Report) { r.Directory = "" }, ErrInvalidTarget},
+	} {
+		report := &Report{}
+		*report = *testReports[0]
+		report.ID = ""
+		report.Name = "invalid"
+
+		entry.update(report)
+
+		if err := b.Storage().Save(report); err != entry.expected {
+			t.Logf("\nExpected %#v\nbut got  %#v", entry.expected, err)
+			t.Fail()
+		}
+	}
+}
+
+func testReportDelete(b *Backend, testReports []*Report, t *testing.T) {
+	testItemDelete(b, &Report{}, testInterfaceToSlice(testReports), t)
+}
+
+func testReportDeleteAll(b *Backend, testReports []*Report, t *testing.T) {
+	testItemDeleteAll(b, &Report{}, testInterfaceToSlice(testReports), t)
+}
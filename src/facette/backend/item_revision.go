@@ -0,0 +1,263 @@
+package backend
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// RevisionChangeAdded represents a field added between two revisions.
+	RevisionChangeAdded = "added"
+	// RevisionChangeRemoved represents a field removed between two revisions.
+	RevisionChangeRemoved = "removed"
+	// RevisionChangeModified represents a field modified between two revisions.
+	RevisionChangeModified = "modified"
+)
+
+// Revision represents a back-end item revision instance, holding the item data as saved at a given time. Revisions
+// are numbered sequentially for each item, starting at 1.
+type Revision struct {
+	ID             string       `gorm:"type:varchar(36);not null;primary_key" json:"id"`
+	ItemID         string       `gorm:"column:item;type:varchar(36);not null;unique_index:idx_revision_number" json:"item"`
+	ItemType       string       `gorm:"column:item_type;type:varchar(32);not null" json:"item_type"`
+	Number         int          `gorm:"not null;unique_index:idx_revision_number" json:"number"`
+	Author         *string      `gorm:"type:varchar(128)" json:"author"`
+	Data           RevisionData `gorm:"type:text;not null" json:"data,omitempty"`
+	OrganizationID *string      `gorm:"column:organization;type:varchar(36) DEFAULT NULL REFERENCES organizations (id) ON DELETE CASCADE ON UPDATE CASCADE" json:"-"`
+	Created        time.Time    `gorm:"not null;default:current_timestamp" json:"created"`
+}
+
+// BeforeSave handles the ORM 'BeforeSave' callback.
+func (r *Revision) BeforeSave(scope *gorm.Scope) error {
+	if r.ID == "" {
+		id, err := uuid.GenerateUUID()
+		if err != nil {
+			return err
+		}
+
+		scope.SetColumn("ID", id)
+	}
+
+	if r.Created.IsZero() {
+		scope.SetColumn("Created", time.Now().UTC().Round(time.Second))
+	}
+
+	// Ensure optional fields are null if empty
+	if r.Author != nil && *r.Author == "" {
+		scope.SetColumn("Author", nil)
+	}
+
+	if r.OrganizationID != nil && *r.OrganizationID == "" {
+		scope.SetColumn("OrganizationID", nil)
+	}
+
+	return nil
+}
+
+// Diff returns the list of changes between a previous revision and the current one.
+func (r *Revision) Diff(prev *Revision) RevisionChangeList {
+	return revisionDiff(prev.Data, r.Data)
+}
+
+// Decode fills a back-end item with the revision data.
+func (r *Revision) Decode(item interface{}) error {
+	data, err := json.Marshal(r.Data)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, item)
+}
+
+// RevisionData represents a back-end item revision data.
+type RevisionData map[string]interface{}
+
+// Value marshals the revision data for compatibility with SQL drivers.
+func (rd RevisionData) Value() (driver.Value, error) {
+	data, err := json.Marshal(rd)
+	return data, err
+}
+
+// Scan unmarshals the revision data retrieved from SQL drivers.
+func (rd *RevisionData) Scan(v interface{}) error {
+	return scanValue(v, rd)
+}
+
+// RevisionChange represents a field change between two revisions, the field path being expressed using the JSON
+// representation of the item (e.g. "groups[0].series[1].metric").
+type RevisionChange struct {
+	Path string      `json:"path"`
+	Kind string      `json:"kind"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// RevisionChangeList represents a list of revision changes, sortable by field path.
+type RevisionChangeList []RevisionChange
+
+// Value marshals the revision changes list for compatibility with SQL drivers.
+func (l RevisionChangeList) Value() (driver.Value, error) {
+	data, err := json.Marshal(l)
+	return data, err
+}
+
+// Scan unmarshals the revision changes list retrieved from SQL drivers.
+func (l *RevisionChangeList) Scan(v interface{}) error {
+	return scanValue(v, l)
+}
+
+func (l RevisionChangeList) Len() int {
+	return len(l)
+}
+
+func (l RevisionChangeList) Less(i, j int) bool {
+	return l[i].Path < l[j].Path
+}
+
+func (l RevisionChangeList) Swap(i, j int) {
+	l[i], l[j] = l[j], l[i]
+}
+
+// SaveRevision stores a new revision of a back-end item given its type and the name of the user having saved it.
+func (b *Backend) SaveRevision(typ string, item interface{}, author string) (*Revision, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+
+	rev := &Revision{
+		ItemID:   reflect.Indirect(reflect.ValueOf(item)).FieldByName("ID").String(),
+		ItemType: typ,
+		Number:   1,
+		Author:   &author,
+	}
+
+	if err := json.Unmarshal(data, &rev.Data); err != nil {
+		return nil, err
+	}
+
+	// Item type isn't part of its data, as set only upon search results
+	delete(rev.Data, "type")
+
+	if v, ok := item.(OrganizationItem); ok {
+		organization := v.GetOrganization()
+		rev.OrganizationID = &organization
+	}
+
+	// Get last item revision number
+	revs := []*Revision{}
+	if _, err := b.storage.List(&revs, map[string]interface{}{"item": rev.ItemID}, []string{"-number"}, 0,
+		1); err != nil {
+		return nil, err
+	} else if len(revs) > 0 {
+		rev.Number = revs[0].Number + 1
+	}
+
+	if err := b.storage.Save(rev); err != nil {
+		return nil, err
+	}
+
+	return rev, nil
+}
+
+// DeleteRevisions removes all the revisions of a back-end item given its identifier.
+func (b *Backend) DeleteRevisions(id string) error {
+	revs := []*Revision{}
+	if _, err := b.storage.List(&revs, map[string]interface{}{"item": id}, nil, 0, 0); err != nil {
+		return err
+	}
+
+	for _, rev := range revs {
+		if err := b.storage.Delete(rev); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DiffItems returns the list of changes between two states of a back-end item, either of them being nil if the item
+// didn't exist.
+func DiffItems(prev, cur interface{}) (RevisionChangeList, error) {
+	data := [2]RevisionData{}
+
+	for i, item := range []interface{}{prev, cur} {
+		if item == nil {
+			continue
+		}
+
+		buf, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		} else if err := json.Unmarshal(buf, &data[i]); err != nil {
+			return nil, err
+		}
+
+		// Item type isn't part of its data, as set only upon search results
+		delete(data[i], "type")
+	}
+
+	return revisionDiff(data[0], data[1]), nil
+}
+
+func revisionDiff(prev, data RevisionData) RevisionChangeList {
+	old, cur := map[string]interface{}{}, map[string]interface{}{}
+	revisionFlatten(old, "", map[string]interface{}(prev))
+	revisionFlatten(cur, "", map[string]interface{}(data))
+
+	changes := RevisionChangeList{}
+
+	for path, v := range old {
+		if nv, ok := cur[path]; !ok {
+			changes = append(changes, RevisionChange{Path: path, Kind: RevisionChangeRemoved, Old: v})
+		} else if !reflect.DeepEqual(v, nv) {
+			changes = append(changes, RevisionChange{Path: path, Kind: RevisionChangeModified, Old: v, New: nv})
+		}
+	}
+
+	for path, v := range cur {
+		if _, ok := old[path]; !ok {
+			changes = append(changes, RevisionChange{Path: path, Kind: RevisionChangeAdded, New: v})
+		}
+	}
+
+	sort.Sort(changes)
+
+	return changes
+}
+
+func revisionFlatten(result map[string]interface{}, path string, v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 && path != "" {
+			result[path] = v
+		}
+
+		for key, value := range v {
+			if path != "" {
+				key = path + "." + key
+			}
+
+			revisionFlatten(result, key, value)
+		}
+
+	case []interface{}:
+		if len(v) == 0 && path != "" {
+			result[path] = v
+		}
+
+		for i, value := range v {
+			revisionFlatten(result, fmt.Sprintf("%s[%d]", path, i), value)
+		}
+
+	default:
+		result[path] = v
+	}
+}
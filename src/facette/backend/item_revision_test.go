@@ -0,0 +1,106 @@
+package backend
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testGraphRevisions(b *Backend, testGraphs []*Graph, t *testing.T) {
+	graph := b.NewGraph()
+	if err := b.Storage().Get("name", "item1", graph); err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	}
+
+	first, err := b.SaveRevision("graphs", graph, "user1")
+	if err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	}
+
+	graph.Options["title"] = "A new graph title"
+	graph.Groups[0].Series[0].Metric = "metric2"
+
+	second, err := b.SaveRevision("graphs", graph, "")
+	if err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	} else if first.Number != 1 || second.Number != 2 {
+		t.Logf("\nExpected revisions 1 and 2\nbut got  %d and %d", first.Number, second.Number)
+		t.Fail()
+	}
+
+	revs := []*Revision{}
+	if count, err := b.Storage().List(&revs, map[string]interface{}{"item": graph.ID}, []string{"number"}, 0,
+		0); err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	} else if count != 2 {
+		t.Fatalf("\nExpected %d\nbut got  %d", 2, count)
+	} else if revs[0].Author == nil || *revs[0].Author != "user1" || revs[1].Author != nil {
+		t.Logf("\nExpected %q and <nil> authors\nbut got  %v and %v", "user1", revs[0].Author, revs[1].Author)
+		t.Fail()
+	}
+
+	// Ensure item is restored as saved in the first revision
+	result := b.NewGraph()
+	if err := revs[0].Decode(result); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	} else if result.ID != graph.ID || result.Options["title"] != "A great graph title" ||
+		result.Groups[0].Series[0].Metric != "metric1" {
+		t.Logf("\nExpected %#v\nbut got  %#v", testGraphs[0], result)
+		t.Fail()
+	}
+
+	if err := b.DeleteRevisions(graph.ID); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	} else if count, _ := b.Storage().List(&revs, map[string]interface{}{"item": graph.ID}, nil, 0,
+		0); count != 0 {
+		t.Logf("\nExpected %d\nbut got  %d", 0, count)
+		t.Fail()
+	}
+}
+
+func Test_Revision_Diff(t *testing.T) {
+	prev := &Revision{Data: RevisionData{
+		"name":    "item1",
+		"options": map[string]interface{}{"title": "title1", "yaxis_unit": "bytes"},
+		"groups": []interface{}{
+			map[string]interface{}{"series": []interface{}{
+				map[string]interface{}{"metric": "metric1"},
+			}},
+		},
+	}}
+
+	cur := &Revision{Data: RevisionData{
+		"name":    "item1",
+		"options": map[string]interface{}{"title": "title2", "stacked": true},
+		"groups": []interface{}{
+			map[string]interface{}{"series": []interface{}{
+				map[string]interface{}{"metric": "metric1"},
+				map[string]interface{}{"metric": "metric2"},
+			}},
+		},
+	}}
+
+	expected := RevisionChangeList{
+		{Path: "groups[0].series[1].metric", Kind: RevisionChangeAdded, New: "metric2"},
+		{Path: "options.stacked", Kind: RevisionChangeAdded, New: true},
+		{Path: "options.title", Kind: RevisionChangeModified, Old: "title1", New: "title2"},
+		{Path: "options.yaxis_unit", Kind: RevisionChangeRemoved, Old: "bytes"},
+	}
+
+	if result := cur.Diff(prev); !reflect.DeepEqual(result, expected) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
+		t.Fail()
+	}
+
+	// Ensure first revision is compared with an empty one
+	if result := prev.Diff(&Revision{}); len(result) != 4 {
+		t.Logf("\nExpected 4 added fields\nbut got  %#v", result)
+		t.Fail()
+	}
+
+	if result := cur.Diff(cur); len(result) != 0 {
+		t.Logf("\nExpected no changes\nbut got  %#v", result)
+		t.Fail()
+	}
+}
@@ -0,0 +1,154 @@
+package backend
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/jinzhu/gorm"
+)
+
+// Snapshot represents a back-end snapshot instance, freezing a graph or a collection along with its plots over a given
+// time range. Snapshots are publicly accessible using their secret, of which only the hash is stored, and are kept
+// upon deletion of the item they have been taken from.
+type Snapshot struct {
+	ID             string       `gorm:"type:varchar(36);not null;primary_key" json:"id"`
+	ItemID         string       `gorm:"column:item;type:varchar(36);not null;index" json:"item"`
+	ItemType       string       `gorm:"column:item_type;type:varchar(32);not null" json:"item_type"`
+	Name           string       `gorm:"type:varchar(128);not null" json:"name"`
+	Hash           string       `gorm:"type:varchar(64);not null;unique_index" json:"-"`
+	Data           SnapshotData `gorm:"type:text;not null" json:"data,omitempty"`
+	Author         *string      `gorm:"type:varchar(128)" json:"author"`
+	OrganizationID *string      `gorm:"column:organization;type:varchar(36) DEFAULT NULL REFERENCES organizations (id) ON DELETE CASCADE ON UPDATE CASCADE" json:"-"`
+	Created        time.Time    `gorm:"not null;default:current_timestamp" json:"created"`
+	Expires        *time.Time   `json:"expires,omitempty"`
+}
+
+// NewSnapshot creates a new back-end snapshot instance of an item given its type, returning the snapshot secret
+// along with it.
+func NewSnapshot(typ string, item interface{}) (*Snapshot, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", err
+	}
+
+	secret := hex.EncodeToString(buf)
+
+	rv := reflect.Indirect(reflect.ValueOf(item))
+
+	snapshot := &Snapshot{
+		ItemID:   rv.FieldByName("ID").String(),
+		ItemType: typ,
+		Name:     rv.FieldByName("Name").String(),
+		Hash:     HashToken(secret),
+	}
+
+	if v, ok := item.(OrganizationItem); ok {
+		snapshot.SetOrganization(v.GetOrganization())
+	}
+
+	return snapshot, secret, nil
+}
+
+// BeforeSave handles the ORM 'BeforeSave' callback.
+func (s *Snapshot) BeforeSave(scope *gorm.Scope) error {
+	if s.ID == "" {
+		id, err := uuid.GenerateUUID()
+		if err != nil {
+			return err
+		}
+
+		scope.SetColumn("ID", id)
+	}
+
+	if s.Name == "" {
+		return ErrInvalidName
+	}
+
+	if s.Created.IsZero() {
+		scope.SetColumn("Created", time.Now().UTC().Round(time.Second))
+	}
+
+	// Store expiration time as UTC, as it is compared when purging expired snapshots
+	if s.Expires != nil {
+		scope.SetColumn("Expires", s.Expires.UTC())
+	}
+
+	// Ensure optional fields are null if empty
+	if s.Author != nil && *s.Author == "" {
+		scope.SetColumn("Author", nil)
+	}
+
+	if s.OrganizationID != nil && *s.OrganizationID == "" {
+		scope.SetColumn("OrganizationID", nil)
+	}
+
+	return nil
+}
+
+// GetOrganization returns the identifier of the organization the snapshot belongs to, or an empty string if none.
+func (s *Snapshot) GetOrganization() string {
+	if s.OrganizationID == nil {
+		return ""
+	}
+
+	return *s.OrganizationID
+}
+
+// SetOrganization sets the organization the snapshot belongs to given its identifier, an empty identifier meaning
+// none.
+func (s *Snapshot) SetOrganization(id string) {
+	if id == "" {
+		s.OrganizationID = nil
+	} else {
+		s.OrganizationID = &id
+	}
+}
+
+// SetData sets the snapshot frozen data given the expanded item and the plots of its graphs.
+func (s *Snapshot) SetData(item interface{}, plots []interface{}) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	s.Data = SnapshotData{Item: data, Plots: []json.RawMessage{}}
+
+	for _, p := range plots {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+
+		s.Data.Plots = append(s.Data.Plots, data)
+	}
+
+	return nil
+}
+
+// Expired returns whether or not the snapshot has expired at a given time.
+func (s *Snapshot) Expired(now time.Time) bool {
+	return s.Expires != nil && !now.Before(*s.Expires)
+}
+
+// SnapshotData represents a back-end snapshot frozen data, holding the expanded item and the plots of its graphs (a
+// single one for graphs, one per entry for collections).
+type SnapshotData struct {
+	Item  json.RawMessage   `json:"item,omitempty"`
+	Plots []json.RawMessage `json:"plots,omitempty"`
+}
+
+// Value marshals the snapshot data for compatibility with SQL drivers.
+func (sd SnapshotData) Value() (driver.Value, error) {
+	data, err := json.Marshal(sd)
+	return data, err
+}
+
+// Scan unmarshals the snapshot data retrieved from SQL drivers.
+func (sd *SnapshotData) Scan(v interface{}) error {
+	return scanValue(v, sd)
+}
@@ -0,0 +1,63 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func testGraphSnapshots(b *Backend, testGraphs []*Graph, t *testing.T) {
+	snapshot, secret, err := NewSnapshot("graphs", testGraphs[0])
+	if err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	} else if snapshot.Name != testGraphs[0].Name {
+		t.Logf("\nExpected %q\nbut got  %q", testGraphs[0].Name, snapshot.Name)
+		t.Fail()
+	}
+
+	expires := time.Now().UTC().Add(time.Hour).Round(time.Second)
+	snapshot.Expires = &expires
+
+	plots := []interface{}{map[string]interface{}{"series": []interface{}{}}}
+
+	if err := snapshot.SetData(testGraphs[0], plots); err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	} else if err := b.Storage().Save(snapshot); err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	}
+
+	// Ensure snapshot is retrieved using its secret hash along with its frozen data
+	result := &Snapshot{}
+	if err := b.Storage().Get("hash", HashToken(secret), result); err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	} else if result.ID != snapshot.ID || len(result.Data.Plots) != 1 ||
+		string(result.Data.Plots[0]) != `{"series":[]}` {
+		t.Logf("\nExpected %#v\nbut got  %#v", snapshot.Data, result.Data)
+		t.Fail()
+	}
+
+	if result.Expired(expires.Add(-time.Second)) {
+		t.Logf("\nExpected snapshot not to be expired")
+		t.Fail()
+	} else if !result.Expired(expires) {
+		t.Logf("\nExpected snapshot to be expired")
+		t.Fail()
+	}
+
+	// Ensure several snapshots of the same item can be taken
+	other, _, err := NewSnapshot("graphs", testGraphs[0])
+	if err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	} else if err := other.SetData(testGraphs[0], plots); err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	} else if err := b.Storage().Save(other); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	}
+
+	for _, item := range []*Snapshot{result, other} {
+		if err := b.Storage().Delete(item); err != nil {
+			t.Logf("\nExpected <nil>\nbut got  %#v", err)
+			t.Fail()
+		}
+	}
+}
@@ -140,6 +140,102 @@ func testItemUpdate(b *Backend, refItem interface{}, testItems []interface{}, t
 	}
 }
 
+func testItemConditionalSave(b *Backend, refItem interface{}, testItems []interface{}, t *testing.T) {
+	// Retrieve two copies of the same item, as if concurrently updated
+	item1 := testNewItem(refItem)
+	if err := b.Storage().Get("name", "item1", item1); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+		return
+	}
+
+	item2 := testNewItem(refItem)
+	if err := b.Storage().Get("name", "item1", item2); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+		return
+	}
+
+	modified := reflect.Indirect(reflect.ValueOf(item1)).FieldByName("Modified").Interface().(time.Time)
+
+	desc1, desc2 := "A first description", "A second description"
+	reflect.Indirect(reflect.ValueOf(item1)).FieldByName("Description").Set(reflect.ValueOf(&desc1))
+	reflect.Indirect(reflect.ValueOf(item2)).FieldByName("Description").Set(reflect.ValueOf(&desc2))
+
+	item1.(ConditionalItem).ExpectModified(modified)
+	if err := b.Storage().Save(item1); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	}
+
+	// Second save must fail even if happening within the same second as the first one
+	item2.(ConditionalItem).ExpectModified(modified)
+	if err := b.Storage().Save(item2); err != ErrItemModified {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrItemModified, err)
+		t.Fail()
+	}
+
+	item := testNewItem(refItem)
+	rv := reflect.Indirect(reflect.ValueOf(item))
+	if err := b.Storage().Get("name", "item1", item); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	} else if v := rv.FieldByName("Description").Interface().(*string); v == nil || *v != desc1 {
+		t.Logf("\nExpected %q\nbut got  %#v", desc1, v)
+		t.Fail()
+	} else if v := rv.FieldByName("Modified").Interface().(time.Time); !v.After(modified) {
+		t.Logf("\nExpected (.Modified after %s)\nbut got  %s", modified, v)
+		t.Fail()
+	}
+
+	// Restore test item state
+	if err := b.Storage().Get("name", "item1", testItems[0]); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	}
+}
+
+func testItemConflict(b *Backend, refItem interface{}, testItems []interface{}, t *testing.T) {
+	org := &Organization{Name: "conflict"}
+	if err := b.Storage().Save(org); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+		return
+	}
+	defer b.Storage().Delete(org)
+
+	newItem := func(organization string) interface{} {
+		item := testNewItem(refItem)
+		reflect.Indirect(reflect.ValueOf(item)).FieldByName("Name").SetString("item1")
+		item.(OrganizationItem).SetOrganization(organization)
+		return item
+	}
+
+	// Names are unique within organizations, including the default one
+	if err := b.Storage().Save(newItem("")); err != sqlstorage.ErrItemConflict {
+		t.Logf("\nExpected %#v\nbut got  %#v", sqlstorage.ErrItemConflict, err)
+		t.Fail()
+	}
+
+	item := newItem(org.ID)
+	if err := b.Storage().Save(item); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	}
+	defer b.Storage().Delete(item)
+
+	if err := b.Storage().Save(newItem(org.ID)); err != sqlstorage.ErrItemConflict {
+		t.Logf("\nExpected %#v\nbut got  %#v", sqlstorage.ErrItemConflict, err)
+		t.Fail()
+	}
+
+	// Saving an item again doesn't conflict with itself
+	if err := b.Storage().Save(item); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	}
+}
+
 func testItemCount(b *Backend, refItem interface{}, testItems []interface{}, t *testing.T) {
 	if count, err := b.Storage().Count(testNewItem(refItem)); err != nil {
 		t.Logf("\nExpected <nil>\nbut got  %#v", err)
@@ -0,0 +1,76 @@
+package backend
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// TokenKindAPI represents a long-lived API token.
+	TokenKindAPI = "api"
+	// TokenKindSession represents a login session token.
+	TokenKindSession = "session"
+)
+
+// Token represents a back-end user authentication token instance. Only the token hash is stored, the token itself
+// being returned to the user once upon creation.
+type Token struct {
+	ID       string     `gorm:"type:varchar(36);not null;primary_key" json:"id"`
+	UserID   string     `gorm:"column:user;type:varchar(36) NOT NULL REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE" json:"user"`
+	Kind     string     `gorm:"type:varchar(16);not null" json:"kind"`
+	Name     string     `gorm:"type:varchar(128)" json:"name,omitempty"`
+	Hash     string     `gorm:"type:varchar(64);not null;unique_index" json:"-"`
+	Created  time.Time  `gorm:"not null;default:current_timestamp" json:"created"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	LastUsed *time.Time `json:"last_used,omitempty"`
+}
+
+// NewToken creates a new back-end user authentication token instance, returning the token secret along with it.
+func NewToken(userID, kind string) (*Token, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", err
+	}
+
+	secret := hex.EncodeToString(buf)
+
+	return &Token{UserID: userID, Kind: kind, Hash: HashToken(secret)}, secret, nil
+}
+
+// HashToken returns the hash of a token secret, as stored in the back-end.
+func HashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// BeforeSave handles the ORM 'BeforeSave' callback.
+func (t *Token) BeforeSave(scope *gorm.Scope) error {
+	if t.ID == "" {
+		id, err := uuid.GenerateUUID()
+		if err != nil {
+			return err
+		}
+
+		scope.SetColumn("ID", id)
+	}
+
+	if t.Kind != TokenKindAPI && t.Kind != TokenKindSession {
+		return ErrInvalidKind
+	}
+
+	if t.Created.IsZero() {
+		scope.SetColumn("Created", time.Now().UTC().Round(time.Second))
+	}
+
+	return nil
+}
+
+// Expired returns whether or not the token has expired at a given time.
+func (t *Token) Expired(now time.Time) bool {
+	return t.Expires != nil && !now.Before(*t.Expires)
+}
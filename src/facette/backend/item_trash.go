@@ -0,0 +1,426 @@
+package backend
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/facette/maputil"
+	"github.com/facette/sqlstorage"
+	"github.com/hashicorp/go-uuid"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// TrashItemEntry represents a trashed collection entry, removed along with the graph it refers to.
+	TrashItemEntry = "entries"
+	// TrashItemParent represents a trashed collection parent, unset along with the collection it refers to.
+	TrashItemParent = "parents"
+)
+
+// TrashEntry represents a back-end trash entry instance, holding a deleted item along with the items deleted or
+// detached with it by the database cascading rules (e.g. graphs linked to a template graph, or the collection entries
+// referring to it), so that they can be restored together.
+type TrashEntry struct {
+	ID             string        `gorm:"type:varchar(36);not null;primary_key" json:"id"`
+	ItemID         string        `gorm:"column:item;type:varchar(36);not null;index" json:"item"`
+	ItemType       string        `gorm:"column:item_type;type:varchar(32);not null" json:"item_type"`
+	Name           string        `gorm:"type:varchar(128);not null" json:"name"`
+	Items          TrashItemList `gorm:"type:text;not null" json:"items,omitempty"`
+	Author         *string       `gorm:"type:varchar(128)" json:"author"`
+	OrganizationID *string       `gorm:"column:organization;type:varchar(36) DEFAULT NULL REFERENCES organizations (id) ON DELETE CASCADE ON UPDATE CASCADE" json:"-"`
+	Deleted        time.Time     `gorm:"not null;default:current_timestamp" json:"deleted"`
+}
+
+// TableName returns the table name to use in the database.
+func (TrashEntry) TableName() string {
+	return "trash"
+}
+
+// BeforeSave handles the ORM 'BeforeSave' callback.
+func (e *TrashEntry) BeforeSave(scope *gorm.Scope) error {
+	if e.ID == "" {
+		id, err := uuid.GenerateUUID()
+		if err != nil {
+			return err
+		}
+
+		scope.SetColumn("ID", id)
+	}
+
+	if e.Deleted.IsZero() {
+		scope.SetColumn("Deleted", time.Now().UTC().Round(time.Second))
+	}
+
+	// Ensure optional fields are null if empty
+	if e.Author != nil && *e.Author == "" {
+		scope.SetColumn("Author", nil)
+	}
+
+	if e.OrganizationID != nil && *e.OrganizationID == "" {
+		scope.SetColumn("OrganizationID", nil)
+	}
+
+	return nil
+}
+
+// GetOrganization returns the identifier of the organization the trashed item belongs to, or an empty string if none.
+func (e *TrashEntry) GetOrganization() string {
+	if e.OrganizationID == nil {
+		return ""
+	}
+
+	return *e.OrganizationID
+}
+
+// SetOrganization sets the organization the trashed item belongs to given its identifier, an empty identifier meaning
+// none.
+func (e *TrashEntry) SetOrganization(id string) {
+	if id == "" {
+		e.OrganizationID = nil
+	} else {
+		e.OrganizationID = &id
+	}
+}
+
+// Decode fills a back-end item with the trashed item data.
+func (e *TrashEntry) Decode(item interface{}) error {
+	if len(e.Items) == 0 {
+		return ErrUnresolvableItem
+	}
+
+	return json.Unmarshal(e.Items[0].Data, item)
+}
+
+// TrashItem represents a back-end trash entry item, either a deleted item or a detached reference.
+type TrashItem struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// TrashItemList represents a list of back-end trash entry items, in restoration order.
+type TrashItemList []*TrashItem
+
+// Value marshals the trash items list for compatibility with SQL drivers.
+func (tl TrashItemList) Value() (driver.Value, error) {
+	data, err := json.Marshal(tl)
+	return data, err
+}
+
+// Scan unmarshals the trash items list retrieved from SQL drivers.
+func (tl *TrashItemList) Scan(v interface{}) error {
+	return scanValue(v, tl)
+}
+
+// trashDependencies represents the items depending on an item type, given the column referring to it.
+var trashDependencies = map[string][]trashDependency{
+	"graphs": {
+		{"graphs", "link"},
+		{TrashItemEntry, "graph"},
+		{"alerts", "graph"},
+		{"annotations", "graph"},
+	},
+	"collections": {
+		{"collections", "link"},
+		{TrashItemParent, "parent"},
+		{"annotations", "collection"},
+		{"reports", "collection"},
+	},
+}
+
+type trashDependency struct {
+	typ    string
+	column string
+}
+
+type trashEntry struct {
+	Collection string      `json:"collection"`
+	Graph      string      `json:"graph"`
+	Index      int         `json:"index"`
+	Attributes maputil.Map `json:"attributes,omitempty"`
+	Options    maputil.Map `json:"options,omitempty"`
+}
+
+type trashParent struct {
+	Collection string `json:"collection"`
+	Parent     string `json:"parent"`
+}
+
+// NewTrashEntry creates a new back-end trash entry instance for an item about to be deleted given its type, gathering
+// the items depending on it.
+func (b *Backend) NewTrashEntry(typ string, item interface{}) (*TrashEntry, error) {
+	rv := reflect.Indirect(reflect.ValueOf(item))
+
+	e := &TrashEntry{
+		ItemID:   rv.FieldByName("ID").String(),
+		ItemType: typ,
+		Name:     rv.FieldByName("Name").String(),
+	}
+
+	if v, ok := item.(OrganizationItem); ok {
+		e.SetOrganization(v.GetOrganization())
+	}
+
+	if err := b.trashAppend(e, typ, item); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// RestoreTrashEntry restores a back-end trash entry items. Depending items that can't be restored anymore (e.g. their
+// name being taken by a newer item, or an item they refer to having been deleted since) are skipped and returned, and
+// restored collections entries referring to graphs deleted since are dropped.
+func (b *Backend) RestoreTrashEntry(e *TrashEntry) (TrashItemList, error) {
+	skipped := TrashItemList{}
+
+	for i, ti := range e.Items {
+		var err error
+
+		switch ti.Type {
+		case TrashItemEntry:
+			err = b.trashRestoreEntry(ti)
+
+		case TrashItemParent:
+			err = b.trashRestoreParent(ti)
+
+		default:
+			err = b.trashRestoreItem(ti)
+		}
+
+		if err != nil && i == 0 {
+			return nil, err
+		} else if err != nil {
+			skipped = append(skipped, ti)
+		}
+	}
+
+	return skipped, nil
+}
+
+// PurgeTrashEntry permanently removes a back-end trash entry, along with the revisions of its items.
+func (b *Backend) PurgeTrashEntry(e *TrashEntry) error {
+	for _, ti := range e.Items {
+		item := struct {
+			ID string `json:"id"`
+		}{}
+
+		if err := json.Unmarshal(ti.Data, &item); err != nil {
+			return err
+		} else if item.ID == "" {
+			continue
+		}
+
+		if err := b.DeleteRevisions(item.ID); err != nil {
+			return err
+		}
+	}
+
+	return b.storage.Delete(e)
+}
+
+func (b *Backend) trashAppend(e *TrashEntry, typ string, item interface{}) error {
+	if err := trashAppendItem(e, typ, item); err != nil {
+		return err
+	}
+
+	id := reflect.Indirect(reflect.ValueOf(item)).FieldByName("ID").String()
+
+	for _, dep := range trashDependencies[typ] {
+		switch dep.typ {
+		case TrashItemEntry:
+			entries := []*CollectionEntry{}
+			if _, err := b.storage.List(&entries, map[string]interface{}{dep.column: id}, nil, 0, 0); err != nil {
+				return err
+			}
+
+			for _, entry := range entries {
+				if err := trashAppendItem(e, dep.typ, trashEntry{
+					Collection: entry.CollectionID,
+					Graph:      entry.GraphID,
+					Index:      entry.Index,
+					Attributes: entry.Attributes,
+					Options:    entry.Options,
+				}); err != nil {
+					return err
+				}
+			}
+
+		case TrashItemParent:
+			collections := []*Collection{}
+			if _, err := b.storage.List(&collections, map[string]interface{}{dep.column: id}, nil, 0,
+				0); err != nil {
+				return err
+			}
+
+			for _, c := range collections {
+				if err := trashAppendItem(e, dep.typ, trashParent{Collection: c.ID, Parent: id}); err != nil {
+					return err
+				}
+			}
+
+		default:
+			ref, _ := b.newTrashItem(dep.typ)
+
+			rv := reflect.New(reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(ref)), 0, 0).Type())
+			if _, err := b.storage.List(rv.Interface(), map[string]interface{}{dep.column: id}, nil, 0,
+				0); err != nil {
+				return err
+			}
+
+			for i, n := 0, reflect.Indirect(rv).Len(); i < n; i++ {
+				child := reflect.Indirect(rv).Index(i).Interface()
+
+				// Collections are listed without their entries
+				if c, ok := child.(*Collection); ok {
+					if err := b.storage.Get("id", c.ID, c); err != nil {
+						return err
+					}
+				}
+
+				if err := b.trashAppend(e, dep.typ, child); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *Backend) trashRestoreItem(ti *TrashItem) error {
+	item, ok := b.newTrashItem(ti.Type)
+	if !ok {
+		return ErrUnresolvableItem
+	} else if err := json.Unmarshal(ti.Data, item); err != nil {
+		return err
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(item))
+
+	// Ensure the item hasn't been restored since, as saving would override it
+	if err := b.storage.Get("id", rv.FieldByName("ID").String(), reflect.New(rv.Type()).Interface()); err == nil {
+		return sqlstorage.ErrItemConflict
+	} else if err != sqlstorage.ErrItemNotFound {
+		return err
+	}
+
+	// Detach item from the users and collections deleted since, as they wouldn't prevent restoring it
+	if v, ok := item.(ACLItem); ok {
+		if acl := v.GetACL(); acl.OwnerID != nil && !b.trashExists(&User{}, *acl.OwnerID) {
+			acl.OwnerID = nil
+		}
+	}
+
+	if c, ok := item.(*Collection); ok {
+		if c.ParentID != nil && !b.trashExists(&Collection{}, *c.ParentID) {
+			c.ParentID = nil
+		}
+
+		entries := []*CollectionEntry{}
+		for _, entry := range c.Entries {
+			if b.trashExists(&Graph{}, entry.GraphID) {
+				entries = append(entries, entry)
+			}
+		}
+		c.Entries = entries
+	}
+
+	return b.storage.Save(item)
+}
+
+func (b *Backend) trashRestoreEntry(ti *TrashItem) error {
+	entry := trashEntry{}
+	if err := json.Unmarshal(ti.Data, &entry); err != nil {
+		return err
+	}
+
+	c := &Collection{}
+	if err := b.storage.Get("id", entry.Collection, c); err != nil {
+		return err
+	}
+
+	for _, e := range c.Entries {
+		if e.GraphID == entry.Graph {
+			return sqlstorage.ErrItemConflict
+		}
+	}
+
+	// Insert entry back at its original position
+	idx := entry.Index - 1
+	if idx < 0 {
+		idx = 0
+	} else if idx > len(c.Entries) {
+		idx = len(c.Entries)
+	}
+
+	c.Entries = append(c.Entries[:idx], append([]*CollectionEntry{{
+		GraphID:    entry.Graph,
+		Attributes: entry.Attributes,
+		Options:    entry.Options,
+	}}, c.Entries[idx:]...)...)
+
+	return b.storage.Save(c)
+}
+
+func (b *Backend) trashRestoreParent(ti *TrashItem) error {
+	parent := trashParent{}
+	if err := json.Unmarshal(ti.Data, &parent); err != nil {
+		return err
+	}
+
+	c := &Collection{}
+	if err := b.storage.Get("id", parent.Collection, c); err != nil {
+		return err
+	} else if c.ParentID != nil {
+		// Collection has been moved since
+		return sqlstorage.ErrItemConflict
+	}
+
+	c.ParentID = &parent.Parent
+
+	return b.storage.Save(c)
+}
+
+func (b *Backend) trashExists(item interface{}, id string) bool {
+	return b.storage.Get("id", id, item) == nil
+}
+
+func (b *Backend) newTrashItem(typ string) (interface{}, bool) {
+	switch typ {
+	case "collections":
+		return b.NewCollection(), true
+
+	case "graphs":
+		return b.NewGraph(), true
+
+	case "sourcegroups":
+		return b.NewSourceGroup(), true
+
+	case "metricgroups":
+		return b.NewMetricGroup(), true
+
+	case "alerts":
+		return b.NewAlertRule(), true
+
+	case "annotations":
+		return b.NewAnnotation(), true
+
+	case "reports":
+		return b.NewReport(), true
+	}
+
+	return nil, false
+}
+
+func trashAppendItem(e *TrashEntry, typ string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	e.Items = append(e.Items, &TrashItem{Type: typ, Data: data})
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/facette/sqlstorage"
+)
+
+func testGraphTrash(b *Backend, testGraphs []*Graph, t *testing.T) {
+	tmpl := b.NewGraph()
+	tmpl.Name = "trash-template1"
+	tmpl.Template = true
+
+	if err := b.Storage().Save(tmpl); err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	}
+
+	graph := b.NewGraph()
+	graph.Name = "trash-graph1"
+	graph.LinkID = &tmpl.ID
+
+	other := b.NewGraph()
+	other.Name = "trash-graph2"
+
+	for _, g := range []*Graph{graph, other} {
+		if err := b.Storage().Save(g); err != nil {
+			t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+		}
+	}
+
+	collection := b.NewCollection()
+	collection.Name = "trash-collection1"
+	collection.Entries = []*CollectionEntry{{GraphID: other.ID}, {GraphID: graph.ID}, {GraphID: other.ID}}
+
+	if err := b.Storage().Save(collection); err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	}
+
+	child := b.NewCollection()
+	child.Name = "trash-collection2"
+	child.ParentID = &collection.ID
+
+	if err := b.Storage().Save(child); err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	}
+
+	// Ensure linked graph and collection entry are restored along with the template graph
+	entry, err := b.NewTrashEntry("graphs", tmpl)
+	if err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	} else if len(entry.Items) != 3 {
+		t.Fatalf("\nExpected %d\nbut got  %d", 3, len(entry.Items))
+	}
+
+	if err := b.Storage().Delete(tmpl); err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	} else if err := b.Storage().Get("id", graph.ID, b.NewGraph()); err != sqlstorage.ErrItemNotFound {
+		t.Fatalf("\nExpected %#v\nbut got  %#v", sqlstorage.ErrItemNotFound, err)
+	}
+
+	if skipped, err := b.RestoreTrashEntry(entry); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	} else if len(skipped) != 0 {
+		t.Logf("\nExpected no skipped items\nbut got  %#v", skipped)
+		t.Fail()
+	}
+
+	result := b.NewCollection()
+	if err := b.Storage().Get("id", collection.ID, result); err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	} else if len(result.Entries) != 3 || result.Entries[1].GraphID != graph.ID {
+		t.Logf("\nExpected %q graph as second entry\nbut got  %#v", graph.ID, result.Entries)
+		t.Fail()
+	}
+
+	// Ensure restoring twice fails
+	if _, err := b.RestoreTrashEntry(entry); err != sqlstorage.ErrItemConflict {
+		t.Logf("\nExpected %#v\nbut got  %#v", sqlstorage.ErrItemConflict, err)
+		t.Fail()
+	}
+
+	// Ensure child collection is attached back to its restored parent
+	if entry, err = b.NewTrashEntry("collections", result); err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	} else if err := b.Storage().Delete(result); err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	} else if _, err := b.RestoreTrashEntry(entry); err != nil {
+		t.Fatalf("\nExpected <nil>\nbut got  %#v", err)
+	}
+
+	result = b.NewCollection()
+	if err := b.Storage().Get("id", child.ID, result); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	} else if result.ParentID == nil || *result.ParentID != collection.ID {
+		t.Logf("\nExpected %q\nbut got  %v", collection.ID, result.ParentID)
+		t.Fail()
+	}
+
+	for _, item := range []interface{}{child, collection, tmpl, other} {
+		if err := b.Storage().Delete(item); err != nil {
+			t.Logf("\nExpected <nil>\nbut got  %#v", err)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package backend
+
+import (
+	"github.com/jinzhu/gorm"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// RoleViewer represents the read-only user role.
+	RoleViewer = "viewer"
+	// RoleEditor represents the library editor user role.
+	RoleEditor = "editor"
+	// RoleAdmin represents the administrator user role.
+	RoleAdmin = "admin"
+
+	// OriginLocal represents the origin of users authenticated with their back-end password.
+	OriginLocal = "local"
+)
+
+// User represents a back-end user item instance. Role column defaults to administrator so that users created prior to
+// roles introduction keep their privileges, new users defaulting to viewer unless specified.
+type User struct {
+	Item
+	Email        *string `gorm:"type:varchar(254)" json:"email"`
+	Password     string  `gorm:"-" json:"password,omitempty"`
+	PasswordHash string  `gorm:"column:password;type:varchar(60);not null" json:"-"`
+	Role         string  `gorm:"type:varchar(16);not null;default:'admin'" json:"role"`
+	Origin       string  `gorm:"type:varchar(32);not null;default:'local'" json:"origin"`
+	Enabled      bool    `gorm:"not null" json:"enabled"`
+}
+
+// NewUser creates a new back-end user item instance.
+func (b *Backend) NewUser() *User {
+	return &User{Item: Item{backend: b}}
+}
+
+// BeforeSave handles the ORM 'BeforeSave' callback.
+func (u *User) BeforeSave(scope *gorm.Scope) error {
+	if err := u.Item.BeforeSave(scope); err != nil {
+		return err
+	}
+
+	switch u.Role {
+	case "":
+		u.Role = RoleViewer
+		scope.SetColumn("Role", u.Role)
+
+	case RoleViewer, RoleEditor, RoleAdmin:
+
+	default:
+		return ErrInvalidRole
+	}
+
+	if u.Origin == "" {
+		u.Origin = OriginLocal
+		scope.SetColumn("Origin", u.Origin)
+	}
+
+	// Hash password if a new one is provided, as plain passwords must never be stored. Users provisioned by external
+	// authentication backends don't need any.
+	if u.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+
+		u.Password = ""
+		scope.SetColumn("PasswordHash", string(hash))
+	} else if u.PasswordHash == "" && u.Origin == OriginLocal {
+		return ErrInvalidPassword
+	}
+
+	// Ensure optional fields are null if empty
+	if u.Email != nil && *u.Email == "" {
+		scope.SetColumn("Email", nil)
+	}
+
+	return nil
+}
+
+// CheckPassword returns whether or not a password matches the user one.
+func (u *User) CheckPassword(password string) bool {
+	return u.PasswordHash != "" && bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) == nil
+}
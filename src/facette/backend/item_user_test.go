@@ -0,0 +1,200 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/facette/sqlstorage"
+)
+
+func testUserNew() []*User {
+	email := "item1@example.net"
+
+	return []*User{
+		&User{
+			Item: Item{
+				Name: "item1",
+			},
+			Email:    &email,
+			Password: "secret1",
+			Enabled:  true,
+		},
+
+		&User{
+			Item: Item{
+				Name: "item2",
+			},
+			Password: "secret2",
+			Role:     RoleEditor,
+			Enabled:  true,
+		},
+
+		&User{
+			Item: Item{
+				Name: "item3",
+			},
+			Password: "secret3",
+			Enabled:  false,
+		},
+	}
+}
+
+func testUserCreate(b *Backend, testUsers []*User, t *testing.T) {
+	testItemCreate(b, &User{}, testInterfaceToSlice(testUsers), t)
+}
+
+func testUserCreateInvalid(b *Backend, testUsers []*User, t *testing.T) {
+	testItemCreateInvalid(b, &User{}, testInterfaceToSlice(testUsers), t)
+
+	user := &User{Item: Item{Name: "invalid"}}
+	if err := b.Storage().Save(user); err != ErrInvalidPassword {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrInvalidPassword, err)
+		t.Fail()
+	}
+
+	user = &User{Item: Item{Name: "invalid"}, Password: "secret", Role: "invalid"}
+	if err := b.Storage().Save(user); err != ErrInvalidRole {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrInvalidRole, err)
+		t.Fail()
+	}
+}
+
+func testUserGet(b *Backend, testUsers []*User, t *testing.T) {
+	testItemGet(b, &User{}, testInterfaceToSlice(testUsers), t)
+}
+
+func testUserGetUnknown(b *Backend, testUsers []*User, t *testing.T) {
+	testItemGetUnknown(b, &User{}, testInterfaceToSlice(testUsers), t)
+}
+
+func testUserUpdate(b *Backend, testUsers []*User, t *testing.T) {
+	testItemUpdate(b, &User{}, testInterfaceToSlice(testUsers), t)
+}
+
+func testUserCount(b *Backend, testUsers []*User, t *testing.T) {
+	testItemCount(b, &User{}, testInterfaceToSlice(testUsers), t)
+}
+
+func testUserList(b *Backend, testUsers []*User, t *testing.T) {
+	testItemList(b, &User{}, testInterfaceToSlice(testUsers), t)
+}
+
+func testUserPassword(b *Backend, testUsers []*User, t *testing.T) {
+	user := &User{}
+	if err := b.Storage().Get("name", "item1", user); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+		return
+	} else if user.PasswordHash == "secret1" {
+		t.Logf("\nExpected (hashed password)\nbut got  %#v", user.PasswordHash)
+		t.Fail()
+	}
+
+	for _, entry := range []struct {
+		password string
+		expected bool
+	}{
+		{"secret1", true},
+		{"secret2", false},
+		{"", false},
+	} {
+		if result := user.CheckPassword(entry.password); result != entry.expected {
+			t.Logf("\nExpected %v\nbut got  %v", entry.expected, result)
+			t.Fail()
+		}
+	}
+
+	// Ensure password is kept unless a new one is provided
+	if err := b.Storage().Save(user); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	} else if !user.CheckPassword("secret1") {
+		t.Logf("\nExpected %v\nbut got  %v", true, false)
+		t.Fail()
+	}
+
+	user.Password = "changed1"
+	if err := b.Storage().Save(user); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	} else if !user.CheckPassword("changed1") || user.CheckPassword("secret1") {
+		t.Logf("\nExpected (changed password)\nbut got  %#v", user.PasswordHash)
+		t.Fail()
+	}
+
+	// Ensure users provisioned by external authentication backends don't require any password
+	user = &User{Item: Item{Name: "external1"}, Origin: "ldap"}
+	if err := b.Storage().Save(user); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+		return
+	} else if user.CheckPassword("") {
+		t.Logf("\nExpected %v\nbut got  %v", false, true)
+		t.Fail()
+	}
+
+	if err := b.Storage().Delete(user); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	}
+}
+
+func testUserTokens(b *Backend, testUsers []*User, t *testing.T) {
+	user := &User{}
+	if err := b.Storage().Get("name", "item2", user); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+		return
+	}
+
+	token, secret, err := NewToken(user.ID, TokenKindAPI)
+	if err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+		return
+	}
+
+	expires := time.Now().UTC().Add(time.Hour).Round(time.Second)
+	token.Name = "token1"
+	token.Expires = &expires
+
+	if err := b.Storage().Save(token); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	}
+
+	result := &Token{}
+	if err := b.Storage().Get("hash", HashToken(secret), result); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	} else if result.ID != token.ID || result.UserID != user.ID {
+		t.Logf("\nExpected %#v\nbut got  %#v", token, result)
+		t.Fail()
+	} else if result.Expired(time.Now()) || !result.Expired(expires) {
+		t.Logf("\nExpected token to expire at %s", expires)
+		t.Fail()
+	}
+
+	if err := b.Storage().Get("hash", HashToken("invalid"), &Token{}); err != sqlstorage.ErrItemNotFound {
+		t.Logf("\nExpected %#v\nbut got  %#v", sqlstorage.ErrItemNotFound, err)
+		t.Fail()
+	}
+
+	if err := b.Storage().Save(&Token{UserID: user.ID, Kind: "invalid", Hash: "invalid"}); err != ErrInvalidKind {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrInvalidKind, err)
+		t.Fail()
+	}
+
+	if err := b.Storage().Delete(token); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+	}
+}
+
+func testUserDelete(b *Backend, testUsers []*User, t *testing.T) {
+	testItemDelete(b, &User{}, testInterfaceToSlice(testUsers), t)
+}
+
+func testUserDeleteAll(b *Backend, testUsers []*User, t *testing.T) {
+	testItemDeleteAll(b, &User{}, testInterfaceToSlice(testUsers), t)
+}
@@ -25,6 +25,12 @@ type Connector interface {
 	Plots(*plot.Query) ([]plot.Series, error)
 }
 
+// AnnotationConnector represents a connector handler interface optionally implemented by connectors able to provide
+// annotations (e.g. events) for a time range.
+type AnnotationConnector interface {
+	Annotations(*plot.AnnotationQuery) ([]plot.Annotation, error)
+}
+
 // NewConnector creates a new instance of a connector handler.
 func NewConnector(typ string, name string, settings *maputil.Map, log *logger.Logger) (Connector, error) {
 	// Check for existing connector handler
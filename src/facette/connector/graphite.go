@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 const (
 	graphiteURLMetrics = "/metrics/index.json"
 	graphiteURLRender  = "/render"
+	graphiteURLEvents  = "/events/get_data"
 )
 
 type graphitePlot struct {
@@ -30,6 +32,13 @@ type graphitePlot struct {
 	Datapoints [][2]float64
 }
 
+type graphiteEvent struct {
+	When float64
+	What string
+	Data string
+	Tags interface{}
+}
+
 // graphiteConnector implements the connector handler for another Graphite instance.
 type graphiteConnector struct {
 	name          string
@@ -205,6 +214,81 @@ func (c *graphiteConnector) Plots(q *plot.Query) ([]plot.Series, error) {
 	return results, nil
 }
 
+// Annotations retrieves the Graphite events according to the query parameters and a time interval.
+func (c *graphiteConnector) Annotations(q *plot.AnnotationQuery) ([]plot.Annotation, error) {
+	var events []graphiteEvent
+
+	query := url.Values{}
+	query.Set("from", strconv.FormatInt(q.StartTime.Unix(), 10))
+	query.Set("until", strconv.FormatInt(q.EndTime.Unix(), 10))
+
+	// Graphite matches events having all the given tags, thus prefer the query ones if any
+	if q.Query != "" {
+		query.Set("tags", q.Query)
+	} else if len(q.Tags) > 0 {
+		query.Set("tags", strings.Join(q.Tags, " "))
+	}
+
+	// Request events from back-end
+	r, err := http.NewRequest("GET", strings.TrimSuffix(c.url, "/")+graphiteURLEvents+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("graphite[%s]: unable to set up HTTP request: %s", c.name, err)
+	}
+
+	r.Header.Add("User-Agent", "Facette")
+	r.Header.Add("X-Requested-With", "GraphiteConnector")
+
+	rsp, err := c.client.Do(r)
+	if err != nil {
+		return nil, fmt.Errorf("graphite[%s]: unable to perform HTTP request: %s", c.name, err)
+	}
+	defer rsp.Body.Close()
+
+	// Parse back-end response
+	if err = graphiteCheckBackendResponse(rsp); err != nil {
+		return nil, fmt.Errorf("graphite[%s]: invalid HTTP backend response: %s", c.name, err)
+	}
+
+	data, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("graphite[%s]: unable to read HTTP response body: %s", c.name, err)
+	}
+
+	if err = json.Unmarshal(data, &events); err != nil {
+		return nil, fmt.Errorf("graphite[%s]: unable to unmarshal JSON data: %s", c.name, err)
+	}
+
+	results := []plot.Annotation{}
+	for _, e := range events {
+		a := plot.Annotation{
+			Source: c.name,
+			Start:  time.Unix(int64(e.When), 0).UTC(),
+			Text:   e.What,
+		}
+
+		if e.Data != "" {
+			a.Text += "\n" + e.Data
+		}
+
+		// Tags are either returned as list or as space-separated string depending on Graphite version
+		switch v := e.Tags.(type) {
+		case string:
+			a.Tags = strings.Fields(v)
+
+		case []interface{}:
+			for _, tag := range v {
+				if s, ok := tag.(string); ok {
+					a.Tags = append(a.Tags, s)
+				}
+			}
+		}
+
+		results = append(results, a)
+	}
+
+	return results, nil
+}
+
 func graphiteCheckBackendResponse(resp *http.Response) error {
 	if resp.StatusCode != 200 {
 		return fmt.Errorf("got HTTP status code %d, expected 200", resp.StatusCode)
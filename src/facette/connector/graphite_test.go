@@ -0,0 +1,109 @@
+// +build !disable_connector_graphite
+
+package connector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"facette/plot"
+
+	"github.com/facette/maputil"
+)
+
+func Test_Graphite_Annotations(t *testing.T) {
+	var query url.Values
+
+	// Tags are returned as a list by recent Graphite versions, and as a space-separated string by older ones
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != graphiteURLEvents {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		query = r.URL.Query()
+
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Write([]byte(`[
+			{"when": 10, "what": "deploy", "data": "version 1.2.3", "tags": ["deploy", "app1"]},
+			{"when": 20, "what": "restart", "data": "", "tags": "restart app1"},
+			{"when": 30, "what": "maintenance", "tags": null}
+		]`))
+	}))
+	defer server.Close()
+
+	c, err := NewConnector("graphite", "graphite1", &maputil.Map{
+		"url":     server.URL,
+		"pattern": "(?P<source>[^\\.]+)\\.(?P<metric>.+)",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := c.(AnnotationConnector).Annotations(&plot.AnnotationQuery{
+		StartTime: time.Unix(0, 0),
+		EndTime:   time.Unix(60, 0),
+		Tags:      []string{"app1", "deploy"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []plot.Annotation{
+		{Source: "graphite1", Start: time.Unix(10, 0).UTC(), Text: "deploy\nversion 1.2.3",
+			Tags: []string{"deploy", "app1"}},
+		{Source: "graphite1", Start: time.Unix(20, 0).UTC(), Text: "restart", Tags: []string{"restart", "app1"}},
+		{Source: "graphite1", Start: time.Unix(30, 0).UTC(), Text: "maintenance"},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
+		t.Fail()
+	}
+
+	expectedQuery := url.Values{"from": {"0"}, "until": {"60"}, "tags": {"app1 deploy"}}
+	if !reflect.DeepEqual(query, expectedQuery) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expectedQuery, query)
+		t.Fail()
+	}
+
+	// Query parameter overrides annotation source tags
+	if _, err = c.(AnnotationConnector).Annotations(&plot.AnnotationQuery{
+		StartTime: time.Unix(0, 0),
+		EndTime:   time.Unix(60, 0),
+		Query:     "release",
+		Tags:      []string{"app1"},
+	}); err != nil {
+		t.Fatal(err)
+	} else if query.Get("tags") != "release" {
+		t.Logf("\nExpected %q\nbut got  %q", "release", query.Get("tags"))
+		t.Fail()
+	}
+}
+
+func Test_Graphite_Annotations_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	c, err := NewConnector("graphite", "graphite1", &maputil.Map{
+		"url":     server.URL,
+		"pattern": "(?P<source>[^\\.]+)\\.(?P<metric>.+)",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.(AnnotationConnector).Annotations(&plot.AnnotationQuery{
+		StartTime: time.Unix(0, 0),
+		EndTime:   time.Unix(60, 0),
+	}); err == nil {
+		t.Log("\nExpected error\nbut got  <nil>")
+		t.Fail()
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"github.com/influxdata/influxdb/influxql"
 )
 
+const influxDBDefaultEventsMeasurement = "events"
+
 type influxDBMap struct {
 	source []string
 	metric []string
@@ -43,6 +45,7 @@ type influxdbConnector struct {
 	database      string
 	pattern       *regexp.Regexp
 	mapping       influxDBMap
+	events        string
 	client        influxdb.Client
 }
 
@@ -88,6 +91,10 @@ func init() {
 			return nil, ErrMissingConnectorSetting("database")
 		}
 
+		if c.events, err = settings.GetString("events_measurement", influxDBDefaultEventsMeasurement); err != nil {
+			return nil, err
+		}
+
 		if settings.Has("pattern") && settings.Has("mapping") {
 			return nil, fmt.Errorf("connector settings allows either %q or %q, not both", "pattern", "mapping")
 		} else if !settings.Has("pattern") && !settings.Has("mapping") {
@@ -391,6 +398,92 @@ func (c *influxdbConnector) Plots(q *plot.Query) ([]plot.Series, error) {
 	return results, nil
 }
 
+// Annotations retrieves the events stored in the events measurement according to the query parameters and a time
+// interval. Events text is read from either the "text" or "title" field, and tags from the comma-separated "tags"
+// field. The query parameter, if any, overrides the events measurement name.
+func (c *influxdbConnector) Annotations(q *plot.AnnotationQuery) ([]plot.Annotation, error) {
+	measurement := c.events
+	if q.Query != "" {
+		measurement = q.Query
+	}
+
+	query := influxdb.Query{
+		Command: fmt.Sprintf(
+			"select * from %s where time >= %ds and time <= %ds order by asc",
+			influxql.QuoteIdent(measurement),
+			q.StartTime.Unix(),
+			q.EndTime.Unix(),
+		),
+		Database: c.database,
+	}
+
+	// Execute query
+	response, err := c.client.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch events: %s", err)
+	} else if response.Error() != nil {
+		return nil, fmt.Errorf("failed to fetch events: %s", response.Error())
+	}
+
+	results := []plot.Annotation{}
+	for _, r := range response.Results {
+		for _, s := range r.Series {
+			for _, v := range s.Values {
+				a := plot.Annotation{Source: c.name}
+
+				for i, column := range s.Columns {
+					value, ok := v[i].(string)
+					if !ok {
+						continue
+					}
+
+					switch column {
+					case "time":
+						if a.Start, err = time.Parse(time.RFC3339Nano, value); err != nil {
+							return nil, fmt.Errorf("failed to parse time: %s", value)
+						}
+
+					case "text":
+						a.Text = value
+
+					case "title":
+						if a.Text == "" {
+							a.Text = value
+						}
+
+					case "tags":
+						for _, tag := range strings.Split(value, ",") {
+							if tag = strings.TrimSpace(tag); tag != "" {
+								a.Tags = append(a.Tags, tag)
+							}
+						}
+					}
+				}
+
+				if len(q.Tags) > 0 && !influxDBMatchTags(a.Tags, q.Tags) {
+					continue
+				}
+
+				results = append(results, a)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func influxDBMatchTags(tags, filter []string) bool {
+	for _, tag := range tags {
+		for _, f := range filter {
+			if tag == f {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func mapKey(seriesColumns map[string]string, item string) (string, string) {
 	if item == "name" {
 		return "", seriesColumns["name"]
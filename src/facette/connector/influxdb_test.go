@@ -0,0 +1,95 @@
+// +build !disable_connector_influxdb
+
+package connector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"facette/plot"
+
+	"github.com/facette/maputil"
+)
+
+func Test_InfluxDB_Annotations(t *testing.T) {
+	var command, database string
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/query" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		command = r.FormValue("q")
+		database = r.FormValue("db")
+
+		// Events text is read from either the "text" or "title" column, the former prevailing
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Write([]byte(`{"results": [{"statement_id": 0, "series": [{
+			"name": "events",
+			"columns": ["time", "host", "tags", "text", "title"],
+			"values": [
+				["1970-01-01T00:00:10Z", "host1", "deploy, app1", "version 1.2.3", "deploy"],
+				["1970-01-01T00:00:20Z", "host1", "restart,app2", null, "restart"],
+				["1970-01-01T00:00:30.5Z", "host2", null, "maintenance", null]
+			]
+		}]}]}`))
+	}))
+	defer server.Close()
+
+	c, err := NewConnector("influxdb", "influxdb1", &maputil.Map{
+		"url":      server.URL,
+		"database": "db1",
+		"pattern":  "(?P<source>[^\\.]+)\\.(?P<metric>.+)",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := &plot.AnnotationQuery{
+		StartTime: time.Unix(0, 0),
+		EndTime:   time.Unix(60, 0),
+	}
+
+	result, err := c.(AnnotationConnector).Annotations(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []plot.Annotation{
+		{Source: "influxdb1", Start: time.Unix(10, 0).UTC(), Text: "version 1.2.3", Tags: []string{"deploy", "app1"}},
+		{Source: "influxdb1", Start: time.Unix(20, 0).UTC(), Text: "restart", Tags: []string{"restart", "app2"}},
+		{Source: "influxdb1", Start: time.Unix(30, 500000000).UTC(), Text: "maintenance"},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
+		t.Fail()
+	}
+
+	expectedCommand := `select * from events where time >= 0s and time <= 60s order by asc`
+	if command != expectedCommand || database != "db1" {
+		t.Logf("\nExpected %q on %q\nbut got  %q on %q", expectedCommand, "db1", command, database)
+		t.Fail()
+	}
+
+	// Events not matching any of the tags are filtered out, and query parameter overrides the events measurement
+	q.Query = "deployments"
+	q.Tags = []string{"app1", "app3"}
+
+	if result, err = c.(AnnotationConnector).Annotations(q); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(result, expected[:1]) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected[:1], result)
+		t.Fail()
+	}
+
+	expectedCommand = `select * from deployments where time >= 0s and time <= 60s order by asc`
+	if command != expectedCommand {
+		t.Logf("\nExpected %q\nbut got  %q", expectedCommand, command)
+		t.Fail()
+	}
+}
@@ -0,0 +1,52 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Parse_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *",
+		"* * * foo *", "@never"} {
+		if _, err := Parse(spec); err != ErrInvalidSchedule {
+			t.Logf("\nExpected %#v for %q\nbut got  %#v", ErrInvalidSchedule, spec, err)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Schedule_Next(t *testing.T) {
+	// Reference time is Wednesday, January 3, 2018 10:42:30
+	ref := time.Date(2018, time.January, 3, 10, 42, 30, 0, time.UTC)
+
+	checks := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2018, time.January, 3, 10, 43, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2018, time.January, 3, 10, 45, 0, 0, time.UTC)},
+		{"30 9 * * *", time.Date(2018, time.January, 4, 9, 30, 0, 0, time.UTC)},
+		{"0 8 * * mon", time.Date(2018, time.January, 8, 8, 0, 0, 0, time.UTC)},
+		{"0 8 * * 7", time.Date(2018, time.January, 7, 8, 0, 0, 0, time.UTC)},
+		{"0 0 15 * fri", time.Date(2018, time.January, 5, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1,15 feb-mar *", time.Date(2018, time.February, 1, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2018, time.January, 7, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2018, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2018, time.January, 3, 11, 0, 0, 0, time.UTC)},
+	}
+
+	for _, c := range checks {
+		s, err := Parse(c.spec)
+		if err != nil {
+			t.Logf("\nExpected no error for %q\nbut got  %#v", c.spec, err)
+			t.Fail()
+			continue
+		}
+
+		if result := s.Next(ref); !result.Equal(c.expected) {
+			t.Logf("\nExpected %s for %q\nbut got  %s", c.expected, c.spec, result)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package cron
+
+import "errors"
+
+var (
+	// ErrInvalidSchedule represents an invalid schedule specification error.
+	ErrInvalidSchedule = errors.New("invalid schedule")
+)
@@ -0,0 +1,108 @@
+// +build !disable_notifier_command
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"facette/template"
+
+	"github.com/facette/logger"
+	"github.com/facette/maputil"
+)
+
+// commandNotifier implements the notifier handler executing local commands.
+type commandNotifier struct {
+	name    string
+	command string
+	args    []string
+	timeout time.Duration
+}
+
+func init() {
+	notifiers["command"] = func(name string, settings *maputil.Map, log *logger.Logger) (Notifier, error) {
+		var err error
+
+		n := &commandNotifier{
+			name: name,
+		}
+
+		// Load channel configuration
+		if n.command, err = settings.GetString("command", ""); err != nil {
+			return nil, err
+		} else if n.command == "" {
+			return nil, ErrMissingNotifierSetting("command")
+		}
+
+		if n.args, err = settings.GetStringSlice("args", nil); err != nil {
+			return nil, err
+		}
+
+		for _, arg := range n.args {
+			if _, err := template.Parse(arg); err != nil {
+				return nil, template.ErrInvalidTemplate
+			}
+		}
+
+		timeout, err := getInt(settings, "timeout", notifierDefaultTimeout)
+		if err != nil {
+			return nil, err
+		}
+		n.timeout = time.Duration(timeout) * time.Second
+
+		return n, nil
+	}
+}
+
+// Name returns the name of the current notifier.
+func (n *commandNotifier) Name() string {
+	return n.name
+}
+
+// Notify executes the command, passing notification attributes both as templated arguments and as FACETTE_*
+// environment variables. The notification message is also written to the command standard input.
+func (n *commandNotifier) Notify(notification *Notification) error {
+	attrs := notification.Attrs()
+
+	args := make([]string, len(n.args))
+	for i, arg := range n.args {
+		var err error
+		if args[i], err = template.Expand(arg, attrs); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, n.command, args...)
+	cmd.Stdin = strings.NewReader(notification.Message)
+	cmd.Env = os.Environ()
+
+	keys := []string{}
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("FACETTE_%s=%v", strings.ToUpper(k), attrs[k]))
+	}
+
+	output := bytes.NewBuffer(nil)
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("command failed: %s: %s", err, strings.TrimSpace(output.String()))
+	}
+
+	return nil
+}
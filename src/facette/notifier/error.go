@@ -0,0 +1,16 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnsupportedNotifier represents an unsupported notifier handler error.
+	ErrUnsupportedNotifier = errors.New("unsupported notifier handler")
+)
+
+// ErrMissingNotifierSetting creates a new missing notifier setting error.
+func ErrMissingNotifierSetting(key string) error {
+	return fmt.Errorf("missing mandatory %q notifier setting", key)
+}
@@ -0,0 +1,86 @@
+package notifier
+
+import (
+	"sort"
+	"time"
+
+	"github.com/facette/logger"
+	"github.com/facette/maputil"
+)
+
+const notifierDefaultTimeout = 10
+
+var (
+	version string
+
+	notifiers = make(map[string]func(string, *maputil.Map, *logger.Logger) (Notifier, error))
+)
+
+// Notifier represents a notification channel handler interface.
+type Notifier interface {
+	Name() string
+	Notify(*Notification) error
+}
+
+// Notification represents a notification message instance. Attachments are only delivered by notifiers supporting
+// them (i.e. SMTP), others ignore them.
+type Notification struct {
+	Subject     string
+	Message     string
+	Time        time.Time
+	Attributes  map[string]interface{}
+	Attachments []Attachment
+}
+
+// Attachment represents a notification file attachment instance.
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Attrs returns the notification template attributes, including both its subject and message.
+func (n *Notification) Attrs() map[string]interface{} {
+	attrs := make(map[string]interface{}, len(n.Attributes)+3)
+	for k, v := range n.Attributes {
+		attrs[k] = v
+	}
+
+	attrs["subject"] = n.Subject
+	attrs["message"] = n.Message
+	attrs["time"] = n.Time.Format(time.RFC3339)
+
+	return attrs
+}
+
+// NewNotifier creates a new instance of a notification channel handler.
+func NewNotifier(typ string, name string, settings *maputil.Map, log *logger.Logger) (Notifier, error) {
+	// Check for existing notifier handler
+	if _, ok := notifiers[typ]; !ok {
+		return nil, ErrUnsupportedNotifier
+	}
+
+	// Return new notifier handler instance
+	return notifiers[typ](name, settings, log)
+}
+
+// Notifiers returns the list of supported notification channel handlers.
+func Notifiers() []string {
+	list := []string{}
+	for name := range notifiers {
+		list = append(list, name)
+	}
+
+	sort.Strings(list)
+
+	return list
+}
+
+// getInt returns the integer value associated with a settings key, handling numbers decoded from JSON data.
+func getInt(settings *maputil.Map, key string, fallback int) (int, error) {
+	if v, ok := (*settings)[key].(float64); ok {
+		return int(v), nil
+	}
+
+	return settings.GetInt(key, fallback)
+}
@@ -0,0 +1,206 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facette/maputil"
+)
+
+var testNotification = &Notification{
+	Subject:    "alert1 is critical",
+	Message:    "alert1 changed from ok to critical",
+	Time:       time.Unix(0, 0).UTC(),
+	Attributes: map[string]interface{}{"state": "critical"},
+}
+
+func Test_Webhook_Notify(t *testing.T) {
+	var body, contentType, header string
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+		contentType = r.Header.Get("Content-Type")
+		header = r.Header.Get("X-Token")
+	}))
+	defer server.Close()
+
+	n, err := NewNotifier("webhook", "webhook1", &maputil.Map{
+		"url":          server.URL,
+		"body":         "{{ .state }}: {{ .subject }}",
+		"content_type": "text/plain",
+		"headers":      map[string]interface{}{"X-Token": "abc"},
+		"timeout":      5.0,
+	}, nil)
+	if err != nil {
+		t.Log(err)
+		t.FailNow()
+	}
+
+	if err := n.Notify(testNotification); err != nil {
+		t.Log(err)
+		t.Fail()
+	}
+
+	expected := "critical: alert1 is critical"
+	if body != expected {
+		t.Logf("\nExpected %q\nbut got  %q", expected, body)
+		t.Fail()
+	} else if contentType != "text/plain" {
+		t.Logf("\nExpected %q\nbut got  %q", "text/plain", contentType)
+		t.Fail()
+	} else if header != "abc" {
+		t.Logf("\nExpected %q\nbut got  %q", "abc", header)
+		t.Fail()
+	}
+}
+
+func Test_Webhook_Notify_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	n, err := NewNotifier("webhook", "webhook1", &maputil.Map{"url": server.URL}, nil)
+	if err != nil {
+		t.Log(err)
+		t.FailNow()
+	}
+
+	if err := n.Notify(testNotification); err == nil {
+		t.Logf("\nExpected error\nbut got  %#v", err)
+		t.Fail()
+	}
+}
+
+func Test_Command_Notify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "facette")
+	if err != nil {
+		t.Log(err)
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "output")
+
+	n, err := NewNotifier("command", "command1", &maputil.Map{
+		"command": "sh",
+		"args":    []interface{}{"-c", "echo \"$FACETTE_STATE {{ .subject }}\" > " + path},
+	}, nil)
+	if err != nil {
+		t.Log(err)
+		t.FailNow()
+	}
+
+	if err := n.Notify(testNotification); err != nil {
+		t.Log(err)
+		t.Fail()
+	}
+
+	data, _ := ioutil.ReadFile(path)
+
+	expected := "critical alert1 is critical\n"
+	if string(data) != expected {
+		t.Logf("\nExpected %q\nbut got  %q", expected, string(data))
+		t.Fail()
+	}
+}
+
+func Test_SMTP_Multipart(t *testing.T) {
+	attachments := []Attachment{
+		{Name: "report.csv", ContentType: "text/csv", Data: []byte(strings.Repeat("time,value\n", 10))},
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := smtpWriteMultipart(buf, "body\n", attachments); err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+		return
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(append([]byte("Subject: test\r\n"), buf.Bytes()...)))
+	if err != nil {
+		t.Logf("\nExpected <nil>\nbut got  %#v", err)
+		t.Fail()
+		return
+	}
+
+	_, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+
+	expected := []string{"body\r\n", string(attachments[0].Data)}
+	for i := 0; ; i++ {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			if i != len(expected) {
+				t.Logf("\nExpected %d parts\nbut got  %d", len(expected), i)
+				t.Fail()
+			}
+			break
+		} else if err != nil || i >= len(expected) {
+			t.Logf("\nUnexpected part %d: %v", i, err)
+			t.Fail()
+			break
+		}
+
+		var r io.Reader = part
+		if part.Header.Get("Content-Transfer-Encoding") == "base64" {
+			r = base64.NewDecoder(base64.StdEncoding, part)
+		}
+
+		data, _ := ioutil.ReadAll(r)
+		if string(data) != expected[i] {
+			t.Logf("\nExpected %q\nbut got  %q", expected[i], data)
+			t.Fail()
+		}
+	}
+}
+
+func Test_SMTP_EncodeSubject(t *testing.T) {
+	for _, test := range []struct {
+		subject  string
+		expected string
+	}{
+		{"alert1 is critical", "alert1 is critical"},
+		{"alert1 is critical\r\nBcc: user@example.net", "alert1 is critical Bcc: user@example.net"},
+		{"alert1\ris\ncritical", "alert1 is critical"},
+		{"alerte « disque » critique", "alerte « disque » critique"},
+	} {
+		v := smtpEncodeSubject(test.subject)
+		if strings.ContainsAny(v, "\r\n") {
+			t.Logf("\nExpected (no line break)\nbut got  %q", v)
+			t.Fail()
+		}
+
+		if decoded, err := new(mime.WordDecoder).DecodeHeader(v); err != nil || decoded != test.expected {
+			t.Logf("\nExpected %q\nbut got  %q (%v)", test.expected, decoded, err)
+			t.Fail()
+		}
+	}
+}
+
+func Test_NewNotifier_Invalid(t *testing.T) {
+	if _, err := NewNotifier("unknown", "unknown1", &maputil.Map{}, nil); err != ErrUnsupportedNotifier {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrUnsupportedNotifier, err)
+		t.Fail()
+	}
+
+	expected := ErrMissingNotifierSetting("to").Error()
+	if _, err := NewNotifier("smtp", "smtp1", &maputil.Map{"host": "localhost", "from": "a@b"}, nil); err == nil ||
+		err.Error() != expected {
+		t.Logf("\nExpected %q\nbut got  %#v", expected, err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,185 @@
+// +build !disable_notifier_smtp
+
+package notifier
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	"facette/template"
+
+	"github.com/facette/logger"
+	"github.com/facette/maputil"
+)
+
+const (
+	smtpDefaultSubject = "[Facette] {{ .subject }}"
+	smtpDefaultBody    = "{{ .message }}\n"
+)
+
+// smtpNotifier implements the notifier handler for SMTP email delivery.
+type smtpNotifier struct {
+	name     string
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+	to       []string
+	subject  string
+	body     string
+}
+
+func init() {
+	notifiers["smtp"] = func(name string, settings *maputil.Map, log *logger.Logger) (Notifier, error) {
+		var err error
+
+		n := &smtpNotifier{
+			name: name,
+		}
+
+		// Load channel configuration
+		if n.host, err = settings.GetString("host", ""); err != nil {
+			return nil, err
+		} else if n.host == "" {
+			return nil, ErrMissingNotifierSetting("host")
+		}
+
+		port, err := getInt(settings, "port", 25)
+		if err != nil {
+			return nil, err
+		}
+		n.addr = net.JoinHostPort(n.host, strconv.Itoa(port))
+
+		if n.username, err = settings.GetString("username", ""); err != nil {
+			return nil, err
+		}
+
+		if n.password, err = settings.GetString("password", ""); err != nil {
+			return nil, err
+		}
+
+		if n.from, err = settings.GetString("from", ""); err != nil {
+			return nil, err
+		} else if n.from == "" {
+			return nil, ErrMissingNotifierSetting("from")
+		}
+
+		if n.to, err = settings.GetStringSlice("to", nil); err != nil {
+			return nil, err
+		} else if len(n.to) == 0 {
+			return nil, ErrMissingNotifierSetting("to")
+		}
+
+		if n.subject, err = settings.GetString("subject", smtpDefaultSubject); err != nil {
+			return nil, err
+		}
+
+		if n.body, err = settings.GetString("body", smtpDefaultBody); err != nil {
+			return nil, err
+		}
+
+		for _, s := range []string{n.subject, n.body} {
+			if _, err := template.Parse(s); err != nil {
+				return nil, template.ErrInvalidTemplate
+			}
+		}
+
+		return n, nil
+	}
+}
+
+// Name returns the name of the current notifier.
+func (n *smtpNotifier) Name() string {
+	return n.name
+}
+
+// Notify sends a notification email to the configured recipients.
+func (n *smtpNotifier) Notify(notification *Notification) error {
+	var auth smtp.Auth
+
+	attrs := notification.Attrs()
+
+	subject, err := template.Expand(n.subject, attrs)
+	if err != nil {
+		return err
+	}
+
+	body, err := template.Expand(n.body, attrs)
+	if err != nil {
+		return err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	fmt.Fprintf(buf, "From: %s\r\n", n.from)
+	fmt.Fprintf(buf, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(buf, "Subject: %s\r\n", smtpEncodeSubject(subject))
+	fmt.Fprintf(buf, "Date: %s\r\n", notification.Time.Format("Mon, 02 Jan 2006 15:04:05 -0700"))
+	fmt.Fprintf(buf, "MIME-Version: 1.0\r\n")
+
+	if len(notification.Attachments) == 0 {
+		fmt.Fprintf(buf, "Content-Type: text/plain; charset=utf-8\r\n")
+		fmt.Fprintf(buf, "\r\n%s", strings.Replace(body, "\n", "\r\n", -1))
+	} else if err := smtpWriteMultipart(buf, body, notification.Attachments); err != nil {
+		return err
+	}
+
+	if n.username != "" {
+		auth = smtp.PlainAuth("", n.username, n.password, n.host)
+	}
+
+	if err := smtp.SendMail(n.addr, auth, n.from, n.to, buf.Bytes()); err != nil {
+		return fmt.Errorf("unable to send email: %s", err)
+	}
+
+	return nil
+}
+
+// smtpEncodeSubject returns a subject header value, its line breaks being replaced to prevent header injection and its
+// non-ASCII characters being encoded as per RFC 2047.
+func smtpEncodeSubject(subject string) string {
+	return mime.QEncoding.Encode("utf-8", strings.Join(strings.FieldsFunc(subject, func(r rune) bool {
+		return r == '\r' || r == '\n'
+	}), " "))
+}
+
+func smtpWriteMultipart(buf *bytes.Buffer, body string, attachments []Attachment) error {
+	mw := multipart.NewWriter(buf)
+
+	fmt.Fprintf(buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", mw.Boundary())
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=utf-8"}})
+	if err != nil {
+		return err
+	}
+	part.Write([]byte(strings.Replace(body, "\n", "\r\n", -1)))
+
+	for _, a := range attachments {
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.ContentType},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Name})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Wrap base64-encoded data lines as per RFC 2045
+		data := base64.StdEncoding.EncodeToString(a.Data)
+		for len(data) > 76 {
+			part.Write([]byte(data[:76] + "\r\n"))
+			data = data[76:]
+		}
+		part.Write([]byte(data + "\r\n"))
+	}
+
+	return mw.Close()
+}
@@ -0,0 +1,144 @@
+// +build !disable_notifier_webhook
+
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"facette/template"
+
+	"github.com/facette/httputil"
+	"github.com/facette/logger"
+	"github.com/facette/maputil"
+)
+
+// webhookNotifier implements the notifier handler for generic HTTP webhooks.
+type webhookNotifier struct {
+	name        string
+	url         string
+	method      string
+	contentType string
+	body        string
+	headers     map[string]string
+	client      *http.Client
+}
+
+func init() {
+	notifiers["webhook"] = func(name string, settings *maputil.Map, log *logger.Logger) (Notifier, error) {
+		var err error
+
+		n := &webhookNotifier{
+			name:    name,
+			headers: make(map[string]string),
+		}
+
+		// Load channel configuration
+		if n.url, err = settings.GetString("url", ""); err != nil {
+			return nil, err
+		} else if n.url == "" {
+			return nil, ErrMissingNotifierSetting("url")
+		}
+
+		if _, err := url.Parse(n.url); err != nil {
+			return nil, fmt.Errorf("unable to parse URL: %s", err)
+		}
+
+		if n.method, err = settings.GetString("method", "POST"); err != nil {
+			return nil, err
+		}
+		n.method = strings.ToUpper(n.method)
+
+		if n.contentType, err = settings.GetString("content_type", "application/json"); err != nil {
+			return nil, err
+		}
+
+		// Body template defaults to the JSON-encoded notification attributes if none provided
+		if n.body, err = settings.GetString("body", ""); err != nil {
+			return nil, err
+		} else if _, err := template.Parse(n.body); err != nil {
+			return nil, template.ErrInvalidTemplate
+		}
+
+		headers, err := settings.GetMap("headers", nil)
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range headers {
+			n.headers[k] = fmt.Sprintf("%v", v)
+		}
+
+		timeout, err := getInt(settings, "timeout", notifierDefaultTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		allowInsecure, err := settings.GetBool("allow_insecure_tls", false)
+		if err != nil {
+			return nil, err
+		}
+
+		// Create new HTTP client
+		n.client = httputil.NewClient(time.Duration(timeout)*time.Second, true, allowInsecure)
+
+		return n, nil
+	}
+}
+
+// Name returns the name of the current notifier.
+func (n *webhookNotifier) Name() string {
+	return n.name
+}
+
+// Notify sends a notification to the webhook URL.
+func (n *webhookNotifier) Notify(notification *Notification) error {
+	var (
+		body []byte
+		err  error
+	)
+
+	attrs := notification.Attrs()
+
+	if n.body != "" {
+		var data string
+		if data, err = template.Expand(n.body, attrs); err != nil {
+			return err
+		}
+
+		body = []byte(data)
+	} else if body, err = json.Marshal(attrs); err != nil {
+		return fmt.Errorf("unable to marshal JSON data: %s", err)
+	}
+
+	req, err := http.NewRequest(n.method, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to set up HTTP request: %s", err)
+	}
+
+	req.Header.Add("User-Agent", "facette/"+version)
+	req.Header.Set("Content-Type", n.contentType)
+
+	for k, v := range n.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to perform HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected HTTP response status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	return nil
+}
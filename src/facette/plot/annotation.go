@@ -0,0 +1,21 @@
+package plot
+
+import "time"
+
+// Annotation represents a plot annotation instance, either stored in the back-end or retrieved from a connector.
+type Annotation struct {
+	ID     string     `json:"id,omitempty"`
+	Source string     `json:"source,omitempty"`
+	Start  time.Time  `json:"start"`
+	End    *time.Time `json:"end,omitempty"`
+	Text   string     `json:"text"`
+	Tags   []string   `json:"tags,omitempty"`
+}
+
+// AnnotationQuery represents a connector annotations query instance.
+type AnnotationQuery struct {
+	StartTime time.Time
+	EndTime   time.Time
+	Query     string
+	Tags      []string
+}
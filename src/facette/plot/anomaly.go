@@ -0,0 +1,114 @@
+package plot
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	_ = iota
+	// AnomalyStddev represents a mean and standard deviation based anomaly detection type.
+	AnomalyStddev
+	// AnomalyMAD represents a median absolute deviation based anomaly detection type.
+	AnomalyMAD
+)
+
+const (
+	// DefaultAnomalyWindow represents the default anomaly detection trailing window.
+	DefaultAnomalyWindow = time.Hour
+	// DefaultAnomalyFactor represents the default anomaly detection deviation factor.
+	DefaultAnomalyFactor = 3.0
+
+	// madScale is the constant scaling the median absolute deviation into a standard deviation estimate for
+	// normally distributed data.
+	madScale = 1.4826
+)
+
+// AnomalyBands represents a set of anomaly detection band series.
+type AnomalyBands struct {
+	Lower     Series
+	Upper     Series
+	Anomalies Series
+}
+
+// Anomalies computes a rolling baseline and deviation band over a trailing time window for a series, the deviation
+// being multiplied by the given factor. Plots lying outside of the band are reported in the anomalies series, other
+// plots being set to NaN.
+func Anomalies(series Series, method int, window time.Duration, factor float64) (AnomalyBands, error) {
+	if method != AnomalyStddev && method != AnomalyMAD {
+		return AnomalyBands{}, ErrInvalidAnomalyMethod
+	}
+
+	step := series.interval()
+	if step <= 0 {
+		return AnomalyBands{}, ErrNotEnoughPlots
+	}
+
+	size := int(window / step)
+	if size < 2 {
+		return AnomalyBands{}, ErrInvalidWindow
+	}
+
+	length := len(series.Plots)
+
+	result := AnomalyBands{
+		Lower:     Series{Step: series.Step, Plots: make([]Plot, length), Summary: make(map[string]Value)},
+		Upper:     Series{Step: series.Step, Plots: make([]Plot, length), Summary: make(map[string]Value)},
+		Anomalies: Series{Step: series.Step, Plots: make([]Plot, length), Summary: make(map[string]Value)},
+	}
+
+	set := make([]float64, 0, size)
+
+	for i, p := range series.Plots {
+		lower, upper := Value(math.NaN()), Value(math.NaN())
+
+		// Gather trailing window valid values, excluding the current plot
+		set = set[:0]
+		for j := i - size; j < i; j++ {
+			if j >= 0 && !series.Plots[j].Value.IsNaN() {
+				set = append(set, float64(series.Plots[j].Value))
+			}
+		}
+
+		if len(set) >= 2 {
+			var baseline, deviation float64
+
+			if method == AnomalyStddev {
+				baseline, deviation = mean(set), stddevValue(set)
+			} else {
+				baseline, deviation = medianAbsoluteDeviation(set)
+				deviation *= madScale
+			}
+
+			lower = Value(baseline - factor*deviation)
+			upper = Value(baseline + factor*deviation)
+		}
+
+		result.Lower.Plots[i] = Plot{Time: p.Time, Value: lower}
+		result.Upper.Plots[i] = Plot{Time: p.Time, Value: upper}
+		result.Anomalies.Plots[i] = Plot{Time: p.Time, Value: Value(math.NaN())}
+
+		if !p.Value.IsNaN() && !lower.IsNaN() && (p.Value < lower || p.Value > upper) {
+			result.Anomalies.Plots[i].Value = p.Value
+		}
+	}
+
+	return result, nil
+}
+
+// medianAbsoluteDeviation returns both the median and the median absolute deviation of a set of values. The set
+// is sorted in place.
+func medianAbsoluteDeviation(set []float64) (float64, float64) {
+	sort.Float64s(set)
+	median := percentileValue(set, 50)
+
+	deviations := make([]float64, len(set))
+	for i, v := range set {
+		deviations[i] = math.Abs(v - median)
+	}
+
+	sort.Float64s(deviations)
+
+	return median, percentileValue(deviations, 50)
+}
@@ -0,0 +1,75 @@
+package plot
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func Test_Anomalies_Stddev(t *testing.T) {
+	series := Series{Step: 60, Plots: []Plot{
+		{Time: time.Unix(0, 0), Value: 10}, {Time: time.Unix(60, 0), Value: 12},
+		{Time: time.Unix(120, 0), Value: 10}, {Time: time.Unix(180, 0), Value: 12},
+		{Time: time.Unix(240, 0), Value: 50}, {Time: time.Unix(300, 0), Value: 11},
+	}}
+
+	bands, err := Anomalies(series, AnomalyStddev, 4*time.Minute, 2)
+	if err != nil {
+		t.Log(err)
+		t.Fail()
+		return
+	}
+
+	nan := Value(math.NaN())
+
+	checks := []struct {
+		series   Series
+		expected []Value
+	}{
+		{bands.Lower, []Value{nan, nan, 9, 10 - Value(2*math.Sqrt(8.0/9)) + Value(2.0/3), 9}},
+		{bands.Upper, []Value{nan, nan, 13, 10 + Value(2*math.Sqrt(8.0/9)) + Value(2.0/3), 13}},
+		{bands.Anomalies, []Value{nan, nan, nan, nan, 50, nan}},
+	}
+
+	for _, c := range checks {
+		for i, v := range c.expected {
+			if c.series.Plots[i].Value.IsNaN() != v.IsNaN() ||
+				!v.IsNaN() && math.Abs(float64(c.series.Plots[i].Value-v)) > 1e-9 {
+				t.Logf("\nExpected %g\nbut got  %g", v, c.series.Plots[i].Value)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func Test_Anomalies_MAD(t *testing.T) {
+	series := Series{Step: 60, Plots: []Plot{
+		{Time: time.Unix(0, 0), Value: 10}, {Time: time.Unix(60, 0), Value: 12},
+		{Time: time.Unix(120, 0), Value: 11}, {Time: time.Unix(180, 0), Value: 50},
+		{Time: time.Unix(240, 0), Value: 12}, {Time: time.Unix(300, 0), Value: 10},
+	}}
+
+	bands, err := Anomalies(series, AnomalyMAD, 3*time.Minute, 3)
+	if err != nil {
+		t.Log(err)
+		t.Fail()
+		return
+	}
+
+	expected := []Value{Value(math.NaN()), Value(math.NaN()), Value(math.NaN()), 50, Value(math.NaN()),
+		Value(math.NaN())}
+
+	for i, v := range expected {
+		if bands.Anomalies.Plots[i].Value.IsNaN() != v.IsNaN() || !v.IsNaN() && bands.Anomalies.Plots[i].Value != v {
+			t.Logf("\nExpected %g\nbut got  %g", v, bands.Anomalies.Plots[i].Value)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Anomalies_InvalidWindow(t *testing.T) {
+	if _, err := Anomalies(testSeriesNormalize[0], AnomalyStddev, time.Second, 3); err != ErrInvalidWindow {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrInvalidWindow, err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,140 @@
+package plot
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	_ = iota
+	// DownsampleBucket represents a fixed time buckets downsampling type.
+	DownsampleBucket
+	// DownsampleLTTB represents a Largest-Triangle-Three-Buckets downsampling type.
+	DownsampleLTTB
+)
+
+// LTTB downsamples a series using the Largest-Triangle-Three-Buckets algorithm. Unlike normalization, it keeps the
+// plots original timestamps and thus is only suitable when no alignment among multiple series is needed.
+func LTTB(series Series, startTime, endTime time.Time, sample int) (Series, error) {
+	if sample <= 0 {
+		return Series{}, ErrInvalidSample
+	}
+
+	// Discard series plots out of time specs range
+	plots := make([]Plot, 0, len(series.Plots))
+	for _, p := range series.Plots {
+		if p.Time.Before(startTime) || p.Time.After(endTime) {
+			continue
+		}
+
+		plots = append(plots, p)
+	}
+
+	length := len(plots)
+
+	result := Series{
+		Step:    series.Step,
+		Summary: make(map[string]Value),
+	}
+
+	// Return plots untouched if there is nothing to downsample
+	if length <= sample || length < 3 {
+		result.Plots = plots
+		return result, nil
+	} else if sample < 3 {
+		result.Plots = []Plot{plots[0], plots[length-1]}[:sample]
+		return result, nil
+	}
+
+	result.Plots = make([]Plot, 0, sample)
+	result.Step = int(endTime.Sub(startTime).Seconds()) / sample
+
+	// Always keep the first plot, then select the plot forming the largest triangle with the previously selected
+	// one and the average of the next bucket. The first and last plots being excluded, the remaining ones are
+	// dispatched into (sample - 2) buckets.
+	result.Plots = append(result.Plots, plots[0])
+
+	size := float64(length-2) / float64(sample-2)
+	a := plots[0]
+
+	for i := 0; i < sample-2; i++ {
+		start := int(float64(i)*size) + 1
+		end := int(float64(i+1)*size) + 1
+		if i == sample-3 {
+			end = length - 1
+		}
+
+		// Calculate next bucket average point
+		nextStart := end
+		nextEnd := int(float64(i+2)*size) + 1
+		if nextEnd > length {
+			nextEnd = length
+		}
+
+		c := averagePlot(plots[nextStart:nextEnd])
+
+		// Select plot with the largest triangle area, keeping the first one if the bucket only contains missing
+		// values to preserve the gap
+		selected := plots[start]
+		maxArea := -1.0
+
+		for _, b := range plots[start:end] {
+			if b.Value.IsNaN() {
+				continue
+			}
+
+			if area := triangleArea(a, b, c); area > maxArea {
+				selected = b
+				maxArea = area
+			}
+		}
+
+		result.Plots = append(result.Plots, selected)
+		a = selected
+	}
+
+	result.Plots = append(result.Plots, plots[length-1])
+
+	return result, nil
+}
+
+func averagePlot(plots []Plot) Plot {
+	var (
+		sumTime, sumValue float64
+		count             int
+	)
+
+	for _, p := range plots {
+		if p.Value.IsNaN() {
+			continue
+		}
+
+		sumTime += float64(p.Time.UnixNano())
+		sumValue += float64(p.Value)
+		count++
+	}
+
+	if count == 0 {
+		return Plot{Value: Value(math.NaN())}
+	}
+
+	return Plot{
+		Time:  time.Unix(0, int64(sumTime/float64(count))),
+		Value: Value(sumValue / float64(count)),
+	}
+}
+
+func triangleArea(a, b, c Plot) float64 {
+	// Fallback to values distance if one of the surrounding points is missing
+	if a.Value.IsNaN() && c.Value.IsNaN() {
+		return 0
+	} else if a.Value.IsNaN() {
+		return math.Abs(float64(b.Value - c.Value))
+	} else if c.Value.IsNaN() {
+		return math.Abs(float64(b.Value - a.Value))
+	}
+
+	ax, bx, cx := a.Time.Unix(), b.Time.Unix(), c.Time.Unix()
+
+	return math.Abs(float64(ax-cx)*float64(b.Value-a.Value)-float64(ax-bx)*float64(c.Value-a.Value)) / 2
+}
@@ -0,0 +1,58 @@
+package plot
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func Test_LTTB(t *testing.T) {
+	series := Series{Step: 10, Plots: []Plot{
+		{Time: time.Unix(0, 0), Value: 1}, {Time: time.Unix(10, 0), Value: 2},
+		{Time: time.Unix(20, 0), Value: 1}, {Time: time.Unix(30, 0), Value: 95},
+		{Time: time.Unix(40, 0), Value: 2}, {Time: time.Unix(50, 0), Value: 1},
+		{Time: time.Unix(60, 0), Value: Value(math.NaN())}, {Time: time.Unix(70, 0), Value: Value(math.NaN())},
+		{Time: time.Unix(80, 0), Value: Value(math.NaN())}, {Time: time.Unix(90, 0), Value: 2},
+	}}
+
+	expected := Series{Plots: []Plot{
+		{Time: time.Unix(0, 0), Value: 1},
+		{Time: time.Unix(20, 0), Value: 1},
+		{Time: time.Unix(30, 0), Value: 95},
+		{Time: time.Unix(60, 0), Value: Value(math.NaN())},
+		{Time: time.Unix(90, 0), Value: 2},
+	}}
+
+	startTime := time.Unix(0, 0)
+
+	result, err := LTTB(series, startTime, startTime.Add(100*time.Second), 5)
+	if err != nil {
+		t.Log(err)
+		t.Fail()
+	} else if !compareSeries(result, expected) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
+		t.Fail()
+	}
+}
+
+func Test_LTTB_Untouched(t *testing.T) {
+	startTime := time.Unix(0, 0)
+
+	result, err := LTTB(testSeriesNormalize[2], startTime, startTime.Add(300*time.Second), 10)
+	if err != nil {
+		t.Log(err)
+		t.Fail()
+	} else if !compareSeries(result, testSeriesNormalize[2]) {
+		t.Logf("\nExpected %#v\nbut got  %#v", testSeriesNormalize[2], result)
+		t.Fail()
+	}
+}
+
+func Test_LTTB_InvalidSample(t *testing.T) {
+	startTime := time.Unix(0, 0)
+
+	if _, err := LTTB(testSeriesNormalize[0], startTime, startTime.Add(300*time.Second), 0); err != ErrInvalidSample {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrInvalidSample, err)
+		t.Fail()
+	}
+}
@@ -3,10 +3,22 @@ package plot
 import "errors"
 
 var (
+	// ErrInvalidAnomalyMethod represents an invalid anomaly detection method error.
+	ErrInvalidAnomalyMethod = errors.New("invalid anomaly detection method")
+	// ErrInvalidBounds represents an invalid histogram bounds error.
+	ErrInvalidBounds = errors.New("invalid histogram bounds")
+	// ErrInvalidPercentile represents an invalid percentile value error.
+	ErrInvalidPercentile = errors.New("invalid percentile value")
+	// ErrInvalidSmoothingFactor represents an invalid smoothing factor value error.
+	ErrInvalidSmoothingFactor = errors.New("invalid smoothing factor value")
 	// ErrInvalidSample represents an invalid series sample value error.
 	ErrInvalidSample = errors.New("invalid sample value")
+	// ErrInvalidWindow represents an invalid window value error.
+	ErrInvalidWindow = errors.New("invalid window value")
 	// ErrEmptySeries represents a empty series list error.
 	ErrEmptySeries = errors.New("no series provided")
+	// ErrNotEnoughPlots represents a not enough plots error.
+	ErrNotEnoughPlots = errors.New("not enough plots")
 	// ErrUnnormalizedSeries represents an unnormalized series list error.
 	ErrUnnormalizedSeries = errors.New("unnormalized series")
 )
@@ -0,0 +1,85 @@
+package plot
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type exportLine struct {
+	Series string `json:"series"`
+	Time   string `json:"time"`
+	Value  Value  `json:"value"`
+}
+
+// WriteCSV writes the response series as delimiter-separated values, with a first column holding the RFC 3339
+// timestamps and one column per series. Missing values are left empty.
+func (r *Response) WriteCSV(w io.Writer, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+
+	// Gather all series timestamps, as series might not be aligned
+	index := make(map[int64]int)
+	times := []time.Time{}
+
+	for _, s := range r.Series {
+		for _, p := range s.Plots {
+			if _, ok := index[p.Time.UnixNano()]; !ok {
+				index[p.Time.UnixNano()] = 0
+				times = append(times, p.Time)
+			}
+		}
+	}
+
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	for i, t := range times {
+		index[t.UnixNano()] = i
+	}
+
+	rows := make([][]string, len(times))
+	for i, t := range times {
+		rows[i] = make([]string, len(r.Series)+1)
+		rows[i][0] = t.UTC().Format(time.RFC3339)
+	}
+
+	header := make([]string, len(r.Series)+1)
+	header[0] = "time"
+
+	for i, s := range r.Series {
+		header[i+1] = s.Name
+
+		for _, p := range s.Plots {
+			if !p.Value.IsNaN() {
+				rows[index[p.Time.UnixNano()]][i+1] = strconv.FormatFloat(float64(p.Value), 'f', -1, 64)
+			}
+		}
+	}
+
+	if err := cw.Write(header); err != nil {
+		return err
+	} else if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// WriteJSONLines writes the response series as JSON lines, each line holding a single series plot. Missing values
+// are set to null.
+func (r *Response) WriteJSONLines(w io.Writer) error {
+	enc := json.NewEncoder(w)
+
+	for _, s := range r.Series {
+		for _, p := range s.Plots {
+			line := exportLine{Series: s.Name, Time: p.Time.UTC().Format(time.RFC3339), Value: p.Value}
+			if err := enc.Encode(line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
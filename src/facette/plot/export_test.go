@@ -0,0 +1,63 @@
+package plot
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+func Test_Response_WriteCSV(t *testing.T) {
+	expected := "time\tseries1\tseries2\n" +
+		"1970-01-01T00:00:00Z\t1\t\n" +
+		"1970-01-01T00:00:10Z\t\t2.5\n" +
+		"1970-01-01T00:00:20Z\t3\t4\n"
+
+	buf := bytes.NewBuffer(nil)
+	if err := testExportResponse().WriteCSV(buf, '\t'); err != nil {
+		t.Log(err)
+		t.Fail()
+	} else if result := buf.String(); result != expected {
+		t.Logf("\nExpected %q\nbut got  %q", expected, result)
+		t.Fail()
+	}
+}
+
+func Test_Response_WriteJSONLines(t *testing.T) {
+	expected := `{"series":"series1","time":"1970-01-01T00:00:00Z","value":1}` + "\n" +
+		`{"series":"series1","time":"1970-01-01T00:00:10Z","value":null}` + "\n" +
+		`{"series":"series1","time":"1970-01-01T00:00:20Z","value":3}` + "\n" +
+		`{"series":"series2","time":"1970-01-01T00:00:10Z","value":2.5}` + "\n" +
+		`{"series":"series2","time":"1970-01-01T00:00:20Z","value":4}` + "\n"
+
+	buf := bytes.NewBuffer(nil)
+	if err := testExportResponse().WriteJSONLines(buf); err != nil {
+		t.Log(err)
+		t.Fail()
+	} else if result := buf.String(); result != expected {
+		t.Logf("\nExpected %q\nbut got  %q", expected, result)
+		t.Fail()
+	}
+}
+
+func testExportResponse() *Response {
+	return &Response{
+		Series: []SeriesResponse{
+			{
+				Name: "series1",
+				Series: Series{Plots: []Plot{
+					{Time: time.Unix(0, 0), Value: 1},
+					{Time: time.Unix(10, 0), Value: Value(math.NaN())},
+					{Time: time.Unix(20, 0), Value: 3},
+				}},
+			},
+			{
+				Name: "series2",
+				Series: Series{Plots: []Plot{
+					{Time: time.Unix(10, 0), Value: 2.5},
+					{Time: time.Unix(20, 0), Value: 4},
+				}},
+			},
+		},
+	}
+}
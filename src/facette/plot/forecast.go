@@ -0,0 +1,231 @@
+package plot
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// DefaultHoltWintersAlpha represents the default Holt-Winters level smoothing factor.
+	DefaultHoltWintersAlpha = 0.5
+	// DefaultHoltWintersBeta represents the default Holt-Winters trend smoothing factor.
+	DefaultHoltWintersBeta = 0.1
+	// DefaultHoltWintersGamma represents the default Holt-Winters seasonal smoothing factor.
+	DefaultHoltWintersGamma = 0.1
+)
+
+// LinearTrend returns a new series fitting the series plots using a least squares linear regression, extrapolated
+// up to the given time.
+func LinearTrend(series Series, until time.Time) (Series, error) {
+	var sumX, sumY, sumXY, sumXX, count float64
+
+	step := series.interval()
+	if step <= 0 || len(series.Plots) == 0 {
+		return Series{}, ErrNotEnoughPlots
+	}
+
+	// Use time offsets relative to the first plot to prevent floating point precision loss
+	origin := series.Plots[0].Time
+
+	for _, p := range series.Plots {
+		if p.Value.IsNaN() {
+			continue
+		}
+
+		x := p.Time.Sub(origin).Seconds()
+		y := float64(p.Value)
+
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+		count++
+	}
+
+	if count < 2 || count*sumXX-sumX*sumX == 0 {
+		return Series{}, ErrNotEnoughPlots
+	}
+
+	slope := (count*sumXY - sumX*sumY) / (count*sumXX - sumX*sumX)
+	intercept := (sumY - slope*sumX) / count
+
+	result := Series{
+		Step:    int(step.Seconds()),
+		Plots:   make([]Plot, 0, len(series.Plots)),
+		Summary: make(map[string]Value),
+	}
+
+	for _, t := range series.times(step, until) {
+		result.Plots = append(result.Plots, Plot{
+			Time:  t,
+			Value: Value(intercept + slope*t.Sub(origin).Seconds()),
+		})
+	}
+
+	return result, nil
+}
+
+// HoltWinters returns a new series forecasting the series plots using the additive Holt-Winters triple exponential
+// smoothing, extrapolated up to the given time. If the season duration is zero or the series doesn't contain at
+// least two seasons of plots, the seasonal component is ignored (double exponential smoothing).
+func HoltWinters(series Series, until time.Time, alpha, beta, gamma float64, season time.Duration) (Series, error) {
+	for _, factor := range []float64{alpha, beta, gamma} {
+		if factor < 0 || factor > 1 {
+			return Series{}, ErrInvalidSmoothingFactor
+		}
+	}
+
+	step := series.interval()
+	if step <= 0 {
+		return Series{}, ErrNotEnoughPlots
+	}
+
+	// Skip leading missing plots
+	first := 0
+	for first < len(series.Plots) && series.Plots[first].Value.IsNaN() {
+		first++
+	}
+
+	values := make([]float64, len(series.Plots)-first)
+	for i, p := range series.Plots[first:] {
+		values[i] = float64(p.Value)
+	}
+
+	length := len(values)
+	if length < 2 {
+		return Series{}, ErrNotEnoughPlots
+	}
+
+	// Initialize level, trend and seasonal components
+	period := int(season / step)
+	if period <= 1 || length < 2*period || hasNaN(values[:2*period]) {
+		period = 0
+	}
+
+	level := values[0]
+	trend := 0.0
+	if !math.IsNaN(values[1]) {
+		trend = values[1] - values[0]
+	}
+
+	seasonals := []float64{0}
+
+	if period > 0 {
+		firstMean, secondMean := mean(values[:period]), mean(values[period:2*period])
+
+		level = firstMean
+		trend = (secondMean - firstMean) / float64(period)
+		seasonals = make([]float64, period)
+
+		for i := range seasonals {
+			seasonals[i] = values[i] - firstMean
+		}
+	}
+
+	seasonal := func(i int) *float64 {
+		if period == 0 {
+			return &seasonals[0]
+		}
+
+		return &seasonals[i%period]
+	}
+
+	times := series.times(step, until)
+
+	result := Series{
+		Step:    int(step.Seconds()),
+		Plots:   make([]Plot, len(times)),
+		Summary: make(map[string]Value),
+	}
+
+	for i := 0; i < first; i++ {
+		result.Plots[i] = Plot{Time: times[i], Value: Value(math.NaN())}
+	}
+
+	// Smooth known plots, using the one-step-ahead forecast as value for the missing ones (the first plot being
+	// used for components initialization)
+	for i, v := range values {
+		s := seasonal(i)
+
+		if i == 0 {
+			result.Plots[first] = Plot{Time: times[first], Value: Value(level + *s)}
+			continue
+		}
+
+		forecast := level + trend + *s
+		result.Plots[first+i] = Plot{Time: times[first+i], Value: Value(forecast)}
+
+		if math.IsNaN(v) {
+			v = forecast
+		}
+
+		prevLevel := level
+		level = alpha*(v-*s) + (1-alpha)*(level+trend)
+		trend = beta*(level-prevLevel) + (1-beta)*trend
+
+		if period > 0 {
+			*s = gamma*(v-level) + (1-gamma)*(*s)
+		}
+	}
+
+	// Extrapolate beyond the last known plot
+	for h := 1; first+length+h-1 < len(times); h++ {
+		result.Plots[first+length+h-1] = Plot{
+			Time:  times[first+length+h-1],
+			Value: Value(level + float64(h)*trend + *seasonal(length + h - 1)),
+		}
+	}
+
+	return result, nil
+}
+
+// interval returns the series plots interval, either from its step or the average delta between its plots.
+func (s Series) interval() time.Duration {
+	if s.Step > 0 {
+		return time.Duration(s.Step) * time.Second
+	}
+
+	length := len(s.Plots)
+	if length < 2 {
+		return 0
+	}
+
+	return s.Plots[length-1].Time.Sub(s.Plots[0].Time) / time.Duration(length-1)
+}
+
+// times returns the series plots times followed by the extrapolated ones up to the given time.
+func (s Series) times(step time.Duration, until time.Time) []time.Time {
+	times := make([]time.Time, 0, len(s.Plots))
+	for _, p := range s.Plots {
+		times = append(times, p.Time)
+	}
+
+	if len(times) == 0 {
+		return times
+	}
+
+	for t := times[len(times)-1].Add(step); !t.After(until); t = t.Add(step) {
+		times = append(times, t)
+	}
+
+	return times
+}
+
+func hasNaN(values []float64) bool {
+	for _, v := range values {
+		if math.IsNaN(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum / float64(len(values))
+}
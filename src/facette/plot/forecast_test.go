@@ -0,0 +1,112 @@
+package plot
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func Test_LinearTrend(t *testing.T) {
+	series := Series{Step: 10, Plots: []Plot{
+		{Time: time.Unix(0, 0), Value: 1}, {Time: time.Unix(10, 0), Value: 21},
+		{Time: time.Unix(20, 0), Value: Value(math.NaN())}, {Time: time.Unix(30, 0), Value: 61},
+	}}
+
+	expected := Series{Step: 10, Plots: []Plot{
+		{Time: time.Unix(0, 0), Value: 1}, {Time: time.Unix(10, 0), Value: 21},
+		{Time: time.Unix(20, 0), Value: 41}, {Time: time.Unix(30, 0), Value: 61},
+		{Time: time.Unix(40, 0), Value: 81}, {Time: time.Unix(50, 0), Value: 101},
+	}}
+
+	result, err := LinearTrend(series, time.Unix(55, 0))
+	if err != nil {
+		t.Log(err)
+		t.Fail()
+	} else if !compareSeriesDelta(result, expected, 1e-9) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
+		t.Fail()
+	}
+}
+
+func Test_LinearTrend_NotEnoughPlots(t *testing.T) {
+	series := Series{Step: 10, Plots: []Plot{{Time: time.Unix(0, 0), Value: 1}}}
+
+	if _, err := LinearTrend(series, time.Unix(60, 0)); err != ErrNotEnoughPlots {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrNotEnoughPlots, err)
+		t.Fail()
+	}
+}
+
+func Test_HoltWinters(t *testing.T) {
+	series := Series{Step: 10, Plots: []Plot{
+		{Time: time.Unix(0, 0), Value: Value(math.NaN())}, {Time: time.Unix(10, 0), Value: 10},
+		{Time: time.Unix(20, 0), Value: 12}, {Time: time.Unix(30, 0), Value: 14},
+		{Time: time.Unix(40, 0), Value: 16}, {Time: time.Unix(50, 0), Value: 18},
+	}}
+
+	expected := Series{Step: 10, Plots: []Plot{
+		{Time: time.Unix(0, 0), Value: Value(math.NaN())}, {Time: time.Unix(10, 0), Value: 10},
+		{Time: time.Unix(20, 0), Value: 12}, {Time: time.Unix(30, 0), Value: 14},
+		{Time: time.Unix(40, 0), Value: 16}, {Time: time.Unix(50, 0), Value: 18},
+		{Time: time.Unix(60, 0), Value: 20}, {Time: time.Unix(70, 0), Value: 22},
+	}}
+
+	result, err := HoltWinters(series, time.Unix(70, 0), DefaultHoltWintersAlpha, DefaultHoltWintersBeta,
+		DefaultHoltWintersGamma, 0)
+	if err != nil {
+		t.Log(err)
+		t.Fail()
+	} else if !compareSeriesDelta(result, expected, 1e-9) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
+		t.Fail()
+	}
+}
+
+func Test_HoltWinters_Seasonal(t *testing.T) {
+	pattern := []Value{1, 5, 3}
+
+	series := Series{Step: 60, Plots: make([]Plot, 12)}
+	for i := range series.Plots {
+		series.Plots[i] = Plot{Time: time.Unix(int64(i*60), 0), Value: pattern[i%3]}
+	}
+
+	result, err := HoltWinters(series, time.Unix(14*60, 0), DefaultHoltWintersAlpha, DefaultHoltWintersBeta,
+		DefaultHoltWintersGamma, 3*time.Minute)
+	if err != nil {
+		t.Log(err)
+		t.Fail()
+		return
+	}
+
+	for i, p := range result.Plots {
+		if math.Abs(float64(p.Value-pattern[i%3])) > 1e-9 {
+			t.Logf("\nExpected %g\nbut got  %g", pattern[i%3], p.Value)
+			t.Fail()
+		}
+	}
+}
+
+func Test_HoltWinters_InvalidFactor(t *testing.T) {
+	if _, err := HoltWinters(testSeriesNormalize[0], time.Unix(300, 0), 1.5, 0, 0,
+		0); err != ErrInvalidSmoothingFactor {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrInvalidSmoothingFactor, err)
+		t.Fail()
+	}
+}
+
+func compareSeriesDelta(actual, expected Series, delta float64) bool {
+	if len(actual.Plots) != len(expected.Plots) {
+		return false
+	}
+
+	for i := range expected.Plots {
+		if actual.Plots[i].Value.IsNaN() != expected.Plots[i].Value.IsNaN() ||
+			!actual.Plots[i].Value.IsNaN() &&
+				math.Abs(float64(actual.Plots[i].Value-expected.Plots[i].Value)) > delta ||
+			!actual.Plots[i].Time.Equal(expected.Plots[i].Time) {
+			return false
+		}
+	}
+
+	return true
+}
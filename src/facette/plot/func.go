@@ -2,6 +2,7 @@ package plot
 
 import (
 	"math"
+	"sort"
 	"time"
 )
 
@@ -19,6 +20,21 @@ const (
 	ConsolidateMin
 	// ConsolidateSum represents a sum consolidation type.
 	ConsolidateSum
+	// ConsolidateMedian represents a median value consolidation type.
+	ConsolidateMedian
+	// ConsolidatePercentile represents a percentile value consolidation type.
+	ConsolidatePercentile
+	// ConsolidateCount represents a valid values count consolidation type.
+	ConsolidateCount
+	// ConsolidateStddev represents a standard deviation consolidation type.
+	ConsolidateStddev
+	// ConsolidateEnvelope represents a min/max envelope consolidation type.
+	ConsolidateEnvelope
+)
+
+const (
+	// DefaultPercentile represents the default percentile consolidation value.
+	DefaultPercentile = 95.0
 )
 
 const (
@@ -35,10 +51,12 @@ const (
 type bucket struct {
 	startTime time.Time
 	plots     []Plot
+	scratch   []float64
 }
 
-// Consolidate consolidates plots buckets based on consolidation function.
-func (b bucket) Consolidate(consolidation int) Plot {
+// Consolidate consolidates plots buckets based on consolidation function. The percentile value is only used along
+// with the percentile consolidation type.
+func (b bucket) Consolidate(consolidation int, percentile float64) Plot {
 	plot := Plot{
 		Value: Value(math.NaN()),
 		Time:  b.startTime,
@@ -66,12 +84,7 @@ func (b bucket) Consolidate(consolidation int) Plot {
 			plot.Value = Value(sum / float64(sumCount))
 		}
 
-		if length == 1 {
-			plot.Time = b.plots[0].Time
-		} else {
-			// Interpolate median time
-			plot.Time = b.plots[0].Time.Add(b.plots[length-1].Time.Sub(b.plots[0].Time) / 2)
-		}
+		plot.Time = b.medianTime()
 
 	case ConsolidateSum:
 		sum := 0.0
@@ -110,17 +123,90 @@ func (b bucket) Consolidate(consolidation int) Plot {
 				plot = p
 			}
 		}
+
+	case ConsolidateMedian, ConsolidatePercentile:
+		if consolidation == ConsolidateMedian {
+			percentile = 50
+		}
+
+		if set := b.values(); len(set) > 0 {
+			sort.Float64s(set)
+			plot.Value = Value(percentileValue(set, percentile))
+		}
+
+		plot.Time = b.medianTime()
+
+	case ConsolidateCount:
+		plot.Value = Value(len(b.values()))
+		plot.Time = b.plots[length-1].Time
+
+	case ConsolidateStddev:
+		if set := b.values(); len(set) > 0 {
+			plot.Value = Value(stddevValue(set))
+		}
+
+		plot.Time = b.medianTime()
 	}
 
 	return plot
 }
 
-// Normalize aligns multiple plot series on a common time step, consolidates plots samples if necessary.
-func Normalize(series []Series, startTime, endTime time.Time, sample int, consolidation int,
+// Envelope returns both the lower and upper bounds of the plots bucket, set at the bucket start time.
+func (b bucket) Envelope() (Plot, Plot) {
+	lower := Plot{Value: Value(math.NaN()), Time: b.startTime}
+	upper := Plot{Value: Value(math.NaN()), Time: b.startTime}
+
+	for _, p := range b.plots {
+		if p.Value.IsNaN() {
+			continue
+		}
+
+		if p.Value < lower.Value || lower.Value.IsNaN() {
+			lower.Value = p.Value
+		}
+		if p.Value > upper.Value || upper.Value.IsNaN() {
+			upper.Value = p.Value
+		}
+	}
+
+	return lower, upper
+}
+
+func (b bucket) medianTime() time.Time {
+	length := len(b.plots)
+	if length == 1 {
+		return b.plots[0].Time
+	}
+
+	// Interpolate median time
+	return b.plots[0].Time.Add(b.plots[length-1].Time.Sub(b.plots[0].Time) / 2)
+}
+
+func (b bucket) values() []float64 {
+	// Reuse scratch buffer if large enough to prevent allocations
+	set := b.scratch[:0]
+	if cap(set) < len(b.plots) {
+		set = make([]float64, 0, len(b.plots))
+	}
+
+	for _, p := range b.plots {
+		if !p.Value.IsNaN() {
+			set = append(set, float64(p.Value))
+		}
+	}
+
+	return set
+}
+
+// Normalize aligns multiple plot series on a common time step, consolidates plots samples if necessary. When using
+// the envelope consolidation type, two series (lower and upper bounds) are returned for each of the input series.
+func Normalize(series []Series, startTime, endTime time.Time, sample int, consolidation int, percentile float64,
 	interpolate bool) ([]Series, error) {
 
 	if sample <= 0 {
 		return nil, ErrInvalidSample
+	} else if consolidation == ConsolidatePercentile && (percentile <= 0 || percentile > 100) {
+		return nil, ErrInvalidPercentile
 	}
 
 	length := len(series)
@@ -128,8 +214,10 @@ func Normalize(series []Series, startTime, endTime time.Time, sample int, consol
 		return nil, ErrEmptySeries
 	}
 
-	result := make([]Series, length)
-	buckets := make([][]bucket, length)
+	resultLength := length
+	if consolidation == ConsolidateEnvelope {
+		resultLength *= 2
+	}
 
 	// Override sample to max series length if smaller than requested
 	maxLength := 0
@@ -147,90 +235,127 @@ func Normalize(series []Series, startTime, endTime time.Time, sample int, consol
 	// Calculate the common step for all series based on time range and requested sampling
 	step := endTime.Sub(startTime) / time.Duration(sample)
 
-	// Dispatch plots into proper time step buckets and then apply consolidation function
+	// Preallocate result series plots at once, along with a scratch buffer used by consolidation functions needing
+	// to sort bucket values
+	result := make([]Series, resultLength)
+	plots := make([]Plot, resultLength*sample)
+
+	scratch := make([]float64, 0)
+	if consolidation == ConsolidateMedian || consolidation == ConsolidatePercentile ||
+		consolidation == ConsolidateStddev {
+		scratch = make([]float64, 0, maxLength)
+	}
+
+	for i := range result {
+		result[i] = Series{
+			Step:    int(step.Seconds()),
+			Plots:   plots[i*sample : (i+1)*sample : (i+1)*sample],
+			Summary: make(map[string]Value),
+		}
+	}
+
+	startNano := startTime.UnixNano()
+
+	// Stream through series plots dispatching them into time step buckets, as plots are sorted each bucket
+	// is a contiguous sub-slice of the series plots
 	for i, s := range series {
-		buckets[i] = make([]bucket, sample)
+		src := sortPlots(s.Plots)
+
+		// Skip series plots before time specs range
+		k := sort.Search(len(src), func(n int) bool { return src[n].Time.UnixNano() >= startNano })
 
-		// Initialize time steps
 		for j := 0; j < sample; j++ {
-			buckets[i][j] = bucket{
+			b := bucket{
 				startTime: startTime.Add(time.Duration(j) * step),
-				plots:     make([]Plot, 0),
+				scratch:   scratch,
 			}
-		}
 
-		for _, p := range s.Plots {
-			// Discard series plots out of time specs range
-			if p.Time.Before(startTime) || p.Time.After(endTime) {
-				continue
+			limit := b.startTime.Add(step).UnixNano()
+
+			n := k
+			for n < len(src) && src[n].Time.UnixNano() < limit {
+				n++
 			}
 
-			// Stop if index goes beyond the requested sample
-			idx := int64(float64(p.Time.UnixNano()-startTime.UnixNano())/float64(step.Nanoseconds())+1) - 1
-			if idx >= int64(sample) {
+			b.plots = src[k:n]
+			k = n
+
+			// Consolidate plot bucket into lower and upper bounds if needed
+			if consolidation == ConsolidateEnvelope {
+				result[2*i].Plots[j], result[2*i+1].Plots[j] = b.Envelope()
 				continue
 			}
 
-			buckets[i][idx].plots = append(buckets[i][idx].plots, p)
+			result[i].Plots[j] = b.Consolidate(consolidation, percentile)
+
+			// Align consolidated plots timestamps among normalized series lists (no need to align times if only
+			// one series is being normalized)
+			if length > 1 {
+				result[i].Plots[j].Time = b.startTime.Round(time.Second)
+			}
 		}
+	}
 
-		result[i] = Series{
-			Step:    int(step.Seconds()),
-			Plots:   make([]Plot, sample),
-			Summary: make(map[string]Value),
+	// Interpolate missing points
+	if interpolate {
+		for i := range result {
+			interpolatePlots(result[i].Plots)
 		}
+	}
 
-		// Consolidate plot buckets
-		lastKnown := -1
+	return result, nil
+}
 
-		for j := range buckets[i] {
-			result[i].Plots[j] = buckets[i][j].Consolidate(consolidation)
+func interpolatePlots(plots []Plot) {
+	// Keep reference of last known plot and fill the gaps when reaching the next known one
+	lastKnown := -1
 
-			if interpolate {
-				// Keep reference of last and next known plots
-				if lastKnown != -1 {
-					result[i].Plots[j].prev = &result[i].Plots[lastKnown]
-				}
+	for j := range plots {
+		if plots[j].Value.IsNaN() {
+			continue
+		}
 
-				if !result[i].Plots[j].Value.IsNaN() {
-					if lastKnown != -1 {
-						for k := lastKnown; k < j; k++ {
-							result[i].Plots[k].next = &result[i].Plots[j]
-						}
-					}
+		if lastKnown != -1 && j-lastKnown > 1 {
+			prev, next := plots[lastKnown], plots[j]
+			delta := float64(next.Time.UnixNano() - prev.Time.UnixNano())
 
-					lastKnown = j
-				}
+			for k := lastKnown + 1; k < j; k++ {
+				ratio := float64(plots[k].Time.UnixNano()-prev.Time.UnixNano()) / delta
+				plots[k].Value = prev.Value + Value(ratio*float64(next.Value-prev.Value))
 			}
+		}
 
-			// Stop if only one series is being normalized (no need to align times)
-			if length == 1 {
-				continue
-			}
+		lastKnown = j
+	}
+}
 
-			// Align consolidated plots timestamps among normalized series lists
-			result[i].Plots[j].Time = buckets[i][j].startTime.Add(time.Duration(step.Seconds() * float64(j))).
-				Round(time.Second)
-		}
+func sortPlots(plots []Plot) []Plot {
+	for i := 1; i < len(plots); i++ {
+		if plots[i].Time.Before(plots[i-1].Time) {
+			// Work on a sorted copy not to alter source plots
+			sorted := make(plotList, len(plots))
+			copy(sorted, plots)
+			sort.Stable(sorted)
 
-		// Interpolate missing points
-		if !interpolate {
-			continue
+			return sorted
 		}
+	}
 
-		for j, plot := range result[i].Plots {
-			if !plot.Value.IsNaN() || plot.prev == nil || plot.next == nil {
-				continue
-			}
+	return plots
+}
 
-			a := float64(plot.next.Value-plot.prev.Value) / float64(plot.next.Time.UnixNano()-plot.prev.Time.UnixNano())
-			b := float64(plot.prev.Value) - a*float64(plot.Time.UnixNano())
+type plotList []Plot
 
-			result[i].Plots[j].Value = Value(a*float64(plot.next.Time.UnixNano()) + b)
-		}
-	}
+func (l plotList) Len() int {
+	return len(l)
+}
 
-	return result, nil
+func (l plotList) Less(i, j int) bool {
+	return l[i].Time.Before(l[j].Time)
+}
+
+func (l plotList) Swap(i, j int) {
+	l[i], l[j] = l[j], l[i]
 }
 
 // Average returns a new series averaging each datapoints.
@@ -10,6 +10,7 @@ import (
 var (
 	testBucket                      bucket
 	testSeries, testSeriesNormalize []Series
+	testSeriesBenchmark             []Series
 )
 
 func init() {
@@ -69,7 +70,7 @@ func init() {
 
 func Test_Consolidate_Average(t *testing.T) {
 	expected := Plot{Time: time.Unix(60, 0), Value: 11.75}
-	result := testBucket.Consolidate(ConsolidateAverage)
+	result := testBucket.Consolidate(ConsolidateAverage, 0)
 	if !reflect.DeepEqual(result, expected) {
 		t.Logf("\nExpected %#v\nbut got  %#v", result, expected)
 		t.Fail()
@@ -78,7 +79,7 @@ func Test_Consolidate_Average(t *testing.T) {
 
 func Test_Consolidate_Sum(t *testing.T) {
 	expected := Plot{Time: time.Unix(120, 0), Value: 47}
-	result := testBucket.Consolidate(ConsolidateSum)
+	result := testBucket.Consolidate(ConsolidateSum, 0)
 	if !reflect.DeepEqual(result, expected) {
 		t.Logf("\nExpected %#v\nbut got  %#v", result, expected)
 		t.Fail()
@@ -87,7 +88,7 @@ func Test_Consolidate_Sum(t *testing.T) {
 
 func Test_Consolidate_First(t *testing.T) {
 	expected := Plot{Time: time.Unix(0, 0), Value: 17}
-	result := testBucket.Consolidate(ConsolidateFirst)
+	result := testBucket.Consolidate(ConsolidateFirst, 0)
 	if !reflect.DeepEqual(result, expected) {
 		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
 		t.Fail()
@@ -96,7 +97,7 @@ func Test_Consolidate_First(t *testing.T) {
 
 func Test_Consolidate_Last(t *testing.T) {
 	expected := Plot{Time: time.Unix(120, 0), Value: 2}
-	result := testBucket.Consolidate(ConsolidateLast)
+	result := testBucket.Consolidate(ConsolidateLast, 0)
 	if !reflect.DeepEqual(result, expected) {
 		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
 		t.Fail()
@@ -105,7 +106,7 @@ func Test_Consolidate_Last(t *testing.T) {
 
 func Test_Consolidate_Min(t *testing.T) {
 	expected := Plot{Time: time.Unix(120, 0), Value: 2}
-	result := testBucket.Consolidate(ConsolidateMin)
+	result := testBucket.Consolidate(ConsolidateMin, 0)
 	if !reflect.DeepEqual(result, expected) {
 		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
 		t.Fail()
@@ -114,13 +115,96 @@ func Test_Consolidate_Min(t *testing.T) {
 
 func Test_Consolidate_Max(t *testing.T) {
 	expected := Plot{Time: time.Unix(30, 0), Value: 25}
-	result := testBucket.Consolidate(ConsolidateMax)
+	result := testBucket.Consolidate(ConsolidateMax, 0)
 	if !reflect.DeepEqual(result, expected) {
 		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
 		t.Fail()
 	}
 }
 
+func Test_Consolidate_Median(t *testing.T) {
+	expected := Plot{Time: time.Unix(60, 0), Value: 10}
+	result := testBucket.Consolidate(ConsolidateMedian, 0)
+	if !reflect.DeepEqual(result, expected) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
+		t.Fail()
+	}
+}
+
+func Test_Consolidate_Percentile(t *testing.T) {
+	expected := Plot{Time: time.Unix(60, 0), Value: 23}
+	result := testBucket.Consolidate(ConsolidatePercentile, 75)
+	if !reflect.DeepEqual(result, expected) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
+		t.Fail()
+	}
+}
+
+func Test_Consolidate_Count(t *testing.T) {
+	expected := Plot{Time: time.Unix(120, 0), Value: 4}
+	result := testBucket.Consolidate(ConsolidateCount, 0)
+	if !reflect.DeepEqual(result, expected) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
+		t.Fail()
+	}
+}
+
+func Test_Consolidate_Stddev(t *testing.T) {
+	expected := Plot{Time: time.Unix(60, 0), Value: Value(math.Sqrt(93.6875))}
+	result := testBucket.Consolidate(ConsolidateStddev, 0)
+	if !reflect.DeepEqual(result, expected) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
+		t.Fail()
+	}
+}
+
+func Test_Consolidate_Envelope(t *testing.T) {
+	expected := [2]Plot{{Time: time.Unix(0, 0), Value: 2}, {Time: time.Unix(0, 0), Value: 25}}
+	lower, upper := testBucket.Envelope()
+	if result := [2]Plot{lower, upper}; !reflect.DeepEqual(result, expected) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
+		t.Fail()
+	}
+}
+
+func Test_Normalize_Envelope(t *testing.T) {
+	startTime := time.Unix(0, 0)
+
+	series, err := Normalize(testSeriesNormalize[:1], startTime, startTime.Add(300*time.Second), 3,
+		ConsolidateEnvelope, 0, false)
+	if err != nil {
+		t.Log(err)
+		t.Fail()
+		return
+	}
+
+	expected := []Series{
+		{Step: 100, Plots: []Plot{
+			{Time: time.Unix(0, 0), Value: 1},
+			{Time: time.Unix(100, 0), Value: 8},
+			{Time: time.Unix(200, 0), Value: 11},
+		}},
+		{Step: 100, Plots: []Plot{
+			{Time: time.Unix(0, 0), Value: 46},
+			{Time: time.Unix(100, 0), Value: 44},
+			{Time: time.Unix(200, 0), Value: 47},
+		}},
+	}
+
+	if len(series) != len(expected) {
+		t.Logf("\nExpected %d\nbut got  %d", len(expected), len(series))
+		t.Fail()
+		return
+	}
+
+	for i, s := range series {
+		if !compareSeries(s, expected[i]) {
+			t.Logf("\nExpected %#v\nbut got  %#v", expected[i], s)
+			t.Fail()
+		}
+	}
+}
+
 func Test_Normalize_Average(t *testing.T) {
 	testNormalize([]Series{
 		{Step: 30, Plots: []Plot{
@@ -613,6 +697,31 @@ func Test_Normalize_Sum_Interpolate(t *testing.T) {
 	}, ConsolidateSum, true, t)
 }
 
+func Test_Normalize_Unsorted(t *testing.T) {
+	startTime := time.Unix(0, 0)
+
+	unsorted := Series{Step: 60, Plots: make([]Plot, len(testSeriesNormalize[2].Plots))}
+	for i, p := range testSeriesNormalize[2].Plots {
+		unsorted.Plots[len(unsorted.Plots)-i-1] = p
+	}
+
+	expected, _ := Normalize(testSeriesNormalize[2:], startTime, startTime.Add(300*time.Second), 10,
+		ConsolidateAverage, 0, true)
+
+	series, err := Normalize([]Series{unsorted}, startTime, startTime.Add(300*time.Second), 10, ConsolidateAverage,
+		0, true)
+	if err != nil {
+		t.Log(err)
+		t.Fail()
+	} else if !compareSeries(series[0], expected[0]) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected[0], series[0])
+		t.Fail()
+	} else if !unsorted.Plots[0].Time.Equal(time.Unix(242, 0)) {
+		t.Logf("\nSource plots have been altered")
+		t.Fail()
+	}
+}
+
 func Test_Average(t *testing.T) {
 	expected := Series{
 		Step: 10,
@@ -650,7 +759,7 @@ func Test_Sum(t *testing.T) {
 func testNormalize(expected []Series, consolidation int, interpolate bool, t *testing.T) {
 	startTime := time.Unix(0, 0)
 
-	series, err := Normalize(testSeriesNormalize, startTime, startTime.Add(300*time.Second), 10, consolidation,
+	series, err := Normalize(testSeriesNormalize, startTime, startTime.Add(300*time.Second), 10, consolidation, 0,
 		interpolate)
 	if err != nil {
 		t.Log(err)
@@ -667,3 +776,61 @@ func testNormalize(expected []Series, consolidation int, interpolate bool, t *te
 		}
 	}
 }
+
+func Benchmark_Normalize_Average(b *testing.B) {
+	benchmarkNormalize(ConsolidateAverage, false, b)
+}
+
+func Benchmark_Normalize_Average_Interpolate(b *testing.B) {
+	benchmarkNormalize(ConsolidateAverage, true, b)
+}
+
+func Benchmark_Normalize_Max(b *testing.B) {
+	benchmarkNormalize(ConsolidateMax, false, b)
+}
+
+func Benchmark_Normalize_Median(b *testing.B) {
+	benchmarkNormalize(ConsolidateMedian, false, b)
+}
+
+func Benchmark_Normalize_Envelope(b *testing.B) {
+	benchmarkNormalize(ConsolidateEnvelope, false, b)
+}
+
+func benchmarkNormalize(consolidation int, interpolate bool, b *testing.B) {
+	const (
+		seriesCount = 1000
+		plotsCount  = 10000
+	)
+
+	startTime := time.Unix(0, 0)
+	endTime := startTime.Add(plotsCount * 10 * time.Second)
+
+	if testSeriesBenchmark == nil {
+		testSeriesBenchmark = make([]Series, seriesCount)
+		for i := range testSeriesBenchmark {
+			testSeriesBenchmark[i] = Series{Step: 10, Plots: make([]Plot, plotsCount)}
+			for j := range testSeriesBenchmark[i].Plots {
+				testSeriesBenchmark[i].Plots[j] = Plot{
+					Time:  startTime.Add(time.Duration(j*10+i%10) * time.Second),
+					Value: Value(math.Sin(float64(i+j)) * 100),
+				}
+
+				// Simulate missing plots
+				if (i+j)%97 == 0 {
+					testSeriesBenchmark[i].Plots[j].Value = Value(math.NaN())
+				}
+			}
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		if _, err := Normalize(testSeriesBenchmark, startTime, endTime, DefaultSample, consolidation, 0,
+			interpolate); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
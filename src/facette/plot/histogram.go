@@ -0,0 +1,180 @@
+package plot
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+)
+
+const (
+	// DefaultHistogramBuckets represents the default histogram value ranges count.
+	DefaultHistogramBuckets = 10
+)
+
+// Histogram represents a time series histogram instance, a matrix of time steps by value ranges.
+type Histogram struct {
+	Bounds []Value
+	Times  []time.Time
+	Values [][]Value
+	Step   int
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (h Histogram) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.jsonValue())
+}
+
+// histogramJSON represents the JSON representation of a histogram, times being expressed as Unix timestamps.
+type histogramJSON struct {
+	Bounds []Value   `json:"bounds"`
+	Times  []int     `json:"times"`
+	Values [][]Value `json:"values"`
+	Step   int       `json:"step"`
+}
+
+func (h Histogram) jsonValue() histogramJSON {
+	times := make([]int, len(h.Times))
+	for i, t := range h.Times {
+		times[i] = int(t.Unix())
+	}
+
+	return histogramJSON{h.Bounds, times, h.Values, h.Step}
+}
+
+// NewHistogram creates a new histogram by counting the series plots values falling in each of the time steps and
+// value ranges. Value ranges are evenly distributed between the minimal and maximal plots values.
+func NewHistogram(series []Series, startTime, endTime time.Time, sample, buckets int) (Histogram, error) {
+	if sample <= 0 || buckets <= 0 {
+		return Histogram{}, ErrInvalidSample
+	} else if len(series) == 0 {
+		return Histogram{}, ErrEmptySeries
+	}
+
+	// Get values boundaries
+	min, max := math.NaN(), math.NaN()
+	for _, s := range series {
+		for _, p := range s.Plots {
+			if p.Value.IsNaN() || p.Time.Before(startTime) || p.Time.After(endTime) {
+				continue
+			}
+
+			if v := float64(p.Value); v < min || math.IsNaN(min) {
+				min = v
+			}
+			if v := float64(p.Value); v > max || math.IsNaN(max) {
+				max = v
+			}
+		}
+	}
+
+	if math.IsNaN(min) {
+		return Histogram{}, ErrNotEnoughPlots
+	}
+
+	// Ensure a non-empty values range when all values are equal
+	if min == max {
+		max = min + 1
+	}
+
+	width := (max - min) / float64(buckets)
+
+	h := newHistogram(startTime, endTime, sample, buckets)
+	for i := range h.Bounds {
+		h.Bounds[i] = Value(min + float64(i)*width)
+	}
+
+	step := endTime.Sub(startTime) / time.Duration(sample)
+
+	for _, s := range series {
+		for _, p := range s.Plots {
+			if p.Value.IsNaN() || p.Time.Before(startTime) || p.Time.After(endTime) {
+				continue
+			}
+
+			idx := int(p.Time.Sub(startTime) / step)
+			if idx >= sample {
+				continue
+			}
+
+			// Include maximal value in the last range
+			bucket := int((float64(p.Value) - min) / width)
+			if bucket >= buckets {
+				bucket = buckets - 1
+			}
+
+			h.Values[idx][bucket]++
+		}
+	}
+
+	return h, nil
+}
+
+// NewHistogramFromBuckets creates a new histogram from native histogram buckets (e.g. Prometheus or InfluxDB), each
+// series representing the values of a bucket whose upper bound is given in bounds. If cumulative is true, each
+// bucket values are considered to include the previous bucket ones. Series have to be normalized beforehand.
+func NewHistogramFromBuckets(series []Series, bounds []Value, cumulative bool) (Histogram, error) {
+	length := len(series)
+	if length == 0 {
+		return Histogram{}, ErrEmptySeries
+	} else if len(bounds) != length {
+		return Histogram{}, ErrInvalidBounds
+	}
+
+	for i := 1; i < length; i++ {
+		if bounds[i] <= bounds[i-1] {
+			return Histogram{}, ErrInvalidBounds
+		}
+	}
+
+	count := len(series[0].Plots)
+
+	h := Histogram{
+		Bounds: make([]Value, length+1),
+		Times:  make([]time.Time, count),
+		Values: make([][]Value, count),
+		Step:   series[0].Step,
+	}
+
+	// Lower bound of the first bucket is unknown, thus use zero unless first bound is negative
+	if bounds[0] <= 0 {
+		h.Bounds[0] = bounds[0] - 1
+	}
+	copy(h.Bounds[1:], bounds)
+
+	for i := 0; i < count; i++ {
+		h.Times[i] = series[0].Plots[i].Time
+		h.Values[i] = make([]Value, length)
+
+		for j, s := range series {
+			if len(s.Plots) != count {
+				return Histogram{}, ErrUnnormalizedSeries
+			}
+
+			h.Values[i][j] = s.Plots[i].Value
+			if cumulative && j > 0 {
+				h.Values[i][j] -= series[j-1].Plots[i].Value
+			}
+		}
+	}
+
+	return h, nil
+}
+
+func newHistogram(startTime, endTime time.Time, sample, buckets int) Histogram {
+	step := endTime.Sub(startTime) / time.Duration(sample)
+
+	h := Histogram{
+		Bounds: make([]Value, buckets+1),
+		Times:  make([]time.Time, sample),
+		Values: make([][]Value, sample),
+		Step:   int(step.Seconds()),
+	}
+
+	values := make([]Value, sample*buckets)
+	for i := range h.Times {
+		h.Times[i] = startTime.Add(time.Duration(i) * step).Round(time.Second)
+		h.Values[i] = values[i*buckets : (i+1)*buckets : (i+1)*buckets]
+	}
+
+	return h
+}
@@ -0,0 +1,132 @@
+package plot
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_NewHistogram(t *testing.T) {
+	series := []Series{
+		{Plots: []Plot{
+			{Time: time.Unix(0, 0), Value: 0}, {Time: time.Unix(10, 0), Value: 4},
+			{Time: time.Unix(20, 0), Value: 10}, {Time: time.Unix(30, 0), Value: Value(math.NaN())},
+		}},
+		{Plots: []Plot{
+			{Time: time.Unix(5, 0), Value: 2}, {Time: time.Unix(15, 0), Value: 9},
+			{Time: time.Unix(25, 0), Value: 5}, {Time: time.Unix(35, 0), Value: 1},
+		}},
+	}
+
+	expected := Histogram{
+		Bounds: []Value{0, 5, 10},
+		Times:  []time.Time{time.Unix(0, 0), time.Unix(20, 0)},
+		Values: [][]Value{{3, 1}, {1, 2}},
+		Step:   20,
+	}
+
+	startTime := time.Unix(0, 0)
+
+	result, err := NewHistogram(series, startTime, startTime.Add(40*time.Second), 2, 2)
+	if err != nil {
+		t.Log(err)
+		t.Fail()
+	} else if !reflect.DeepEqual(result, expected) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
+		t.Fail()
+	}
+}
+
+func Test_NewHistogram_Empty(t *testing.T) {
+	startTime := time.Unix(0, 0)
+
+	if _, err := NewHistogram(nil, startTime, startTime.Add(time.Minute), 2, 2); err != ErrEmptySeries {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrEmptySeries, err)
+		t.Fail()
+	}
+}
+
+func Test_NewHistogramFromBuckets(t *testing.T) {
+	series := []Series{
+		{Step: 10, Plots: []Plot{{Time: time.Unix(0, 0), Value: 1}, {Time: time.Unix(10, 0), Value: 2}}},
+		{Step: 10, Plots: []Plot{{Time: time.Unix(0, 0), Value: 4}, {Time: time.Unix(10, 0), Value: 2}}},
+		{Step: 10, Plots: []Plot{{Time: time.Unix(0, 0), Value: 9}, {Time: time.Unix(10, 0), Value: 5}}},
+	}
+
+	expected := Histogram{
+		Bounds: []Value{0, 0.1, 0.5, 1},
+		Times:  []time.Time{time.Unix(0, 0), time.Unix(10, 0)},
+		Values: [][]Value{{1, 3, 5}, {2, 0, 3}},
+		Step:   10,
+	}
+
+	result, err := NewHistogramFromBuckets(series, []Value{0.1, 0.5, 1}, true)
+	if err != nil {
+		t.Log(err)
+		t.Fail()
+	} else if !reflect.DeepEqual(result, expected) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
+		t.Fail()
+	}
+
+	if _, err := NewHistogramFromBuckets(series, []Value{0.5, 0.1, 1}, true); err != ErrInvalidBounds {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrInvalidBounds, err)
+		t.Fail()
+	}
+}
+
+func Test_Histogram_MarshalJSON(t *testing.T) {
+	histogram := Histogram{
+		Bounds: []Value{0, 5, 10},
+		Times:  []time.Time{time.Unix(0, 0), time.Unix(20, 0)},
+		Values: [][]Value{{3, 1}, {2, Value(math.NaN())}},
+		Step:   20,
+	}
+
+	expected := `{"bounds":[0,5,10],"times":[0,20],"values":[[3,1],[2,null]],"step":20}`
+
+	data, err := json.Marshal(histogram)
+	if err != nil {
+		t.Log(err)
+		t.Fail()
+	} else if string(data) != expected {
+		t.Logf("\nExpected %s\nbut got  %s", expected, data)
+		t.Fail()
+	}
+}
+
+func Test_Response_MarshalJSON_Histograms(t *testing.T) {
+	response := Response{
+		Start:  "1970-01-01T00:00:00Z",
+		End:    "1970-01-01T00:00:40Z",
+		Series: []SeriesResponse{},
+		Histograms: []HistogramResponse{
+			{
+				Histogram: Histogram{
+					Bounds: []Value{0, 5, 10},
+					Times:  []time.Time{time.Unix(0, 0), time.Unix(20, 0)},
+					Values: [][]Value{{3, 1}, {1, 2}},
+					Step:   20,
+				},
+				Name:    "latency",
+				Options: map[string]interface{}{"unit": "ms"},
+			},
+		},
+		Options: map[string]interface{}{},
+	}
+
+	expected := `{"start":"1970-01-01T00:00:00Z","end":"1970-01-01T00:00:40Z","series":[],` +
+		`"histograms":[{"bounds":[0,5,10],"times":[0,20],"values":[[3,1],[1,2]],"step":20,"name":"latency",` +
+		`"options":{"unit":"ms"}}],"options":{}}`
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Log(err)
+		t.Fail()
+	} else if string(data) != expected {
+		t.Logf("\nExpected %s\nbut got  %s", expected, data)
+		t.Fail()
+	}
+}
@@ -15,9 +15,6 @@ const (
 type Plot struct {
 	Time  time.Time `json:"time"`
 	Value Value     `json:"value"`
-
-	prev *Plot
-	next *Plot
 }
 
 // MarshalJSON implements the json.Marshaler interface.
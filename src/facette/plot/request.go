@@ -15,6 +15,8 @@ type Request struct {
 	Time       time.Time      `json:"time,omitempty"`
 	Range      string         `json:"range,omitempty"`
 	Sample     int            `json:"sample"`
+	Downsample int            `json:"downsample,omitempty"`
+	TimeShifts []string       `json:"timeshifts,omitempty"`
 	ID         string         `json:"id"`
 	Graph      *backend.Graph `json:"graph"`
 	Attributes maputil.Map    `json:"attributes,omitempty"`
@@ -1,11 +1,15 @@
 package plot
 
+import "encoding/json"
+
 // Response represents a plot response instance.
 type Response struct {
-	Start   string                 `json:"start"`
-	End     string                 `json:"end"`
-	Series  []SeriesResponse       `json:"series"`
-	Options map[string]interface{} `json:"options"`
+	Start       string                 `json:"start"`
+	End         string                 `json:"end"`
+	Series      []SeriesResponse       `json:"series"`
+	Histograms  []HistogramResponse    `json:"histograms,omitempty"`
+	Annotations []Annotation           `json:"annotations,omitempty"`
+	Options     map[string]interface{} `json:"options"`
 }
 
 // SeriesResponse represents a plot response series instance.
@@ -14,3 +18,20 @@ type SeriesResponse struct {
 	Name    string                 `json:"name"`
 	Options map[string]interface{} `json:"options"`
 }
+
+// HistogramResponse represents a plot response histogram instance.
+type HistogramResponse struct {
+	Histogram
+	Name    string                 `json:"name"`
+	Options map[string]interface{} `json:"options"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. It is required as the embedded histogram one would otherwise
+// be promoted, dropping the response name and options.
+func (hr HistogramResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		histogramJSON
+		Name    string                 `json:"name"`
+		Options map[string]interface{} `json:"options"`
+	}{hr.Histogram.jsonValue(), hr.Name, hr.Options})
+}
@@ -4,8 +4,43 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"time"
 )
 
+const (
+	// SummaryFirst represents the first value summary statistic.
+	SummaryFirst = "first"
+	// SummarySum represents the values sum summary statistic.
+	SummarySum = "sum"
+	// SummaryCount represents the valid values count summary statistic.
+	SummaryCount = "count"
+	// SummaryStddev represents the standard deviation summary statistic.
+	SummaryStddev = "stddev"
+	// SummaryMedian represents the median value summary statistic.
+	SummaryMedian = "median"
+	// SummaryDelta represents the difference between last and first values summary statistic.
+	SummaryDelta = "delta"
+	// SummaryMinTime represents the minimal value timestamp summary statistic.
+	SummaryMinTime = "min_time"
+	// SummaryMaxTime represents the maximal value timestamp summary statistic.
+	SummaryMaxTime = "max_time"
+	// SummaryIntegral represents the values integral over time (trapezoidal rule) summary statistic.
+	SummaryIntegral = "integral"
+)
+
+// SummaryStats represents the list of supported extended summary statistics.
+var SummaryStats = []string{
+	SummaryFirst,
+	SummarySum,
+	SummaryCount,
+	SummaryStddev,
+	SummaryMedian,
+	SummaryDelta,
+	SummaryMinTime,
+	SummaryMaxTime,
+	SummaryIntegral,
+}
+
 // Series represents a time series instance.
 type Series struct {
 	Plots   []Plot           `json:"plots"`
@@ -22,30 +57,56 @@ func (s *Series) Scale(factor Value) {
 	}
 }
 
-// Summarize calculates the min/max/average/last and percentile values for a time series.
-func (s *Series) Summarize(percentiles []float64) {
+// Shift shifts the time of a series of plots by a given duration.
+func (s *Series) Shift(d time.Duration) {
+	for i := range s.Plots {
+		s.Plots[i].Time = s.Plots[i].Time.Add(d)
+	}
+}
+
+// Summarize calculates the min/max/average/last and percentile values for a time series. Extended statistics
+// (see SummaryStats) can be requested in addition.
+func (s *Series) Summarize(percentiles []float64, stats ...string) {
 	var (
-		min, max, total, current Value
-		nValidPlots              int64
+		min, max, total, current, first Value
+		minTime, maxTime                time.Time
+		integral                        float64
+		nValidPlots                     int64
+		prev                            *Plot
 	)
 
 	min = Value(math.NaN())
 	max = Value(math.NaN())
 	current = Value(math.NaN())
+	first = Value(math.NaN())
 
 	for i := range s.Plots {
-		if !s.Plots[i].Value.IsNaN() {
-			current = s.Plots[i].Value
-			if current < min || min.IsNaN() {
-				min = s.Plots[i].Value
-			}
-			if current > max || max.IsNaN() {
-				max = current
-			}
+		if s.Plots[i].Value.IsNaN() {
+			prev = nil
+			continue
+		}
+
+		current = s.Plots[i].Value
+		if current < min || min.IsNaN() {
+			min = current
+			minTime = s.Plots[i].Time
+		}
+		if current > max || max.IsNaN() {
+			max = current
+			maxTime = s.Plots[i].Time
+		}
+		if first.IsNaN() {
+			first = current
+		}
 
-			total += current
-			nValidPlots++
+		// Calculate area between previous and current plots using trapezoidal rule
+		if prev != nil {
+			integral += float64(prev.Value+current) / 2 * s.Plots[i].Time.Sub(prev.Time).Seconds()
 		}
+		prev = &s.Plots[i]
+
+		total += current
+		nValidPlots++
 	}
 
 	if s.Summary == nil {
@@ -57,6 +118,60 @@ func (s *Series) Summarize(percentiles []float64) {
 	s.Summary["avg"] = total / Value(nValidPlots)
 	s.Summary["last"] = current
 
+	for _, stat := range stats {
+		switch stat {
+		case SummaryFirst:
+			s.Summary[stat] = first
+
+		case SummarySum:
+			if nValidPlots > 0 {
+				s.Summary[stat] = total
+			} else {
+				s.Summary[stat] = Value(math.NaN())
+			}
+
+		case SummaryCount:
+			s.Summary[stat] = Value(nValidPlots)
+
+		case SummaryStddev:
+			if set := s.values(); len(set) > 0 {
+				s.Summary[stat] = Value(stddevValue(set))
+			} else {
+				s.Summary[stat] = Value(math.NaN())
+			}
+
+		case SummaryMedian:
+			if set := s.values(); len(set) > 0 {
+				sort.Float64s(set)
+				s.Summary[stat] = Value(percentileValue(set, 50))
+			} else {
+				s.Summary[stat] = Value(math.NaN())
+			}
+
+		case SummaryDelta:
+			s.Summary[stat] = current - first
+
+		case SummaryMinTime, SummaryMaxTime:
+			t := minTime
+			if stat == SummaryMaxTime {
+				t = maxTime
+			}
+
+			if t.IsZero() {
+				s.Summary[stat] = Value(math.NaN())
+			} else {
+				s.Summary[stat] = Value(t.Unix())
+			}
+
+		case SummaryIntegral:
+			if nValidPlots > 0 {
+				s.Summary[stat] = Value(integral)
+			} else {
+				s.Summary[stat] = Value(math.NaN())
+			}
+		}
+	}
+
 	if len(percentiles) > 0 {
 		s.Percentiles(percentiles)
 	}
@@ -64,18 +179,12 @@ func (s *Series) Summarize(percentiles []float64) {
 
 // Percentiles calculates the percentile values for a time series.
 func (s *Series) Percentiles(values []float64) {
-	var set []float64
-
 	// Stop if no percentile value provided
 	if len(values) == 0 {
 		return
 	}
 
-	for i := range s.Plots {
-		if !s.Plots[i].Value.IsNaN() {
-			set = append(set, float64(s.Plots[i].Value))
-		}
-	}
+	set := s.values()
 
 	count := len(set)
 	if count == 0 {
@@ -86,20 +195,52 @@ func (s *Series) Percentiles(values []float64) {
 
 	// Calculate percentiles
 	for _, pct := range values {
-		label := fmt.Sprintf("%gth", pct)
-
-		rank := (pct / 100) * float64(count+1)
-		rankInt := int(rank)
-		rankFrac := rank - float64(rankInt)
+		s.Summary[fmt.Sprintf("%gth", pct)] = Value(percentileValue(set, pct))
+	}
+}
 
-		if rank <= 0.0 {
-			s.Summary[label] = Value(set[0])
-			continue
-		} else if rank-1.0 >= float64(count) {
-			s.Summary[label] = Value(set[count-1])
-			continue
+func (s *Series) values() []float64 {
+	set := []float64{}
+	for i := range s.Plots {
+		if !s.Plots[i].Value.IsNaN() {
+			set = append(set, float64(s.Plots[i].Value))
 		}
+	}
+
+	return set
+}
 
-		s.Summary[label] = Value(set[rankInt-1] + rankFrac*(set[rankInt]-set[rankInt-1]))
+// percentileValue returns the percentile value of a sorted set of values.
+func percentileValue(set []float64, pct float64) float64 {
+	count := len(set)
+
+	rank := (pct / 100) * float64(count+1)
+	rankInt := int(rank)
+	rankFrac := rank - float64(rankInt)
+
+	if rankInt <= 0 {
+		return set[0]
+	} else if rankInt >= count {
+		return set[count-1]
 	}
+
+	return set[rankInt-1] + rankFrac*(set[rankInt]-set[rankInt-1])
+}
+
+// stddevValue returns the population standard deviation of a set of values.
+func stddevValue(set []float64) float64 {
+	count := float64(len(set))
+
+	mean := 0.0
+	for _, v := range set {
+		mean += v
+	}
+	mean /= count
+
+	variance := 0.0
+	for _, v := range set {
+		variance += (v - mean) * (v - mean)
+	}
+
+	return math.Sqrt(variance / count)
 }
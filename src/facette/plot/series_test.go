@@ -22,6 +22,22 @@ func Test_Scale(t *testing.T) {
 	}
 }
 
+func Test_Shift(t *testing.T) {
+	series := Series{
+		Plots: []Plot{{Time: time.Unix(0, 0), Value: 1}, {Time: time.Unix(60, 0), Value: 2}},
+	}
+
+	expected := Series{
+		Plots: []Plot{{Time: time.Unix(604800, 0), Value: 1}, {Time: time.Unix(604860, 0), Value: 2}},
+	}
+
+	series.Shift(7 * 24 * time.Hour)
+	if !compareSeries(series, expected) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, series)
+		t.Fail()
+	}
+}
+
 func Test_Summarize(t *testing.T) {
 	series := Series{
 		Plots: []Plot{
@@ -83,6 +99,54 @@ func Test_Summarize(t *testing.T) {
 	}
 }
 
+func Test_Summarize_Extended(t *testing.T) {
+	series := Series{
+		Plots: []Plot{
+			{Time: time.Unix(0, 0), Value: Value(math.NaN())}, {Time: time.Unix(10, 0), Value: 4},
+			{Time: time.Unix(20, 0), Value: 8}, {Time: time.Unix(30, 0), Value: 2},
+			{Time: time.Unix(40, 0), Value: Value(math.NaN())}, {Time: time.Unix(50, 0), Value: 6},
+			{Time: time.Unix(60, 0), Value: 10},
+		},
+	}
+
+	checks := []struct {
+		label string
+		value Value
+	}{
+		{"min", 2},
+		{"max", 10},
+		{"avg", 6},
+		{"last", 10},
+		{"first", 4},
+		{"sum", 30},
+		{"count", 5},
+		{"stddev", Value(math.Sqrt(8))},
+		{"median", 6},
+		{"delta", 6},
+		{"min_time", 30},
+		{"max_time", 60},
+		{"integral", 60 + 50 + 80},
+	}
+
+	series.Summarize(nil, SummaryStats...)
+
+	for _, c := range checks {
+		if series.Summary[c.label] != c.value {
+			t.Logf("\nExpected %s=%g\nbut got  %s=%g", c.label, c.value, c.label, series.Summary[c.label])
+			t.Fail()
+		}
+	}
+
+	// Ensure extended statistics are only calculated if requested
+	series.Summary = nil
+	series.Summarize(nil, SummarySum)
+
+	if _, ok := series.Summary["integral"]; ok || len(series.Summary) != 5 {
+		t.Logf("\nExpected 5 summary entries\nbut got  %d", len(series.Summary))
+		t.Fail()
+	}
+}
+
 func compareSeries(actual, expected Series) bool {
 	if len(actual.Plots) != len(expected.Plots) {
 		return false
@@ -0,0 +1,71 @@
+package render
+
+import (
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	anchorStart = iota
+	anchorMiddle
+	anchorEnd
+)
+
+const (
+	// Character dimensions of the fixed-width font used for text rendering
+	charWidth  = 7
+	charHeight = 13
+)
+
+var (
+	colorBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	colorText       = color.RGBA{0x33, 0x33, 0x33, 0xff}
+	colorSubtle     = color.RGBA{0x99, 0x99, 0x99, 0xff}
+	colorAxis       = color.RGBA{0xcc, 0xcc, 0xcc, 0xff}
+	colorGrid       = color.RGBA{0xee, 0xee, 0xee, 0xff}
+
+	// Default series and constant lines colors, as used by the frontend charts
+	seriesColors = []color.RGBA{
+		{0x7c, 0xb5, 0xec, 0xff}, {0x43, 0x43, 0x48, 0xff}, {0x90, 0xed, 0x7d, 0xff}, {0xf7, 0xa3, 0x5c, 0xff},
+		{0x80, 0x85, 0xe9, 0xff}, {0xf1, 0x5c, 0x80, 0xff}, {0xe4, 0xd3, 0x54, 0xff}, {0x80, 0x85, 0xe8, 0xff},
+		{0x8d, 0x46, 0x53, 0xff}, {0x91, 0xe8, 0xe1, 0xff},
+	}
+	lineColors = []color.RGBA{
+		{0x16, 0xa0, 0x85, 0xff}, {0x27, 0xae, 0x60, 0xff}, {0x29, 0x80, 0xb9, 0xff}, {0x8e, 0x44, 0xad, 0xff},
+		{0x2c, 0x3e, 0x50, 0xff}, {0xf3, 0x9c, 0x12, 0xff}, {0xd3, 0x54, 0x00, 0xff}, {0xc0, 0x39, 0x2b, 0xff},
+	}
+)
+
+// canvas represents the drawing surface interface implemented by the rendering formats.
+type canvas interface {
+	line(x1, y1, x2, y2 float64, c color.RGBA)
+	polyline(xs, ys []float64, c color.RGBA)
+	area(xs, upper, lower []float64, c color.RGBA)
+	rect(x, y, width, height float64, c color.RGBA)
+	text(x, y float64, s string, anchor int, c color.RGBA)
+	encode(w io.Writer) error
+}
+
+func textWidth(s string) float64 {
+	return float64(len([]rune(s)) * charWidth)
+}
+
+func parseColor(s string) (color.RGBA, bool) {
+	if !strings.HasPrefix(s, "#") || len(s) != 7 {
+		return color.RGBA{}, false
+	}
+
+	v, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return color.RGBA{}, false
+	}
+
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}, true
+}
+
+func fade(c color.RGBA, alpha uint8) color.RGBA {
+	c.A = alpha
+	return c
+}
@@ -0,0 +1,338 @@
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"facette/plot"
+
+	"github.com/facette/sliceutil"
+)
+
+const (
+	chartPadding    = 10
+	chartLineHeight = 18
+	chartTickWidth  = 90
+	legendRowHeight = 16
+	areaAlpha       = 0x55
+)
+
+var summaryOrder = []string{"min", "avg", "max", "last"}
+
+type chartSeries struct {
+	name    string
+	color   color.RGBA
+	times   []time.Time
+	upper   []float64
+	lower   []float64
+	summary map[string]plot.Value
+}
+
+// chart represents a graph chart layout instance, drawing plot response series on a canvas.
+type chart struct {
+	opts      Options
+	startTime time.Time
+	endTime   time.Time
+	series    []chartSeries
+	min       float64
+	max       float64
+}
+
+func newChart(resp *plot.Response, opts Options) *chart {
+	ch := &chart{
+		opts:      opts,
+		startTime: parseTime(resp.Start),
+		endTime:   parseTime(resp.End),
+		min:       math.NaN(),
+		max:       math.NaN(),
+	}
+
+	var totals, cumulated []float64
+
+	if opts.StackMode != "" {
+		for _, s := range resp.Series {
+			for i, p := range s.Plots {
+				if i >= len(totals) {
+					totals = append(totals, 0)
+					cumulated = append(cumulated, 0)
+				}
+
+				if !p.Value.IsNaN() {
+					totals[i] += float64(p.Value)
+				}
+			}
+		}
+	}
+
+	for idx, s := range resp.Series {
+		cs := chartSeries{
+			name:    s.Name,
+			color:   seriesColors[idx%len(seriesColors)],
+			times:   make([]time.Time, len(s.Plots)),
+			upper:   make([]float64, len(s.Plots)),
+			lower:   make([]float64, len(s.Plots)),
+			summary: s.Summary,
+		}
+
+		if v, ok := s.Options["color"].(string); ok {
+			if c, ok := parseColor(v); ok {
+				cs.color = c
+			}
+		}
+
+		for i, p := range s.Plots {
+			cs.times[i] = p.Time
+			cs.upper[i] = float64(p.Value)
+
+			if opts.StackMode != "" {
+				// Stack series on top of each other, missing values being considered as zero
+				value := float64(p.Value)
+				if p.Value.IsNaN() {
+					value = 0
+				}
+
+				if opts.StackMode == StackPercent {
+					if totals[i] != 0 {
+						value /= totals[i]
+					} else {
+						value = 0
+					}
+				}
+
+				cs.lower[i] = cumulated[i]
+				cs.upper[i] = cumulated[i] + value
+				cumulated[i] = cs.upper[i]
+			}
+
+			ch.extend(cs.upper[i])
+			if opts.StackMode != "" || opts.Type == TypeArea {
+				ch.extend(cs.lower[i])
+			}
+		}
+
+		ch.series = append(ch.series, cs)
+	}
+
+	for _, v := range opts.Constants {
+		ch.extend(v)
+	}
+
+	if opts.StackMode == StackPercent {
+		ch.min, ch.max = 0, 1
+	} else if math.IsNaN(ch.min) {
+		ch.min, ch.max = 0, 1
+	}
+
+	// Fallback to series time boundaries if none provided
+	if ch.startTime.IsZero() || ch.endTime.IsZero() {
+		for _, cs := range ch.series {
+			for _, t := range cs.times {
+				if ch.startTime.IsZero() || t.Before(ch.startTime) {
+					ch.startTime = t
+				}
+				if ch.endTime.IsZero() || t.After(ch.endTime) {
+					ch.endTime = t
+				}
+			}
+		}
+	}
+
+	if !ch.endTime.After(ch.startTime) {
+		ch.endTime = ch.startTime.Add(time.Minute)
+	}
+
+	return ch
+}
+
+func (ch *chart) extend(v float64) {
+	if math.IsNaN(v) {
+		return
+	}
+
+	if v < ch.min || math.IsNaN(ch.min) {
+		ch.min = v
+	}
+	if v > ch.max || math.IsNaN(ch.max) {
+		ch.max = v
+	}
+}
+
+func (ch *chart) draw(c canvas) error {
+	width, height := float64(ch.opts.Width), float64(ch.opts.Height)
+
+	// Draw title and time range subtitle
+	top := float64(chartPadding)
+
+	if ch.opts.Title != "" {
+		c.text(chartPadding, top+charHeight, ch.opts.Title, anchorStart, colorText)
+		top += chartLineHeight
+	}
+
+	c.text(chartPadding, top+charHeight, fmt.Sprintf("%s - %s", ch.startTime.Format("2006-01-02 15:04:05"),
+		ch.endTime.Format("2006-01-02 15:04:05")), anchorStart, colorSubtle)
+	top += chartLineHeight
+
+	if ch.opts.YAxisLabel != "" {
+		c.text(chartPadding, top+charHeight, ch.opts.YAxisLabel, anchorStart, colorSubtle)
+		top += chartLineHeight
+	}
+
+	top += chartPadding / 2
+
+	// Compute plot area boundaries
+	bottom := height - chartPadding - chartLineHeight
+	if ch.opts.Legend && len(ch.series) > 0 {
+		bottom -= float64(len(ch.series)*legendRowHeight + chartPadding)
+	}
+
+	ticks := valueTicks(ch.min, ch.max, 4)
+	ymin, ymax := ticks[0], ticks[len(ticks)-1]
+
+	labels := make([]string, len(ticks))
+	labelWidth := 0.0
+	for i, v := range ticks {
+		if ch.opts.StackMode == StackPercent {
+			labels[i] = fmt.Sprintf("%.0f%%", v*100)
+		} else {
+			labels[i] = formatValue(v, ch.opts.YAxisUnit)
+		}
+
+		labelWidth = math.Max(labelWidth, textWidth(labels[i]))
+	}
+
+	left := chartPadding + labelWidth + 6
+	right := width - chartPadding
+
+	if right-left < 50 || bottom-top < 30 {
+		return ErrInvalidSize
+	}
+
+	xPos := func(t time.Time) float64 {
+		return left + float64(t.Sub(ch.startTime))/float64(ch.endTime.Sub(ch.startTime))*(right-left)
+	}
+
+	yPos := func(v float64) float64 {
+		return bottom - (v-ymin)/(ymax-ymin)*(bottom-top)
+	}
+
+	// Draw axes and grid
+	for i, v := range ticks {
+		y := yPos(v)
+		c.line(left, y, right, y, colorGrid)
+		c.text(left-6, y+4, labels[i], anchorEnd, colorText)
+	}
+
+	times, layout := timeTicks(ch.startTime, ch.endTime, int((right-left)/chartTickWidth))
+	for _, t := range times {
+		x := xPos(t)
+		c.line(x, bottom, x, bottom+4, colorAxis)
+
+		// Skip labels overflowing image boundaries
+		label := t.Format(layout)
+		if x+textWidth(label)/2 <= width && x-textWidth(label)/2 >= 0 {
+			c.text(x, bottom+charHeight+4, label, anchorMiddle, colorText)
+		}
+	}
+
+	c.line(left, bottom, right, bottom, colorAxis)
+
+	// Draw series, splitting them into segments on missing values
+	for _, cs := range ch.series {
+		var xs, upper, lower []float64
+
+		flush := func() {
+			if len(xs) == 0 {
+				return
+			}
+
+			if ch.opts.Type == TypeArea {
+				c.area(xs, upper, lower, fade(cs.color, areaAlpha))
+			}
+			c.polyline(xs, upper, cs.color)
+
+			xs, upper, lower = nil, nil, nil
+		}
+
+		for i, t := range cs.times {
+			if math.IsNaN(cs.upper[i]) || t.Before(ch.startTime) || t.After(ch.endTime) {
+				flush()
+				continue
+			}
+
+			xs = append(xs, xPos(t))
+			upper = append(upper, yPos(cs.upper[i]))
+			lower = append(lower, yPos(math.Max(cs.lower[i], ymin)))
+		}
+
+		flush()
+	}
+
+	// Draw constant lines
+	for i, v := range ch.opts.Constants {
+		if v >= ymin && v <= ymax {
+			c.line(left, yPos(v), right, yPos(v), lineColors[i%len(lineColors)])
+		}
+	}
+
+	// Draw legend along with series summaries
+	if !ch.opts.Legend {
+		return nil
+	}
+
+	nameWidth := 0.0
+	for _, cs := range ch.series {
+		nameWidth = math.Max(nameWidth, textWidth(cs.name))
+	}
+
+	y := bottom + chartLineHeight + chartPadding
+	for _, cs := range ch.series {
+		y += legendRowHeight
+
+		c.rect(chartPadding, y-10, 10, 10, cs.color)
+		c.text(chartPadding+16, y, cs.name, anchorStart, colorText)
+		c.text(chartPadding+32+nameWidth, y, ch.formatSummary(cs.summary), anchorStart, colorSubtle)
+	}
+
+	return nil
+}
+
+func (ch *chart) formatSummary(summary map[string]plot.Value) string {
+	keys := []string{}
+	for _, key := range append(summaryOrder, plot.SummaryStats...) {
+		if _, ok := summary[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+
+	// Append remaining keys (i.e. percentiles) sorted
+	extra := []string{}
+	for key := range summary {
+		if !sliceutil.Has(keys, key) {
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(extra)
+
+	parts := []string{}
+	for _, key := range append(keys, extra...) {
+		value := summary[key]
+
+		if key == plot.SummaryMinTime || key == plot.SummaryMaxTime {
+			if value.IsNaN() {
+				parts = append(parts, fmt.Sprintf("%s: -", key))
+			} else {
+				parts = append(parts, fmt.Sprintf("%s: %s", key,
+					time.Unix(int64(value), 0).UTC().Format("15:04:05")))
+			}
+			continue
+		}
+
+		parts = append(parts, fmt.Sprintf("%s: %s", key, formatValue(float64(value), ch.opts.YAxisUnit)))
+	}
+
+	return strings.Join(parts, "  ")
+}
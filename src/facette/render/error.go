@@ -0,0 +1,10 @@
+package render
+
+import "errors"
+
+var (
+	// ErrInvalidSize represents an invalid image size error.
+	ErrInvalidSize = errors.New("invalid image size")
+	// ErrUnsupportedFormat represents an unsupported rendering format error.
+	ErrUnsupportedFormat = errors.New("unsupported rendering format")
+)
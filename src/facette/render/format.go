@@ -0,0 +1,115 @@
+package render
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+var (
+	metricPrefixes = []string{"y", "z", "a", "f", "p", "n", "µ", "m", "", "k", "M", "G", "T", "P", "E", "Z", "Y"}
+	binaryPrefixes = []string{"", "k", "M", "G", "T", "P", "E", "Z", "Y"}
+
+	timeSteps = []time.Duration{
+		time.Minute, 2 * time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+		time.Hour, 2 * time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour,
+		24 * time.Hour, 48 * time.Hour, 7 * 24 * time.Hour, 14 * 24 * time.Hour, 28 * 24 * time.Hour,
+	}
+)
+
+// formatValue formats a value according to a Y axis unit.
+func formatValue(v float64, unit string) string {
+	if math.IsNaN(v) {
+		return "-"
+	} else if v == 0 {
+		return "0"
+	}
+
+	switch unit {
+	case UnitMetric:
+		// Use SI prefixes with 3 significant digits
+		idx := int(math.Floor(math.Log10(math.Abs(v)) / 3))
+		if idx < -8 {
+			idx = -8
+		} else if idx > 8 {
+			idx = 8
+		}
+
+		return formatFloat(v/math.Pow(1000, float64(idx)), 3) + metricPrefixes[idx+8]
+
+	case UnitBinary:
+		idx := int(math.Log(math.Abs(v)) / math.Log(1024))
+		if idx < 0 {
+			idx = 0
+		} else if idx >= len(binaryPrefixes) {
+			idx = len(binaryPrefixes) - 1
+		}
+
+		return strconv.FormatFloat(math.Round(v/math.Pow(1024, float64(idx))*100)/100, 'f', -1, 64) +
+			binaryPrefixes[idx]
+	}
+
+	return formatFloat(v, 6)
+}
+
+func formatFloat(v float64, digits int) string {
+	return strconv.FormatFloat(v, 'g', digits, 64)
+}
+
+// valueTicks returns evenly spaced rounded ticks covering a values range.
+func valueTicks(min, max float64, count int) []float64 {
+	if max <= min {
+		max = min + 1
+	}
+
+	// Compute a "nice" step (1, 2 or 5 times a power of ten)
+	raw := (max - min) / float64(count)
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+
+	step := 10 * magnitude
+	for _, factor := range []float64{1, 2, 5} {
+		if raw <= factor*magnitude {
+			step = factor * magnitude
+			break
+		}
+	}
+
+	ticks := []float64{}
+	for v := math.Floor(min/step) * step; v <= max+step/2; v += step {
+		ticks = append(ticks, v)
+		if v >= max {
+			break
+		}
+	}
+
+	return ticks
+}
+
+// timeTicks returns the ticks for a time range, along with the layout to use to format their labels.
+func timeTicks(startTime, endTime time.Time, count int) ([]time.Time, string) {
+	if count < 1 {
+		count = 1
+	}
+
+	step := timeSteps[len(timeSteps)-1]
+	for _, s := range timeSteps {
+		if endTime.Sub(startTime)/s <= time.Duration(count) {
+			step = s
+			break
+		}
+	}
+
+	layout := "15:04"
+	if step >= 24*time.Hour {
+		layout = "Jan 02"
+	}
+
+	ticks := []time.Time{}
+	for t := startTime.Truncate(step); !t.After(endTime); t = t.Add(step) {
+		if !t.Before(startTime) {
+			ticks = append(ticks, t)
+		}
+	}
+
+	return ticks, layout
+}
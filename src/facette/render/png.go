@@ -0,0 +1,142 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// pngCanvas implements the canvas interface for the PNG format.
+type pngCanvas struct {
+	img *image.RGBA
+}
+
+func newPNGCanvas(width, height int) *pngCanvas {
+	c := &pngCanvas{img: image.NewRGBA(image.Rect(0, 0, width, height))}
+	c.rect(0, 0, float64(width), float64(height), colorBackground)
+
+	return c
+}
+
+func (c *pngCanvas) line(x1, y1, x2, y2 float64, col color.RGBA) {
+	c.drawLine(x1, y1, x2, y2, col, false)
+}
+
+func (c *pngCanvas) polyline(xs, ys []float64, col color.RGBA) {
+	if len(xs) == 1 {
+		c.rect(xs[0]-1, ys[0]-1, 2, 2, col)
+		return
+	}
+
+	for i := 1; i < len(xs); i++ {
+		c.drawLine(xs[i-1], ys[i-1], xs[i], ys[i], col, true)
+	}
+}
+
+func (c *pngCanvas) area(xs, upper, lower []float64, col color.RGBA) {
+	n := len(xs)
+
+	for i := 0; i < n-1; i++ {
+		end := math.Ceil(xs[i+1])
+		if i == n-2 {
+			end = math.Floor(xs[i+1]) + 1
+		}
+
+		for x := math.Ceil(xs[i]); x < end; x++ {
+			ratio := 0.0
+			if xs[i+1] != xs[i] {
+				ratio = (x - xs[i]) / (xs[i+1] - xs[i])
+			}
+
+			y1 := upper[i] + (upper[i+1]-upper[i])*ratio
+			y2 := lower[i] + (lower[i+1]-lower[i])*ratio
+
+			for y := math.Round(math.Min(y1, y2)); y < math.Round(math.Max(y1, y2)); y++ {
+				c.blend(int(x), int(y), col)
+			}
+		}
+	}
+}
+
+func (c *pngCanvas) rect(x, y, width, height float64, col color.RGBA) {
+	for py := int(math.Round(y)); py < int(math.Round(y+height)); py++ {
+		for px := int(math.Round(x)); px < int(math.Round(x+width)); px++ {
+			c.blend(px, py, col)
+		}
+	}
+}
+
+func (c *pngCanvas) text(x, y float64, s string, anchor int, col color.RGBA) {
+	d := &font.Drawer{
+		Dst:  c.img,
+		Src:  image.NewUniform(col),
+		Face: basicfont.Face7x13,
+	}
+
+	switch anchor {
+	case anchorMiddle:
+		x -= float64(d.MeasureString(s).Round()) / 2
+
+	case anchorEnd:
+		x -= float64(d.MeasureString(s).Round())
+	}
+
+	d.Dot = fixed.P(int(math.Round(x)), int(math.Round(y)))
+	d.DrawString(s)
+}
+
+func (c *pngCanvas) encode(w io.Writer) error {
+	return png.Encode(w, c.img)
+}
+
+func (c *pngCanvas) drawLine(x1, y1, x2, y2 float64, col color.RGBA, thick bool) {
+	dx, dy := x2-x1, y2-y1
+
+	steps := math.Max(math.Abs(dx), math.Abs(dy))
+	if steps < 1 {
+		steps = 1
+	}
+
+	for i := 0.0; i <= steps; i++ {
+		x := int(math.Round(x1 + dx*i/steps))
+		y := int(math.Round(y1 + dy*i/steps))
+
+		c.blend(x, y, col)
+
+		// Thicken line perpendicularly to its main direction
+		if thick {
+			if math.Abs(dx) >= math.Abs(dy) {
+				c.blend(x, y+1, col)
+			} else {
+				c.blend(x+1, y, col)
+			}
+		}
+	}
+}
+
+// blend draws a pixel using non-premultiplied alpha compositing over the existing one.
+func (c *pngCanvas) blend(x, y int, col color.RGBA) {
+	if !(image.Point{x, y}).In(c.img.Rect) {
+		return
+	}
+
+	if col.A == 0xff {
+		c.img.SetRGBA(x, y, col)
+		return
+	}
+
+	dst := c.img.RGBAAt(x, y)
+	a := float64(col.A) / 0xff
+
+	mix := func(s, d uint8) uint8 {
+		return uint8(math.Round(float64(s)*a + float64(d)*(1-a)))
+	}
+
+	c.img.SetRGBA(x, y, color.RGBA{mix(col.R, dst.R), mix(col.G, dst.G), mix(col.B, dst.B), 0xff})
+}
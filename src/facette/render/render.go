@@ -0,0 +1,138 @@
+package render
+
+import (
+	"io"
+	"time"
+
+	"facette/plot"
+
+	"github.com/facette/maputil"
+)
+
+const (
+	// FormatPNG represents the PNG image rendering format.
+	FormatPNG = "png"
+	// FormatSVG represents the SVG image rendering format.
+	FormatSVG = "svg"
+)
+
+const (
+	// TypeArea represents the area graph type.
+	TypeArea = "area"
+	// TypeLine represents the line graph type.
+	TypeLine = "line"
+)
+
+const (
+	// StackNormal represents the normal series stacking mode.
+	StackNormal = "normal"
+	// StackPercent represents the percentage series stacking mode.
+	StackPercent = "percent"
+)
+
+const (
+	// UnitFixed represents the fixed Y axis unit.
+	UnitFixed = "fixed"
+	// UnitMetric represents the metric (SI prefixes) Y axis unit.
+	UnitMetric = "metric"
+	// UnitBinary represents the binary (powers of 1024) Y axis unit.
+	UnitBinary = "binary"
+)
+
+const (
+	// DefaultWidth represents the default image width in pixels.
+	DefaultWidth = 800
+	// DefaultHeight represents the default image height in pixels.
+	DefaultHeight = 400
+	// MaxSize represents the maximal image width or height in pixels.
+	MaxSize = 4096
+)
+
+// Options represents a graph rendering options instance.
+type Options struct {
+	Width      int
+	Height     int
+	Title      string
+	Type       string
+	StackMode  string
+	YAxisUnit  string
+	YAxisLabel string
+	Legend     bool
+	Constants  []float64
+}
+
+// NewOptions creates new rendering options from plot response options, as set in graph definitions.
+func NewOptions(options map[string]interface{}) Options {
+	m := maputil.Map(options)
+
+	opts := Options{
+		Width:  DefaultWidth,
+		Height: DefaultHeight,
+		Legend: true,
+	}
+
+	opts.Title, _ = m.GetString("title", "")
+	opts.Type, _ = m.GetString("type", TypeArea)
+	opts.StackMode, _ = m.GetString("stack_mode", "")
+	opts.YAxisUnit, _ = m.GetString("yaxis_unit", UnitFixed)
+	opts.YAxisLabel, _ = m.GetString("yaxis_label", "")
+
+	if v, ok := options["legend"].(bool); ok {
+		opts.Legend = v
+	}
+
+	if v, ok := options["constants"].([]interface{}); ok {
+		for _, entry := range v {
+			if f, ok := entry.(float64); ok {
+				opts.Constants = append(opts.Constants, f)
+			}
+		}
+	}
+
+	return opts
+}
+
+// Render renders a plot response as an image in the given format, writing it to w.
+func Render(w io.Writer, format string, resp *plot.Response, opts Options) error {
+	if opts.Width <= 0 || opts.Height <= 0 || opts.Width > MaxSize || opts.Height > MaxSize {
+		return ErrInvalidSize
+	}
+
+	var c canvas
+
+	switch format {
+	case FormatPNG:
+		c = newPNGCanvas(opts.Width, opts.Height)
+
+	case FormatSVG:
+		c = newSVGCanvas(opts.Width, opts.Height)
+
+	default:
+		return ErrUnsupportedFormat
+	}
+
+	ch := newChart(resp, opts)
+	if err := ch.draw(c); err != nil {
+		return err
+	}
+
+	return c.encode(w)
+}
+
+// ContentType returns the MIME type matching a rendering format.
+func ContentType(format string) string {
+	switch format {
+	case FormatPNG:
+		return "image/png"
+
+	case FormatSVG:
+		return "image/svg+xml"
+	}
+
+	return ""
+}
+
+func parseTime(value string) time.Time {
+	t, _ := time.Parse(time.RFC3339, value)
+	return t
+}
@@ -0,0 +1,165 @@
+package render
+
+import (
+	"bytes"
+	"image/png"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"facette/plot"
+)
+
+func Test_Render_PNG(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+
+	opts := testOptions()
+	if err := Render(buf, FormatPNG, testResponse(), opts); err != nil {
+		t.Log(err)
+		t.Fail()
+		return
+	}
+
+	img, err := png.Decode(buf)
+	if err != nil {
+		t.Log(err)
+		t.Fail()
+	} else if size := img.Bounds().Size(); size.X != opts.Width || size.Y != opts.Height {
+		t.Logf("\nExpected %dx%d\nbut got  %dx%d", opts.Width, opts.Height, size.X, size.Y)
+		t.Fail()
+	}
+}
+
+func Test_Render_SVG(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+
+	if err := Render(buf, FormatSVG, testResponse(), testOptions()); err != nil {
+		t.Log(err)
+		t.Fail()
+		return
+	}
+
+	result := buf.String()
+	for _, s := range []string{`width="640"`, ">Graph &amp; title<", ">series1<", ">series2<", "avg: 3  max: 6"} {
+		if !strings.Contains(result, s) {
+			t.Logf("\nExpected %q in output", s)
+			t.Fail()
+		}
+	}
+}
+
+func Test_Render_Invalid(t *testing.T) {
+	opts := testOptions()
+
+	if err := Render(bytes.NewBuffer(nil), "bmp", testResponse(), opts); err != ErrUnsupportedFormat {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrUnsupportedFormat, err)
+		t.Fail()
+	}
+
+	opts.Height = 40
+	if err := Render(bytes.NewBuffer(nil), FormatSVG, testResponse(), opts); err != ErrInvalidSize {
+		t.Logf("\nExpected %#v\nbut got  %#v", ErrInvalidSize, err)
+		t.Fail()
+	}
+}
+
+func Test_NewOptions(t *testing.T) {
+	expected := Options{
+		Width:     DefaultWidth,
+		Height:    DefaultHeight,
+		Title:     "title",
+		Type:      TypeLine,
+		StackMode: StackNormal,
+		YAxisUnit: UnitMetric,
+		Legend:    false,
+		Constants: []float64{1, 2},
+	}
+
+	result := NewOptions(map[string]interface{}{
+		"title":      "title",
+		"type":       "line",
+		"stack_mode": "normal",
+		"yaxis_unit": "metric",
+		"legend":     false,
+		"constants":  []interface{}{1.0, 2.0},
+	})
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
+		t.Fail()
+	}
+}
+
+func Test_formatValue(t *testing.T) {
+	checks := []struct {
+		value    float64
+		unit     string
+		expected string
+	}{
+		{0, UnitFixed, "0"},
+		{math.NaN(), UnitFixed, "-"},
+		{0.25, UnitFixed, "0.25"},
+		{123456, UnitFixed, "123456"},
+		{1500, UnitMetric, "1.5k"},
+		{-0.002, UnitMetric, "-2m"},
+		{2048, UnitBinary, "2k"},
+		{1536 * 1024, UnitBinary, "1.5M"},
+	}
+
+	for _, c := range checks {
+		if result := formatValue(c.value, c.unit); result != c.expected {
+			t.Logf("\nExpected %q\nbut got  %q", c.expected, result)
+			t.Fail()
+		}
+	}
+}
+
+func Test_valueTicks(t *testing.T) {
+	expected := []float64{0, 20, 40, 60, 80}
+
+	if result := valueTicks(3, 72, 4); !reflect.DeepEqual(result, expected) {
+		t.Logf("\nExpected %#v\nbut got  %#v", expected, result)
+		t.Fail()
+	}
+}
+
+func testOptions() Options {
+	return Options{
+		Width:     640,
+		Height:    240,
+		Title:     "Graph & title",
+		Type:      TypeArea,
+		StackMode: StackNormal,
+		YAxisUnit: UnitFixed,
+		Legend:    true,
+		Constants: []float64{3},
+	}
+}
+
+func testResponse() *plot.Response {
+	startTime := time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	resp := &plot.Response{
+		Start: startTime.Format(time.RFC3339),
+		End:   startTime.Add(time.Hour).Format(time.RFC3339),
+	}
+
+	for i, name := range []string{"series1", "series2"} {
+		s := plot.Series{Step: 600}
+		for j := 0; j < 7; j++ {
+			value := plot.Value(j + i)
+			if j == 3 {
+				value = plot.Value(math.NaN())
+			}
+
+			s.Plots = append(s.Plots, plot.Plot{Time: startTime.Add(time.Duration(j) * 10 * time.Minute), Value: value})
+		}
+		s.Summarize(nil)
+
+		resp.Series = append(resp.Series, plot.SeriesResponse{Series: s, Name: name})
+	}
+
+	return resp
+}
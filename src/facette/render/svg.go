@@ -0,0 +1,89 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+)
+
+var svgAnchors = map[int]string{
+	anchorStart:  "start",
+	anchorMiddle: "middle",
+	anchorEnd:    "end",
+}
+
+// svgCanvas implements the canvas interface for the SVG format.
+type svgCanvas struct {
+	width  int
+	height int
+	buf    bytes.Buffer
+}
+
+func newSVGCanvas(width, height int) *svgCanvas {
+	c := &svgCanvas{width: width, height: height}
+	c.rect(0, 0, float64(width), float64(height), colorBackground)
+
+	return c
+}
+
+func (c *svgCanvas) line(x1, y1, x2, y2 float64, col color.RGBA) {
+	fmt.Fprintf(&c.buf, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" %s/>`+"\n", x1, y1, x2, y2,
+		svgPaint("stroke", col))
+}
+
+func (c *svgCanvas) polyline(xs, ys []float64, col color.RGBA) {
+	c.buf.WriteString(`<polyline fill="none" stroke-width="1.5" points="`)
+	for i := range xs {
+		fmt.Fprintf(&c.buf, "%.1f,%.1f ", xs[i], ys[i])
+	}
+	fmt.Fprintf(&c.buf, `" %s/>`+"\n", svgPaint("stroke", col))
+}
+
+func (c *svgCanvas) area(xs, upper, lower []float64, col color.RGBA) {
+	c.buf.WriteString(`<polygon points="`)
+	for i := range xs {
+		fmt.Fprintf(&c.buf, "%.1f,%.1f ", xs[i], upper[i])
+	}
+	for i := len(xs) - 1; i >= 0; i-- {
+		fmt.Fprintf(&c.buf, "%.1f,%.1f ", xs[i], lower[i])
+	}
+	fmt.Fprintf(&c.buf, `" %s/>`+"\n", svgPaint("fill", col))
+}
+
+func (c *svgCanvas) rect(x, y, width, height float64, col color.RGBA) {
+	fmt.Fprintf(&c.buf, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" %s/>`+"\n", x, y, width, height,
+		svgPaint("fill", col))
+}
+
+func (c *svgCanvas) text(x, y float64, s string, anchor int, col color.RGBA) {
+	fmt.Fprintf(&c.buf, `<text x="%.1f" y="%.1f" text-anchor="%s" %s>`, x, y, svgAnchors[anchor],
+		svgPaint("fill", col))
+	xml.EscapeText(&c.buf, []byte(s))
+	c.buf.WriteString("</text>\n")
+}
+
+func (c *svgCanvas) encode(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" `+
+		`viewBox="0 0 %d %d" font-family="monospace" font-size="12" xml:space="preserve">`+"\n", c.width,
+		c.height, c.width, c.height); err != nil {
+		return err
+	}
+
+	if _, err := c.buf.WriteTo(w); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "</svg>\n")
+	return err
+}
+
+func svgPaint(attr string, c color.RGBA) string {
+	result := fmt.Sprintf(`%s="#%02x%02x%02x"`, attr, c.R, c.G, c.B)
+	if c.A != 0xff {
+		result += fmt.Sprintf(` %s-opacity="%.2f"`, attr, float64(c.A)/0xff)
+	}
+
+	return result
+}
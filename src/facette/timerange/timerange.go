@@ -12,6 +12,7 @@ import (
 const durationRegexp = "^([-+])?\\s*" +
 	"(?:(\\d+)\\s*y(?:ears?)?)?\\s*" +
 	"(?:(\\d+)\\s*mo(?:nths?)?)?\\s*" +
+	"(?:(\\d+)\\s*w(?:eeks?)?)?\\s*" +
 	"(?:(\\d+)\\s*d(?:ays?)?)?\\s*" +
 	"(?:(\\d+)\\s*h(?:ours?)?)?\\s*" +
 	"(?:(\\d+)\\s*m(?:inutes?)?)?\\s*" +
@@ -84,8 +85,8 @@ func Apply(t time.Time, input string) (time.Time, error) {
 		return t, ErrInvalidRange
 	}
 
-	return t.AddDate(parts[0], parts[1], parts[2]).
-		Add(time.Duration(parts[3]) * time.Hour).
-		Add(time.Duration(parts[4]) * time.Minute).
-		Add(time.Duration(parts[5]) * time.Second), nil
+	return t.AddDate(parts[0], parts[1], parts[2]*7+parts[3]).
+		Add(time.Duration(parts[4]) * time.Hour).
+		Add(time.Duration(parts[5]) * time.Minute).
+		Add(time.Duration(parts[6]) * time.Second), nil
 }
@@ -16,6 +16,8 @@ func Test_Apply(t *testing.T) {
 		{"2mo", ref.AddDate(0, 2, 0)},
 		{"-1y 3h 126s", ref.AddDate(-1, 0, 0).Add(-3*time.Hour - 126*time.Second)},
 		{"3d 1h 6m", ref.AddDate(0, 0, 3).Add(time.Hour + 6*time.Minute)},
+		{"-1w", ref.AddDate(0, 0, -7)},
+		{"2w 3d", ref.AddDate(0, 0, 17)},
 	}
 
 	for _, entry := range tests {